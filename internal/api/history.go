@@ -0,0 +1,106 @@
+package api
+
+import (
+	"sync"
+	"time"
+
+	"github.com/Primexz/lndnotify/internal/events"
+	"github.com/Primexz/lndnotify/internal/notify"
+)
+
+// HistoryEntry is a processed event with the delivery result of its notification per provider
+type HistoryEntry struct {
+	ID         uint64              `json:"id"`
+	EventType  events.EventType    `json:"event_type"`
+	Node       string              `json:"node"`
+	Timestamp  time.Time           `json:"timestamp"`
+	Severity   events.Severity     `json:"severity"`
+	Message    string              `json:"message"`
+	Deliveries map[string]Delivery `json:"deliveries"`
+}
+
+// Delivery is the last reported delivery state of a notification at a provider
+type Delivery struct {
+	Status notify.DeliveryStatus `json:"status"`
+	Error  string                `json:"error,omitempty"`
+	Time   time.Time             `json:"time"`
+}
+
+// History is a ring buffer of the last processed events
+type History struct {
+	entries []HistoryEntry
+	lastID  uint64
+	mu      sync.Mutex
+}
+
+// NewHistory creates a history keeping the given number of events
+func NewHistory(size int) *History {
+	return &History{entries: make([]HistoryEntry, size)}
+}
+
+// Add adds an event with its rendered message and returns its ID. The oldest event is dropped
+// if the history is full.
+func (h *History) Add(event events.Event, message string) uint64 {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if len(h.entries) == 0 {
+		return 0
+	}
+
+	h.lastID++
+	h.entries[h.index(h.lastID)] = HistoryEntry{
+		ID:         h.lastID,
+		EventType:  event.Type(),
+		Node:       event.Source().Name,
+		Timestamp:  event.Timestamp(),
+		Severity:   event.Severity(),
+		Message:    message,
+		Deliveries: make(map[string]Delivery),
+	}
+	return h.lastID
+}
+
+// Record stores the delivery result of events at a provider. Events that were already dropped
+// from the history are ignored. It implements notify.DeliveryFunc.
+func (h *History) Record(ids []uint64, provider string, status notify.DeliveryStatus, err error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	delivery := Delivery{Status: status, Time: time.Now()}
+	if err != nil {
+		delivery.Error = err.Error()
+	}
+	for _, id := range ids {
+		if id == 0 || len(h.entries) == 0 {
+			continue
+		}
+		entry := &h.entries[h.index(id)]
+		if entry.ID == id {
+			entry.Deliveries[provider] = delivery
+		}
+	}
+}
+
+// Entries returns the events in the history, newest first
+func (h *History) Entries() []HistoryEntry {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	entries := make([]HistoryEntry, 0, len(h.entries))
+	for id := h.lastID; id > 0 && len(entries) < len(h.entries); id-- {
+		entry := h.entries[h.index(id)]
+		deliveries := make(map[string]Delivery, len(entry.Deliveries))
+		for provider, delivery := range entry.Deliveries {
+			deliveries[provider] = delivery
+		}
+		entry.Deliveries = deliveries
+		entries = append(entries, entry)
+	}
+	return entries
+}
+
+// index returns the position of an event in the ring buffer
+func (h *History) index(id uint64) int {
+	return int((id - 1) % uint64(len(h.entries)))
+}
@@ -0,0 +1,66 @@
+package api
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/Primexz/lndnotify/internal/events"
+	"github.com/Primexz/lndnotify/internal/notify"
+)
+
+func TestHistory(t *testing.T) {
+	history := NewHistory(2)
+
+	event := events.NewAliasChangedEvent("02abc", "old", "new")
+	event.SetSource(events.NodeInfo{Name: "alice"})
+	first := history.Add(event, "first")
+	second := history.Add(event, "second")
+
+	history.Record([]uint64{first, second}, "telegram", notify.DeliverySent, nil)
+	history.Record([]uint64{second}, "webhook", notify.DeliveryFailed, errors.New("timeout"))
+
+	third := history.Add(event, "third")
+	history.Record([]uint64{first}, "webhook", notify.DeliverySent, nil)
+
+	entries := history.Entries()
+	if len(entries) != 2 {
+		t.Fatalf("Entries() returned %d entries; want 2", len(entries))
+	}
+	if entries[0].ID != third || entries[0].Message != "third" || entries[1].ID != second {
+		t.Errorf("Entries() = %+v; want newest first", entries)
+	}
+	if entries[1].EventType != events.Event_ALIAS_CHANGED || entries[1].Node != "alice" {
+		t.Errorf("entry = %+v; want alias changed event of alice", entries[1])
+	}
+	if len(entries[0].Deliveries) != 0 {
+		t.Errorf("deliveries of overwritten entry = %+v; want none", entries[0].Deliveries)
+	}
+
+	deliveries := entries[1].Deliveries
+	if d := deliveries["telegram"]; d.Status != notify.DeliverySent || d.Error != "" {
+		t.Errorf("telegram delivery = %+v; want sent", d)
+	}
+	if d := deliveries["webhook"]; d.Status != notify.DeliveryFailed || d.Error != "timeout" || time.Since(d.Time) > time.Minute {
+		t.Errorf("webhook delivery = %+v; want failed with timeout", d)
+	}
+
+	// Entries are copies
+	entries[1].Deliveries["telegram"] = Delivery{Status: notify.DeliveryFailed}
+	if d := history.Entries()[1].Deliveries["telegram"]; d.Status != notify.DeliverySent {
+		t.Errorf("telegram delivery = %+v after modifying a copy; want sent", d)
+	}
+}
+
+func TestHistoryDisabled(t *testing.T) {
+	history := NewHistory(0)
+
+	event := events.NewAliasChangedEvent("02abc", "old", "new")
+	if id := history.Add(event, "message"); id != 0 {
+		t.Errorf("Add() = %d; want 0", id)
+	}
+	history.Record([]uint64{1}, "telegram", notify.DeliverySent, nil)
+	if entries := history.Entries(); len(entries) != 0 {
+		t.Errorf("Entries() = %+v; want none", entries)
+	}
+}
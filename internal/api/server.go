@@ -0,0 +1,211 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"sync/atomic"
+	"time"
+
+	"github.com/Primexz/lndnotify/internal/config"
+	"github.com/Primexz/lndnotify/internal/events"
+	"github.com/Primexz/lndnotify/internal/lnd"
+	"github.com/lightningnetwork/lnd/lnrpc"
+	log "github.com/sirupsen/logrus"
+	"gopkg.in/yaml.v3"
+)
+
+// Node is an LND node whose state is served by the API
+type Node interface {
+	Node() events.NodeInfo
+	IsConnected() bool
+	Status() lnd.NodeStatus
+	Subscriptions() map[string]error
+	Channels() []*lnrpc.Channel
+}
+
+// NodeHealth is the health of a node. A node is healthy if it is connected, LND responds and
+// all subscriptions are established.
+type NodeHealth struct {
+	Name          string            `json:"name"`
+	Alias         string            `json:"alias"`
+	Healthy       bool              `json:"healthy"`
+	Connected     bool              `json:"connected"`
+	LndHealthy    bool              `json:"lnd_healthy"`
+	SyncedToChain bool              `json:"synced_to_chain"`
+	Subscriptions map[string]string `json:"subscriptions"`
+}
+
+// NodeChannels holds the open channels of a node as cached by its channel manager
+type NodeChannels struct {
+	Node     string    `json:"node"`
+	Channels []Channel `json:"channels"`
+}
+
+// Channel is an open channel
+type Channel struct {
+	ChanId        uint64 `json:"chan_id"`
+	ChannelPoint  string `json:"channel_point"`
+	RemotePubkey  string `json:"remote_pubkey"`
+	PeerAlias     string `json:"peer_alias"`
+	Active        bool   `json:"active"`
+	Private       bool   `json:"private"`
+	Initiator     bool   `json:"initiator"`
+	Capacity      int64  `json:"capacity"`
+	LocalBalance  int64  `json:"local_balance"`
+	RemoteBalance int64  `json:"remote_balance"`
+	PendingHtlcs  int    `json:"pending_htlcs"`
+}
+
+// Server serves the status API over HTTP
+type Server struct {
+	srv     *http.Server
+	nodes   []Node
+	history *History
+
+	// cfg is the current config, replaced on reload
+	cfg atomic.Pointer[config.Config]
+}
+
+// NewServer creates a status API server listening on the given address
+func NewServer(listenAddress string, cfg *config.Config, nodes []Node, history *History) *Server {
+	s := &Server{
+		nodes:   nodes,
+		history: history,
+	}
+	s.cfg.Store(cfg)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /healthz", s.handleHealthz)
+	mux.HandleFunc("GET /api/events", s.handleEvents)
+	mux.HandleFunc("GET /api/channels", s.handleChannels)
+	mux.HandleFunc("GET /api/config", s.handleConfig)
+
+	s.srv = &http.Server{
+		Addr:              listenAddress,
+		Handler:           mux,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	return s
+}
+
+// UpdateConfig replaces the config served by /api/config
+func (s *Server) UpdateConfig(cfg *config.Config) {
+	s.cfg.Store(cfg)
+}
+
+// Start starts serving the API in the background
+func (s *Server) Start() {
+	go func() {
+		log.WithField("address", s.srv.Addr).Info("starting api server")
+		if err := s.srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			log.WithError(err).Error("api server failed")
+		}
+	}()
+}
+
+// Stop gracefully shuts down the API server
+func (s *Server) Stop() {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if err := s.srv.Shutdown(ctx); err != nil {
+		log.WithError(err).Error("error shutting down api server")
+	}
+}
+
+// handleHealthz reports the health of every node, with status 503 if any node is unhealthy
+func (s *Server) handleHealthz(w http.ResponseWriter, r *http.Request) {
+	healthy := true
+	nodes := make([]NodeHealth, 0, len(s.nodes))
+	for _, node := range s.nodes {
+		info := node.Node()
+		status := node.Status()
+		health := NodeHealth{
+			Name:          info.Name,
+			Alias:         info.Alias,
+			Connected:     node.IsConnected(),
+			LndHealthy:    status.Healthy,
+			SyncedToChain: status.SyncedToChain,
+			Subscriptions: make(map[string]string),
+		}
+		health.Healthy = health.Connected && health.LndHealthy
+		for name, err := range node.Subscriptions() {
+			if err != nil {
+				health.Subscriptions[name] = err.Error()
+				health.Healthy = false
+			} else {
+				health.Subscriptions[name] = "ok"
+			}
+		}
+
+		healthy = healthy && health.Healthy
+		nodes = append(nodes, health)
+	}
+
+	code := http.StatusOK
+	if !healthy {
+		code = http.StatusServiceUnavailable
+	}
+	writeJSON(w, code, map[string]interface{}{
+		"healthy": healthy,
+		"nodes":   nodes,
+	})
+}
+
+// handleEvents returns the recent events, newest first
+func (s *Server) handleEvents(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, s.history.Entries())
+}
+
+// handleChannels returns the open channels of every node
+func (s *Server) handleChannels(w http.ResponseWriter, r *http.Request) {
+	nodes := make([]NodeChannels, 0, len(s.nodes))
+	for _, node := range s.nodes {
+		channels := make([]Channel, 0)
+		for _, ch := range node.Channels() {
+			channels = append(channels, Channel{
+				ChanId:        ch.GetChanId(),
+				ChannelPoint:  ch.GetChannelPoint(),
+				RemotePubkey:  ch.GetRemotePubkey(),
+				PeerAlias:     ch.GetPeerAlias(),
+				Active:        ch.GetActive(),
+				Private:       ch.GetPrivate(),
+				Initiator:     ch.GetInitiator(),
+				Capacity:      ch.GetCapacity(),
+				LocalBalance:  ch.GetLocalBalance(),
+				RemoteBalance: ch.GetRemoteBalance(),
+				PendingHtlcs:  len(ch.GetPendingHtlcs()),
+			})
+		}
+		nodes = append(nodes, NodeChannels{Node: node.Node().Name, Channels: channels})
+	}
+	writeJSON(w, http.StatusOK, nodes)
+}
+
+// handleConfig returns the current config with secrets redacted, keyed like the config file
+func (s *Server) handleConfig(w http.ResponseWriter, r *http.Request) {
+	cfg := s.cfg.Load().Redacted()
+
+	// The config only has YAML tags, so it is converted via YAML to keep the config file keys
+	data, err := yaml.Marshal(&cfg)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	var value map[string]interface{}
+	if err := yaml.Unmarshal(data, &value); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	writeJSON(w, http.StatusOK, value)
+}
+
+func writeJSON(w http.ResponseWriter, code int, value interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	if err := json.NewEncoder(w).Encode(value); err != nil {
+		log.WithError(err).Warn("error writing api response")
+	}
+}
@@ -4,12 +4,23 @@ import (
 	"fmt"
 	"os"
 	"os/signal"
+	"path/filepath"
 	"syscall"
+	"time"
 
+	"github.com/Primexz/lndnotify/internal/api"
+	"github.com/Primexz/lndnotify/internal/backup"
+	"github.com/Primexz/lndnotify/internal/commands"
 	"github.com/Primexz/lndnotify/internal/config"
 	"github.com/Primexz/lndnotify/internal/events"
+	"github.com/Primexz/lndnotify/internal/fiat"
+	"github.com/Primexz/lndnotify/internal/filter"
 	"github.com/Primexz/lndnotify/internal/lnd"
+	"github.com/Primexz/lndnotify/internal/metrics"
 	"github.com/Primexz/lndnotify/internal/notify"
+	"github.com/Primexz/lndnotify/internal/state"
+	"github.com/Primexz/lndnotify/internal/throttle"
+	"github.com/prometheus/client_golang/prometheus"
 	log "github.com/sirupsen/logrus"
 )
 
@@ -28,80 +39,359 @@ func Run(configPath string, version string) {
 	}
 	log.SetLevel(level)
 
-	// Create LND client
-	lndClient := lnd.NewClient(cfg)
+	rules, err := filter.NewRules(cfg.Filters, filter.MinAmountExpressions(cfg.EventConfig))
+	if err != nil {
+		log.WithError(err).Fatal("invalid filters")
+	}
+
+	// Create one LND client per node
+	var clients []*lnd.Client
+	for _, node := range cfg.Nodes {
+		client, err := newNodeClient(cfg, node)
+		if err != nil {
+			log.WithField("node", node.Name).WithError(err).Fatal("failed to create LND client")
+		}
+		clients = append(clients, client)
+	}
+	defer disconnectAll(clients)
+
+	// Serve Prometheus metrics
+	if cfg.Metrics.ListenAddress != "" {
+		for _, client := range clients {
+			prometheus.WrapRegistererWith(prometheus.Labels{"node": client.Node().Name}, prometheus.DefaultRegisterer).
+				MustRegister(client.Collector())
+		}
 
-	// Connect to LND
-	if err := lndClient.Connect(); err != nil {
-		log.Fatalf("failed to connect to LND: %v", err)
+		metricsServer := metrics.NewServer(cfg.Metrics.ListenAddress)
+		metricsServer.Start()
+		defer metricsServer.Stop()
 	}
-	defer func() {
-		if err := lndClient.Disconnect(); err != nil {
-			log.WithError(err).Error("error disconnecting from LND")
+
+	// Answer chat commands
+	if cfg.Commands.Enabled {
+		commandService, err := newCommandService(cfg, clients)
+		if err != nil {
+			log.WithError(err).Fatal("failed to create command service")
 		}
-	}()
+		commandService.Start()
+		defer commandService.Stop()
+	}
+
+	// Convert sat amounts to fiat in the templates
+	var fiat events.FiatConverter
+	if cfg.Fiat.Currency != "" {
+		converter := newFiatConverter(cfg.Fiat)
+		converter.Start()
+		defer converter.Stop()
+		fiat = converter
+	}
+
+	// Keep the last events and their delivery results for the status API
+	var history *api.History
+	var onDelivery notify.DeliveryFunc
+	if cfg.API.ListenAddress != "" {
+		history = api.NewHistory(cfg.API.HistorySize)
+		onDelivery = history.Record
+	}
 
 	// Create notification manager
 	notifier := notify.NewManager(&notify.ManagerConfig{
-		Providers: cfg.Notifications.Providers,
-		Templates: cfg.Notifications.Templates,
-		Batching:  cfg.Notifications.Batching,
+		Providers:  cfg.Notifications.Providers,
+		Templates:  cfg.Notifications.Templates,
+		Batching:   cfg.Notifications.Batching,
+		Outbox:     cfg.Notifications.Outbox,
+		Locale:     cfg.Notifications.Formatting.Locale.Tag,
+		OnDelivery: onDelivery,
 	})
 
-	// Subscribe to events
-	eventChan, err := lndClient.SubscribeEvents()
-	if err != nil {
-		log.Fatalf("failed to subscribe to events: %v", err)
+	// Serve the status API
+	var apiServer *api.Server
+	if cfg.API.ListenAddress != "" {
+		nodes := make([]api.Node, 0, len(clients))
+		for _, client := range clients {
+			nodes = append(nodes, client)
+		}
+		apiServer = api.NewServer(cfg.API.ListenAddress, cfg, nodes, history)
+		apiServer.Start()
+		defer apiServer.Stop()
+	}
+
+	// Publish events and node status via MQTT
+	var publisher *notify.MQTTPublisher
+	if cfg.MQTT.Broker != "" {
+		publisher, err = notify.NewMQTTPublisher(cfg.MQTT)
+		if err != nil {
+			log.WithError(err).Fatal("failed to create mqtt publisher")
+		}
+	}
+
+	// Archive channel backups, independent of the notifications
+	var archiver *backup.Archiver
+	if cfg.Backup.Local.Directory != "" || cfg.Backup.S3.Endpoint != "" {
+		archiver, err = backup.NewArchiver(cfg.Backup)
+		if err != nil {
+			log.WithError(err).Fatal("failed to create backup archiver")
+		}
+	}
+
+	// Suppress duplicate events and limit the event rate
+	var throttler *throttle.Throttle
+	if cfg.Throttle.Enabled {
+		throttler, err = throttle.New(cfg.Throttle, func(eventType events.EventType, message string) {
+			notifier.SendNotification(notify.Notification{
+				EventType: eventType,
+				Severity:  events.SeverityInfo,
+				Timestamp: time.Now(),
+				Message:   message,
+			}, false)
+		})
+		if err != nil {
+			log.WithError(err).Fatal("invalid throttle config")
+		}
+		throttler.Start()
 	}
 
+	// Subscribe to the events of all nodes
+	done := make(chan struct{})
+	defer close(done)
+
+	eventChan := make(chan events.Event, 100)
+	for _, client := range clients {
+		nodeEvents, err := client.SubscribeEvents()
+		if err != nil {
+			log.Fatalf("failed to subscribe to events: %v", err)
+		}
+		go forwardEvents(client, nodeEvents, eventChan, done)
+		if publisher != nil {
+			go publishNodeStatus(client, publisher, done)
+		}
+	}
+
+	// Event flags and settings of each node, replaced on reload
+	nodeConfigs := nodeConfigMap(cfg)
+
 	if cfg.Events.StatusEvents {
-		notifier.SendNotification(fmt.Sprintf("🟢 lndnotify v%s connected", version), true)
-		defer notifier.SendNotification(fmt.Sprintf("🔴 lndnotify v%s disconnected", version), true)
+		notifier.SendNotification(notify.Notification{Message: fmt.Sprintf("🟢 lndnotify v%s connected", version)}, true)
+		defer notifier.SendNotification(notify.Notification{Message: fmt.Sprintf("🔴 lndnotify v%s disconnected", version)}, true)
 	}
 
 	// Handle shutdown gracefully
 	sigChan := make(chan os.Signal, 1)
 	signal.Notify(sigChan, syscall.SIGINT, syscall.SIGTERM)
 
+	// Reload the config on SIGHUP and, if enabled, on file changes
+	reloadChan := make(chan os.Signal, 1)
+	signal.Notify(reloadChan, syscall.SIGHUP)
+	if cfg.WatchConfig {
+		go watchConfig(configPath, reloadChan, done)
+	}
+
 	log.Info("started lndnotify")
 
 	// Main event loop
 	for {
 		select {
 		case event := <-eventChan:
-			logger := log.WithField("event", event.Type())
+			logger := log.WithFields(log.Fields{
+				"event": event.Type(),
+				"node":  event.Source().Name,
+			})
 
 			logger.Debug("received event")
+			metrics.EventsReceived.WithLabelValues(event.Type().String(), event.Source().Name).Inc()
+
+			if backupEvent, ok := event.(*events.BackupMultiEvent); ok && archiver != nil {
+				archiver.Archive(event.Source().Name, event.Timestamp(), backupEvent.Backup.GetMultiChanBackup())
+			}
 
-			if !event.ShouldProcess(cfg) {
+			nodeCfg := nodeConfigs[event.Source().Name]
+			if !event.ShouldProcess(nodeCfg) {
 				logger.Debug("event filtered, skipping")
+				metrics.EventsFiltered.WithLabelValues(event.Type().String(), event.Source().Name).Inc()
 				continue
 			}
 
-			msg, err := notifier.RenderTemplate(event.Type().String(), event.GetTemplateData(cfg.Notifications.Formatting.Locale.Tag))
+			// The template data is built once and used for the filter rules, the message and
+			// webhooks. Rules see the values of formatted numbers, so they don't depend on the locale.
+			data := event.GetTemplateData(nodeCfg, cfg.Notifications.Formatting.Locale.Tag, fiat)
+			env := filter.NewEnv(data)
+			env["Severity"] = event.Severity().String()
+			if !rules.Match(event.Type(), env) {
+				logger.Debug("event filtered by rule, skipping")
+				metrics.EventsFiltered.WithLabelValues(event.Type().String(), event.Source().Name).Inc()
+				continue
+			}
+
+			if throttler != nil && !throttler.Allow(event.Type(), env) {
+				logger.Debug("event suppressed by throttle, skipping")
+				metrics.EventsSuppressed.WithLabelValues(event.Type().String(), event.Source().Name).Inc()
+				continue
+			}
+
+			msg, err := notifier.RenderTemplate(event.Type().String(), data)
 			if err != nil {
 				logger.WithError(err).Error("error rendering template")
+				metrics.EventsRenderFailed.WithLabelValues(event.Type().String(), event.Source().Name).Inc()
 				continue
 			}
+			metrics.EventsRendered.WithLabelValues(event.Type().String(), event.Source().Name).Inc()
 
+			notification := notify.Notification{
+				EventType: event.Type(),
+				Severity:  event.Severity(),
+				Node:      event.Source(),
+				Timestamp: event.Timestamp(),
+				Data:      data,
+				Env:       env,
+				Message:   msg,
+			}
 			if source, ok := event.(events.FileSource); ok {
-				notifier.SendNotificationWithFile(msg, source.GetFile())
+				notification.File = source.GetFile(nodeCfg)
+			}
+			if history != nil {
+				notification.ID = history.Add(event, msg)
+			}
+			notifier.SendNotification(notification, false)
+			if publisher != nil {
+				publisher.PublishEvent(notification)
+			}
+
+		case <-reloadChan:
+			log.Info("reloading config")
+
+			newCfg, newRules, err := reloadConfig(configPath, cfg, notifier, clients)
+			if err != nil {
+				log.WithError(err).Error("failed to reload config, keeping the current config")
 				continue
 			}
-			notifier.SendNotification(msg, false)
+			cfg = newCfg
+			rules = newRules
+			nodeConfigs = nodeConfigMap(cfg)
+			if apiServer != nil {
+				apiServer.UpdateConfig(cfg)
+			}
+			log.Info("config reloaded")
 
 		case <-sigChan:
 			log.Info("received shutdown signal")
 
-			// Stop the notification manager first to flush any pending batches
-			notifier.Stop()
+			// Send the summaries of suppressed events before the notification manager is stopped
+			if throttler != nil {
+				throttler.Stop()
+			}
 
-			if err := lndClient.Disconnect(); err != nil {
-				log.WithError(err).Error("error disconnecting from LND")
+			// Stop the notification manager before disconnecting to flush any pending batches
+			notifier.Stop()
+			if publisher != nil {
+				publisher.Stop()
+			}
+			if archiver != nil {
+				archiver.Stop()
 			}
 
+			disconnectAll(clients)
+
 			log.Info("shutdown complete")
 			return
 		}
 	}
 }
+
+// nodeConfigMap returns the config of every node by node name
+func nodeConfigMap(cfg *config.Config) map[string]*config.Config {
+	configs := make(map[string]*config.Config, len(cfg.Nodes))
+	for _, node := range cfg.Nodes {
+		configs[node.Name] = cfg.ForNode(node)
+	}
+	return configs
+}
+
+// newFiatConverter creates the fiat converter with the configured price source
+func newFiatConverter(cfg config.FiatConfig) *fiat.Converter {
+	var source fiat.PriceSource
+	switch cfg.Source {
+	case "fixed":
+		source = fiat.FixedSource{Rate: cfg.Rate}
+	default:
+		source = fiat.NewMempoolSource(cfg.URL)
+	}
+	return fiat.NewConverter(source, cfg.Currency, cfg.RefreshInterval)
+}
+
+// newNodeClient connects to the given node. Each named node keeps its handler state in a
+// subdirectory of the state directory.
+func newNodeClient(cfg *config.Config, node config.NodeConfig) (*lnd.Client, error) {
+	stateDir := cfg.StateDir
+	if stateDir != "" && node.Name != "" {
+		stateDir = filepath.Join(stateDir, node.Name)
+	}
+
+	store, err := state.NewStore(stateDir)
+	if err != nil {
+		return nil, fmt.Errorf("opening state store: %w", err)
+	}
+
+	client := lnd.NewClient(node.Name, cfg.ForNode(node), store)
+	if err := client.Connect(); err != nil {
+		return nil, fmt.Errorf("connecting to LND: %w", err)
+	}
+	return client, nil
+}
+
+// forwardEvents tags the events of a node with its name and alias and passes them to the main loop
+func forwardEvents(client *lnd.Client, in <-chan events.Event, out chan<- events.Event, done <-chan struct{}) {
+	for {
+		select {
+		case <-done:
+			return
+		case event := <-in:
+			event.SetSource(client.Node())
+
+			select {
+			case out <- event:
+			case <-done:
+				return
+			}
+		}
+	}
+}
+
+// publishNodeStatus publishes the status of a node to the retained MQTT state topics on every change
+func publishNodeStatus(client *lnd.Client, publisher *notify.MQTTPublisher, done <-chan struct{}) {
+	for {
+		select {
+		case <-done:
+			return
+		case <-client.StatusChanges():
+			node := client.Node().Name
+			status := client.Status()
+			publisher.PublishState(node, "healthy", status.Healthy)
+			publisher.PublishState(node, "synced_to_chain", status.SyncedToChain)
+			publisher.PublishState(node, "active_channels", status.ActiveChannels)
+			publisher.PublishState(node, "inactive_channels", status.InactiveChannels)
+		}
+	}
+}
+
+// newCommandService creates the command service on the chat of the configured provider
+func newCommandService(cfg *config.Config, clients []*lnd.Client) (*commands.Service, error) {
+	provider, _ := cfg.Notifications.Provider(cfg.Commands.Provider)
+	transport, err := commands.NewTransport(cfg.Commands, provider.URL)
+	if err != nil {
+		return nil, err
+	}
+
+	nodes := make([]commands.Node, 0, len(clients))
+	for _, client := range clients {
+		nodes = append(nodes, client)
+	}
+	return commands.NewService(transport, nodes, cfg.Notifications.Formatting.Locale.Tag), nil
+}
+
+func disconnectAll(clients []*lnd.Client) {
+	for _, client := range clients {
+		if err := client.Disconnect(); err != nil {
+			log.WithField("node", client.Node().Name).WithError(err).Error("error disconnecting from LND")
+		}
+	}
+}
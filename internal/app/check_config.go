@@ -0,0 +1,55 @@
+package app
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/Primexz/lndnotify/internal/config"
+	"github.com/Primexz/lndnotify/internal/filter"
+	"github.com/Primexz/lndnotify/internal/notify"
+	"github.com/Primexz/lndnotify/internal/throttle"
+)
+
+// CheckConfig validates the config file and renders every template with sample data. The
+// rendered templates and all errors are printed. It returns false if anything is invalid.
+func CheckConfig(configPath string) bool {
+	cfg, err := config.LoadConfig(configPath)
+	if err != nil {
+		fmt.Printf("❌ config: %v\n", err)
+		return false
+	}
+
+	ok := true
+	if _, err := filter.NewRules(cfg.Filters, filter.MinAmountExpressions(cfg.EventConfig)); err != nil {
+		fmt.Printf("❌ filters: %v\n", err)
+		ok = false
+	}
+	if _, err := throttle.New(cfg.Throttle, nil); err != nil {
+		fmt.Printf("❌ throttle: %v\n", err)
+		ok = false
+	}
+	if err := notify.CheckProviders(cfg.Notifications.Providers); err != nil {
+		fmt.Printf("❌ providers: %v\n", err)
+		ok = false
+	}
+
+	for _, check := range notify.CheckTemplates(cfg.Notifications.Templates, cfg.Notifications.Formatting.Locale.Tag) {
+		if check.Err != nil {
+			fmt.Printf("❌ %s: %v\n\n", check.EventType, check.Err)
+			ok = false
+			continue
+		}
+		fmt.Printf("✅ %s:\n%s\n\n", check.EventType, indent(check.Output))
+	}
+
+	if !ok {
+		fmt.Println("config is invalid")
+		return false
+	}
+	fmt.Println("config is valid")
+	return true
+}
+
+func indent(s string) string {
+	return "    " + strings.ReplaceAll(s, "\n", "\n    ")
+}
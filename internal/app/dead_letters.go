@@ -0,0 +1,33 @@
+package app
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/Primexz/lndnotify/internal/config"
+	"github.com/Primexz/lndnotify/internal/notify"
+	log "github.com/sirupsen/logrus"
+)
+
+// ListDeadLetters prints all notifications of the outbox that could not be delivered
+func ListDeadLetters(configPath string) {
+	cfg, err := config.LoadConfig(configPath)
+	if err != nil {
+		log.WithError(err).Fatal("failed to load config file")
+	}
+
+	entries, err := notify.ListDeadLetters(cfg.Notifications.Outbox)
+	if err != nil {
+		log.WithError(err).Fatal("failed to list dead letters")
+	}
+
+	if len(entries) == 0 {
+		fmt.Println("no undelivered notifications")
+		return
+	}
+
+	for _, entry := range entries {
+		fmt.Printf("%s | %s | provider=%s | attempts=%d | error=%s\n%s\n\n",
+			entry.ID, entry.CreatedAt.Format(time.RFC3339), entry.Provider, entry.Attempts, entry.LastError, entry.Message)
+	}
+}
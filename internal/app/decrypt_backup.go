@@ -0,0 +1,100 @@
+package app
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"strings"
+
+	"filippo.io/age"
+	"github.com/Primexz/lndnotify/internal/backup"
+	"golang.org/x/term"
+)
+
+// DecryptBackup decrypts an encrypted channel backup attachment and writes the backup for
+// `lncli restorechanbackup --multi_file`. It is decrypted with the age identities of
+// identityFile, or with a passphrase read from the terminal if identityFile is empty. The
+// output file defaults to the input file without the .age suffix and is never overwritten.
+func DecryptBackup(path, identityFile, output string) bool {
+	if output == "" {
+		output = strings.TrimSuffix(path, backup.EncryptedSuffix)
+		if output == path {
+			fmt.Printf("❌ %s has no %s suffix, set the output file with -output\n", path, backup.EncryptedSuffix)
+			return false
+		}
+	}
+
+	// #nosec G304 -- path is given on the command line
+	data, err := os.ReadFile(path)
+	if err != nil {
+		fmt.Printf("❌ %v\n", err)
+		return false
+	}
+
+	identities, err := backupIdentities(identityFile)
+	if err != nil {
+		fmt.Printf("❌ %v\n", err)
+		return false
+	}
+
+	plain, err := backup.Decrypt(data, identities...)
+	if err != nil {
+		fmt.Printf("❌ %v\n", err)
+		return false
+	}
+
+	// #nosec G304 -- output is given on the command line
+	f, err := os.OpenFile(output, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o600)
+	if err != nil {
+		fmt.Printf("❌ %v\n", err)
+		return false
+	}
+	if _, err := f.Write(plain); err != nil {
+		f.Close() // nolint:errcheck
+		fmt.Printf("❌ writing %s: %v\n", output, err)
+		return false
+	}
+	if err := f.Close(); err != nil {
+		fmt.Printf("❌ writing %s: %v\n", output, err)
+		return false
+	}
+
+	hash := sha256.Sum256(plain)
+	fmt.Printf("✅ decrypted backup written to %s\n", output)
+	fmt.Printf("   SHA256: %s\n", hex.EncodeToString(hash[:]))
+	fmt.Printf("   restore it with: lncli restorechanbackup --multi_file %s\n", output)
+	return true
+}
+
+// backupIdentities returns the age identities of an identity file, or a passphrase identity
+// read from the terminal if no file is given
+func backupIdentities(identityFile string) ([]age.Identity, error) {
+	if identityFile != "" {
+		// #nosec G304 -- identityFile is given on the command line
+		f, err := os.Open(identityFile)
+		if err != nil {
+			return nil, err
+		}
+		defer f.Close() // nolint:errcheck
+
+		identities, err := age.ParseIdentities(f)
+		if err != nil {
+			return nil, fmt.Errorf("reading identity file: %w", err)
+		}
+		return identities, nil
+	}
+
+	fmt.Fprint(os.Stderr, "Passphrase: ")
+	passphrase, err := term.ReadPassword(int(os.Stdin.Fd())) // nolint:gosec
+	fmt.Fprintln(os.Stderr)
+	if err != nil {
+		return nil, fmt.Errorf("reading passphrase: %w", err)
+	}
+
+	identity, err := age.NewScryptIdentity(string(passphrase))
+	if err != nil {
+		return nil, err
+	}
+	return []age.Identity{identity}, nil
+}
@@ -0,0 +1,142 @@
+package app
+
+import (
+	"fmt"
+	"os"
+	"reflect"
+	"syscall"
+	"time"
+
+	"github.com/Primexz/lndnotify/internal/config"
+	"github.com/Primexz/lndnotify/internal/filter"
+	"github.com/Primexz/lndnotify/internal/lnd"
+	"github.com/Primexz/lndnotify/internal/notify"
+	log "github.com/sirupsen/logrus"
+)
+
+// configWatchInterval is how often the config file is checked for changes
+const configWatchInterval = 5 * time.Second
+
+// reloadConfig loads and validates the config file and applies it to the running
+// notification manager and LND clients. The LND subscriptions keep running. If the new
+// config is invalid, nothing is applied and the error is returned.
+func reloadConfig(configPath string, current *config.Config, notifier *notify.Manager, clients []*lnd.Client) (*config.Config, filter.Rules, error) {
+	cfg, err := config.LoadConfig(configPath)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	level, err := log.ParseLevel(cfg.LogLevel)
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid log level: %w", err)
+	}
+
+	rules, err := filter.NewRules(cfg.Filters, filter.MinAmountExpressions(cfg.EventConfig))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// Templates are rendered with sample data, so errors that only show on execution are
+	// rejected as well and not first noticed when an event arrives
+	for _, check := range notify.CheckTemplates(cfg.Notifications.Templates, cfg.Notifications.Formatting.Locale.Tag) {
+		if check.Err != nil {
+			return nil, nil, fmt.Errorf("template %s: %w", check.EventType, check.Err)
+		}
+	}
+
+	// Nodes can't be added or removed without a restart
+	nodes := make(map[string]config.NodeConfig, len(cfg.Nodes))
+	for _, node := range cfg.Nodes {
+		nodes[node.Name] = node
+	}
+	if len(nodes) != len(clients) {
+		return nil, nil, fmt.Errorf("nodes can't be added or removed on reload")
+	}
+	for _, client := range clients {
+		if _, ok := nodes[client.Node().Name]; !ok {
+			return nil, nil, fmt.Errorf("node %q can't be removed on reload", client.Node().Name)
+		}
+	}
+
+	err = notifier.Reload(&notify.ManagerConfig{
+		Providers: cfg.Notifications.Providers,
+		Templates: cfg.Notifications.Templates,
+		Locale:    cfg.Notifications.Formatting.Locale.Tag,
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	for _, client := range clients {
+		client.UpdateConfig(cfg.ForNode(nodes[client.Node().Name]))
+	}
+	log.SetLevel(level)
+
+	warnRestartRequired(current, cfg)
+	return cfg, rules, nil
+}
+
+// warnRestartRequired logs the changed settings that are only applied on restart
+func warnRestartRequired(old, cfg *config.Config) {
+	connections := func(c *config.Config) []config.LNDConfig {
+		var conns []config.LNDConfig
+		for _, node := range c.Nodes {
+			conns = append(conns, node.LNDConfig)
+		}
+		return conns
+	}
+
+	settings := map[string][2]any{
+		"lnd":                    {connections(old), connections(cfg)},
+		"state_dir":              {old.StateDir, cfg.StateDir},
+		"metrics":                {old.Metrics, cfg.Metrics},
+		"mqtt":                   {old.MQTT, cfg.MQTT},
+		"api":                    {old.API, cfg.API},
+		"backup":                 {old.Backup, cfg.Backup},
+		"commands":               {old.Commands, cfg.Commands},
+		"notifications.batching": {old.Notifications.Batching, cfg.Notifications.Batching},
+		"notifications.outbox":   {old.Notifications.Outbox, cfg.Notifications.Outbox},
+		"throttle":               {old.Throttle, cfg.Throttle},
+		"fiat":                   {old.Fiat, cfg.Fiat},
+		"watch_config":           {old.WatchConfig, cfg.WatchConfig},
+	}
+	for name, values := range settings {
+		if !reflect.DeepEqual(values[0], values[1]) {
+			log.WithField("setting", name).Warn("config setting changed, restart required to apply it")
+		}
+	}
+}
+
+// watchConfig triggers a reload whenever the modification time of the config file changes
+func watchConfig(configPath string, reloadChan chan<- os.Signal, done <-chan struct{}) {
+	var lastMod time.Time
+	if info, err := os.Stat(configPath); err == nil {
+		lastMod = info.ModTime()
+	}
+
+	ticker := time.NewTicker(configWatchInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-done:
+			return
+		case <-ticker.C:
+			info, err := os.Stat(configPath)
+			if err != nil {
+				log.WithError(err).Warn("error checking config file for changes")
+				continue
+			}
+			if info.ModTime().Equal(lastMod) {
+				continue
+			}
+			lastMod = info.ModTime()
+
+			log.Info("config file changed")
+			select {
+			case reloadChan <- syscall.SIGHUP:
+			default:
+			}
+		}
+	}
+}
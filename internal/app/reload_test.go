@@ -0,0 +1,125 @@
+package app
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/Primexz/lndnotify/internal/config"
+	"github.com/Primexz/lndnotify/internal/events"
+	"github.com/Primexz/lndnotify/internal/lnd"
+	"github.com/Primexz/lndnotify/internal/notify"
+	"golang.org/x/text/language"
+)
+
+const reloadTestConfig = `
+lnd:
+  host: localhost
+  port: 10009
+  tls_cert_path: tls.cert
+  macaroon_path: admin.macaroon
+notifications:
+  providers:
+    - url: "generic://example.com"
+      name: main
+`
+
+func writeConfig(t *testing.T, path, extra string) {
+	t.Helper()
+	if err := os.WriteFile(path, []byte(reloadTestConfig+extra), 0o600); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestReloadConfig(t *testing.T) {
+	tests := []struct {
+		name    string
+		extra   string
+		wantErr string
+	}{
+		{
+			name: "valid",
+			extra: `  templates:
+    healthy_event: "new {{.NodeAlias}}"
+`,
+		},
+		{
+			name: "template parse error",
+			extra: `  templates:
+    healthy_event: "new {{.NodeAlias"
+`,
+			wantErr: "healthy_event",
+		},
+		{
+			name: "template execution error",
+			extra: `  templates:
+    healthy_event: "new {{.Missing.Field}}"
+`,
+			wantErr: "template healthy_event",
+		},
+		{
+			name: "node added",
+			extra: `nodes:
+  - name: second
+    host: localhost
+    port: 10010
+    tls_cert_path: tls.cert
+    macaroon_path: admin.macaroon
+  - name: third
+    host: localhost
+    port: 10011
+    tls_cert_path: tls.cert
+    macaroon_path: admin.macaroon
+`,
+			wantErr: "nodes can't be added or removed",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "config.yaml")
+			writeConfig(t, path, "  templates:\n    healthy_event: \"old\"\n")
+			current, err := config.LoadConfig(path)
+			if err != nil {
+				t.Fatalf("LoadConfig() error = %v", err)
+			}
+
+			notifier := notify.NewManager(&notify.ManagerConfig{
+				Providers: current.Notifications.Providers,
+				Templates: current.Notifications.Templates,
+				Locale:    current.Notifications.Formatting.Locale.Tag,
+			})
+			defer notifier.Stop()
+			clients := []*lnd.Client{lnd.NewClient("", current, nil)}
+
+			writeConfig(t, path, tt.extra)
+			cfg, _, err := reloadConfig(path, current, notifier, clients)
+
+			data := events.SampleTemplateData(events.Event_HEALTHY, language.English)
+			msg, renderErr := notifier.RenderTemplate(events.Event_HEALTHY.String(), data)
+			if renderErr != nil {
+				t.Fatalf("RenderTemplate() error = %v", renderErr)
+			}
+
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("reloadConfig() error = %v; want %q", err, tt.wantErr)
+				}
+				if cfg != nil {
+					t.Error("reloadConfig() returned a config for an invalid config")
+				}
+				if msg != "old" {
+					t.Errorf("template after rejected reload = %q; want old", msg)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("reloadConfig() error = %v", err)
+			}
+			if !strings.HasPrefix(msg, "new ") {
+				t.Errorf("template after reload = %q; want the new template", msg)
+			}
+		})
+	}
+}
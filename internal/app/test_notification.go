@@ -0,0 +1,74 @@
+package app
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/Primexz/lndnotify/internal/config"
+	"github.com/Primexz/lndnotify/internal/events"
+	"github.com/Primexz/lndnotify/internal/notify"
+)
+
+// SendTestNotification renders the template of an event type with sample data and sends it to
+// every provider, or only to the named provider, without connecting to LND. The result of
+// every provider is printed. It returns false if the notification could not be sent to one of them.
+func SendTestNotification(configPath, provider, event string) bool {
+	cfg, err := config.LoadConfig(configPath)
+	if err != nil {
+		fmt.Printf("❌ config: %v\n", err)
+		return false
+	}
+
+	// Accept event types with or without the "_event" suffix, e.g. "forward"
+	eventType, err := events.ParseEventType(event)
+	if err != nil {
+		if eventType, err = events.ParseEventType(event + "_event"); err != nil {
+			fmt.Printf("❌ %v\n", err)
+			return false
+		}
+	}
+
+	lang := cfg.Notifications.Formatting.Locale.Tag
+
+	var msg string
+	for _, check := range notify.CheckTemplates(cfg.Notifications.Templates, lang) {
+		if check.EventType != eventType {
+			continue
+		}
+		if check.Err != nil {
+			fmt.Printf("❌ %s: %v\n", eventType, check.Err)
+			return false
+		}
+		msg = check.Output
+	}
+
+	results, err := notify.SendTestNotification(cfg.Notifications.Providers, provider, notify.Notification{
+		EventType: eventType,
+		Severity:  events.SeverityInfo,
+		Timestamp: time.Now(),
+		Data:      events.SampleTemplateData(eventType, lang),
+		Message:   msg,
+	})
+	if err != nil {
+		fmt.Printf("❌ %v\n", err)
+		return false
+	}
+	if len(results) == 0 {
+		fmt.Println("❌ no providers configured")
+		return false
+	}
+
+	ok := true
+	for _, result := range results {
+		switch {
+		case result.Err != nil:
+			fmt.Printf("❌ %s: %v\n", result.Provider, result.Err)
+			ok = false
+		case result.Uploaded:
+			fmt.Printf("✅ %s: sent with test file\n", result.Provider)
+		default:
+			fmt.Printf("✅ %s: sent (file uploads not supported)\n", result.Provider)
+		}
+	}
+	return ok
+}
@@ -0,0 +1,219 @@
+package backup
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Primexz/lndnotify/internal/config"
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	filePrefix     = "channel_backup_"
+	fileSuffix     = ".backup"
+	fileTimeLayout = "20060102_150405"
+
+	archiveQueueSize = 10
+	storageTimeout   = 2 * time.Minute
+)
+
+// storage is a location channel backups are archived to. Backups are grouped by node, the
+// node of a config without a nodes list is empty.
+type storage interface {
+	// put stores a backup and verifies the stored copy against its hex encoded SHA-256 sum
+	put(ctx context.Context, node, name string, data []byte, sum string) error
+	// sum returns the hex encoded SHA-256 sum of a stored backup
+	sum(ctx context.Context, node, name string) (string, error)
+	// list returns the stored backups of a node
+	list(ctx context.Context, node string) ([]storedBackup, error)
+	remove(ctx context.Context, node, name string) error
+	String() string
+}
+
+// storedBackup is a backup file in a storage
+type storedBackup struct {
+	name string
+	time time.Time
+}
+
+// archiveJob is a backup waiting to be archived
+type archiveJob struct {
+	node      string
+	timestamp time.Time
+	data      []byte
+}
+
+// Archiver writes the channel backups of all nodes to the configured storages. Unchanged
+// backups are skipped and old backups are deleted after every new one.
+type Archiver struct {
+	cfg      config.BackupConfig
+	storages []storage
+
+	// last holds the sum of the last backup per storage and node, loaded from the storage
+	// on the first backup of a node
+	last map[storage]map[string]string
+
+	queue   chan archiveJob
+	stopped bool
+	queueMu sync.Mutex
+	wg      sync.WaitGroup
+}
+
+// NewArchiver creates an archiver for the configured storages and starts archiving in the background
+func NewArchiver(cfg config.BackupConfig) (*Archiver, error) {
+	a := &Archiver{
+		cfg:   cfg,
+		last:  make(map[storage]map[string]string),
+		queue: make(chan archiveJob, archiveQueueSize),
+	}
+
+	if cfg.Local.Directory != "" {
+		a.storages = append(a.storages, newLocalStorage(cfg.Local.Directory))
+	}
+	if cfg.S3.Endpoint != "" {
+		s3, err := newS3Storage(cfg.S3)
+		if err != nil {
+			return nil, err
+		}
+		a.storages = append(a.storages, s3)
+	}
+	for _, s := range a.storages {
+		a.last[s] = make(map[string]string)
+	}
+
+	a.wg.Add(1)
+	go a.archiveLoop()
+	return a, nil
+}
+
+// Archive adds a backup of a node to the archive queue without blocking. Backups are dropped
+// if the queue is full or the archiver is stopped.
+func (a *Archiver) Archive(node string, timestamp time.Time, data []byte) {
+	a.queueMu.Lock()
+	defer a.queueMu.Unlock()
+
+	if a.stopped {
+		return
+	}
+	select {
+	case a.queue <- archiveJob{node: node, timestamp: timestamp, data: data}:
+	default:
+		log.WithField("node", node).Warn("backup archive queue is full, dropping backup")
+	}
+}
+
+// Stop archives the queued backups and stops the archiver
+func (a *Archiver) Stop() {
+	a.queueMu.Lock()
+	a.stopped = true
+	close(a.queue)
+	a.queueMu.Unlock()
+	a.wg.Wait()
+}
+
+// archiveLoop archives the queued backups in order
+func (a *Archiver) archiveLoop() {
+	defer a.wg.Done()
+
+	for job := range a.queue {
+		a.archive(job)
+	}
+}
+
+// archive writes a backup to every storage
+func (a *Archiver) archive(job archiveJob) {
+	hash := sha256.Sum256(job.data)
+	sum := hex.EncodeToString(hash[:])
+	name := filePrefix + job.timestamp.Format(fileTimeLayout) + fileSuffix
+
+	for _, s := range a.storages {
+		logger := log.WithFields(log.Fields{
+			"storage":  s.String(),
+			"node":     job.node,
+			"filename": name,
+			"sha256":   sum,
+		})
+
+		ctx, cancel := context.WithTimeout(context.Background(), storageTimeout)
+		stored, err := a.store(ctx, s, job.node, name, job.data, sum)
+		if err != nil {
+			logger.WithError(err).Error("error archiving channel backup")
+		} else if stored {
+			logger.Info("archived channel backup")
+			if err := a.prune(ctx, s, job.node); err != nil {
+				logger.WithError(err).Warn("error deleting old channel backups")
+			}
+		} else {
+			logger.Debug("channel backup unchanged, skipping")
+		}
+		cancel()
+	}
+}
+
+// store writes a backup to a storage unless it equals the last backup of the node. It
+// reports whether the backup was written.
+func (a *Archiver) store(ctx context.Context, s storage, node, name string, data []byte, sum string) (bool, error) {
+	last, ok := a.last[s][node]
+	if !ok {
+		var err error
+		if last, err = latestSum(ctx, s, node); err != nil {
+			return false, fmt.Errorf("reading last backup: %w", err)
+		}
+	}
+	if last == sum {
+		a.last[s][node] = sum
+		return false, nil
+	}
+
+	if err := s.put(ctx, node, name, data, sum); err != nil {
+		return false, err
+	}
+	a.last[s][node] = sum
+	return true, nil
+}
+
+// prune deletes the backups of a node that are no longer retained
+func (a *Archiver) prune(ctx context.Context, s storage, node string) error {
+	backups, err := s.list(ctx, node)
+	if err != nil {
+		return err
+	}
+	for _, b := range expired(backups, *a.cfg.KeepLast, *a.cfg.KeepDaily) {
+		if err := s.remove(ctx, node, b.name); err != nil {
+			return err
+		}
+		log.WithFields(log.Fields{"storage": s.String(), "node": node, "filename": b.name}).Debug("deleted old channel backup")
+	}
+	return nil
+}
+
+// latestSum returns the sum of the most recent backup of a node, empty if there is none
+func latestSum(ctx context.Context, s storage, node string) (string, error) {
+	backups, err := s.list(ctx, node)
+	if err != nil || len(backups) == 0 {
+		return "", err
+	}
+
+	latest := backups[0]
+	for _, b := range backups[1:] {
+		if b.time.After(latest.time) {
+			latest = b
+		}
+	}
+	return s.sum(ctx, node, latest.name)
+}
+
+// parseFileName returns the time of a backup from its file name. It returns false if the file
+// was not created by the archiver.
+func parseFileName(name string) (time.Time, bool) {
+	if !strings.HasPrefix(name, filePrefix) || !strings.HasSuffix(name, fileSuffix) {
+		return time.Time{}, false
+	}
+	t, err := time.ParseInLocation(fileTimeLayout, strings.TrimSuffix(strings.TrimPrefix(name, filePrefix), fileSuffix), time.Local)
+	return t, err == nil
+}
@@ -0,0 +1,77 @@
+package backup
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/Primexz/lndnotify/internal/config"
+)
+
+// files returns the names of the files in a directory
+func files(t *testing.T, dir string) []string {
+	t.Helper()
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var names []string
+	for _, entry := range entries {
+		names = append(names, entry.Name())
+	}
+	sort.Strings(names)
+	return names
+}
+
+func TestArchiverLocal(t *testing.T) {
+	dir := t.TempDir()
+	keepLast, keepDaily := 2, 0
+	cfg := config.BackupConfig{
+		Local:     config.BackupLocalConfig{Directory: dir},
+		KeepLast:  &keepLast,
+		KeepDaily: &keepDaily,
+	}
+	start := time.Date(2026, 4, 26, 12, 0, 0, 0, time.Local)
+
+	archiver, err := NewArchiver(cfg)
+	if err != nil {
+		t.Fatalf("NewArchiver() error = %v", err)
+	}
+	archiver.Archive("alice", start, []byte("backup 1"))
+	archiver.Archive("alice", start.Add(time.Second), []byte("backup 1"))
+	archiver.Archive("alice", start.Add(2*time.Second), []byte("backup 2"))
+	archiver.Archive("bob", start, []byte("backup 1"))
+	archiver.Stop()
+
+	want := []string{"channel_backup_20260426_120000.backup", "channel_backup_20260426_120002.backup"}
+	if got := files(t, filepath.Join(dir, "alice")); len(got) != 2 || got[0] != want[0] || got[1] != want[1] {
+		t.Errorf("alice backups = %v; want %v", got, want)
+	}
+	if got := files(t, filepath.Join(dir, "bob")); len(got) != 1 {
+		t.Errorf("bob backups = %v; want one backup", got)
+	}
+	data, err := os.ReadFile(filepath.Join(dir, "alice", want[1]))
+	if err != nil || string(data) != "backup 2" {
+		t.Errorf("latest backup = %q, %v; want backup 2", data, err)
+	}
+
+	// The last backup is read from the directory after a restart, and old backups are deleted
+	archiver, err = NewArchiver(cfg)
+	if err != nil {
+		t.Fatalf("NewArchiver() error = %v", err)
+	}
+	archiver.Archive("alice", start.Add(3*time.Second), []byte("backup 2"))
+	archiver.Archive("alice", start.Add(4*time.Second), []byte("backup 3"))
+	archiver.Stop()
+
+	want = []string{"channel_backup_20260426_120002.backup", "channel_backup_20260426_120004.backup"}
+	if got := files(t, filepath.Join(dir, "alice")); len(got) != 2 || got[0] != want[0] || got[1] != want[1] {
+		t.Errorf("alice backups after restart = %v; want %v", got, want)
+	}
+
+	// Backups are dropped after stop
+	archiver.Archive("alice", start.Add(5*time.Second), []byte("backup 4"))
+}
@@ -0,0 +1,59 @@
+package backup
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+
+	"filippo.io/age"
+	"github.com/Primexz/lndnotify/internal/config"
+)
+
+// EncryptedSuffix is appended to the file name of encrypted backups
+const EncryptedSuffix = ".age"
+
+// Encrypt encrypts a backup with age to the recipients or with the passphrase of the config
+func Encrypt(data []byte, cfg config.BackupEventConfig) ([]byte, error) {
+	var recipients []age.Recipient
+	for _, r := range cfg.Recipients {
+		recipient, err := age.ParseX25519Recipient(r)
+		if err != nil {
+			return nil, fmt.Errorf("invalid recipient %q: %w", r, err)
+		}
+		recipients = append(recipients, recipient)
+	}
+	if cfg.Passphrase != "" {
+		recipient, err := age.NewScryptRecipient(cfg.Passphrase)
+		if err != nil {
+			return nil, err
+		}
+		recipients = append(recipients, recipient)
+	}
+
+	var buf bytes.Buffer
+	w, err := age.Encrypt(&buf, recipients...)
+	if err != nil {
+		return nil, fmt.Errorf("encrypting backup: %w", err)
+	}
+	if _, err := w.Write(data); err != nil {
+		return nil, fmt.Errorf("encrypting backup: %w", err)
+	}
+	if err := w.Close(); err != nil {
+		return nil, fmt.Errorf("encrypting backup: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// Decrypt decrypts a backup encrypted by Encrypt with an age identity, e.g. from
+// age.ParseIdentities or age.NewScryptIdentity for a passphrase
+func Decrypt(data []byte, identities ...age.Identity) ([]byte, error) {
+	r, err := age.Decrypt(bytes.NewReader(data), identities...)
+	if err != nil {
+		return nil, fmt.Errorf("decrypting backup: %w", err)
+	}
+	plain, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("decrypting backup: %w", err)
+	}
+	return plain, nil
+}
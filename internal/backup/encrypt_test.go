@@ -0,0 +1,60 @@
+package backup
+
+import (
+	"bytes"
+	"testing"
+
+	"filippo.io/age"
+	"github.com/Primexz/lndnotify/internal/config"
+)
+
+func TestEncrypt(t *testing.T) {
+	data := []byte("channel backup")
+
+	identity, err := age.GenerateX25519Identity()
+	if err != nil {
+		t.Fatal(err)
+	}
+	other, err := age.GenerateX25519Identity()
+	if err != nil {
+		t.Fatal(err)
+	}
+	passphrase, err := age.NewScryptIdentity("secret")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name     string
+		cfg      config.BackupEventConfig
+		identity age.Identity
+		wantErr  bool
+	}{
+		{"recipient", config.BackupEventConfig{Recipients: []string{identity.Recipient().String()}}, identity, false},
+		{"passphrase", config.BackupEventConfig{Passphrase: "secret"}, passphrase, false},
+		{"wrong identity", config.BackupEventConfig{Recipients: []string{identity.Recipient().String()}}, other, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			encrypted, err := Encrypt(data, tt.cfg)
+			if err != nil {
+				t.Fatalf("Encrypt() error = %v", err)
+			}
+			if bytes.Contains(encrypted, data) {
+				t.Fatal("encrypted backup contains the plain backup")
+			}
+
+			plain, err := Decrypt(encrypted, tt.identity)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Decrypt() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && !bytes.Equal(plain, data) {
+				t.Errorf("Decrypt() = %q, want %q", plain, data)
+			}
+		})
+	}
+
+	if _, err := Encrypt(data, config.BackupEventConfig{Recipients: []string{"age1invalid"}}); err == nil {
+		t.Error("Encrypt() with invalid recipient succeeded")
+	}
+}
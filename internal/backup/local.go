@@ -0,0 +1,108 @@
+package backup
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// localStorage archives backups to a directory, with a subdirectory per node
+type localStorage struct {
+	dir string
+}
+
+func newLocalStorage(dir string) *localStorage {
+	return &localStorage{dir: dir}
+}
+
+func (s *localStorage) String() string {
+	return "local"
+}
+
+// nodeDir returns the directory of the backups of a node
+func (s *localStorage) nodeDir(node string) string {
+	return filepath.Join(s.dir, node)
+}
+
+// put writes a backup to a temporary file, verifies it and renames it, so the directory never
+// contains incomplete backups
+func (s *localStorage) put(ctx context.Context, node, name string, data []byte, sum string) error {
+	dir := s.nodeDir(node)
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return fmt.Errorf("creating backup directory: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(dir, ".tmp-"+name)
+	if err != nil {
+		return fmt.Errorf("creating backup file: %w", err)
+	}
+	defer os.Remove(tmp.Name()) // nolint:errcheck
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close() // nolint:errcheck
+		return fmt.Errorf("writing backup file: %w", err)
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close() // nolint:errcheck
+		return fmt.Errorf("syncing backup file: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("closing backup file: %w", err)
+	}
+
+	written, err := fileSum(tmp.Name())
+	if err != nil {
+		return err
+	}
+	if written != sum {
+		return fmt.Errorf("backup file is corrupted: sha256 %s, expected %s", written, sum)
+	}
+
+	if err := os.Rename(tmp.Name(), filepath.Join(dir, name)); err != nil {
+		return fmt.Errorf("renaming backup file: %w", err)
+	}
+	return nil
+}
+
+func (s *localStorage) sum(ctx context.Context, node, name string) (string, error) {
+	return fileSum(filepath.Join(s.nodeDir(node), name))
+}
+
+func (s *localStorage) list(ctx context.Context, node string) ([]storedBackup, error) {
+	entries, err := os.ReadDir(s.nodeDir(node))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("reading backup directory: %w", err)
+	}
+
+	var backups []storedBackup
+	for _, entry := range entries {
+		if !entry.Type().IsRegular() {
+			continue
+		}
+		if t, ok := parseFileName(entry.Name()); ok {
+			backups = append(backups, storedBackup{name: entry.Name(), time: t})
+		}
+	}
+	return backups, nil
+}
+
+func (s *localStorage) remove(ctx context.Context, node, name string) error {
+	return os.Remove(filepath.Join(s.nodeDir(node), name))
+}
+
+// fileSum returns the hex encoded SHA-256 sum of a file
+func fileSum(path string) (string, error) {
+	// #nosec G304 -- path is built from the configured backup directory
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("reading backup file: %w", err)
+	}
+	hash := sha256.Sum256(data)
+	return hex.EncodeToString(hash[:]), nil
+}
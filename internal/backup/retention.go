@@ -0,0 +1,31 @@
+package backup
+
+import (
+	"sort"
+)
+
+// expired returns the backups that are no longer retained. The keepLast most recent backups
+// and the last backup of each of the keepDaily most recent days with backups are retained.
+func expired(backups []storedBackup, keepLast, keepDaily int) []storedBackup {
+	sorted := make([]storedBackup, len(backups))
+	copy(sorted, backups)
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].time.After(sorted[j].time)
+	})
+
+	var expired []storedBackup
+	days := make(map[string]struct{})
+	for i, b := range sorted {
+		day := b.time.Local().Format("2006-01-02")
+		_, seen := days[day]
+		if !seen && len(days) < keepDaily {
+			days[day] = struct{}{}
+			continue
+		}
+		if i < keepLast {
+			continue
+		}
+		expired = append(expired, b)
+	}
+	return expired
+}
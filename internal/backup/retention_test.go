@@ -0,0 +1,54 @@
+package backup
+
+import (
+	"encoding/hex"
+	"testing"
+	"time"
+)
+
+func hexSum(hash [32]byte) string {
+	return hex.EncodeToString(hash[:])
+}
+
+func TestExpired(t *testing.T) {
+	day := func(d, hour int) time.Time {
+		return time.Date(2026, 4, d, hour, 0, 0, 0, time.Local)
+	}
+	backups := []storedBackup{
+		{name: "a", time: day(20, 9)},
+		{name: "b", time: day(20, 18)},
+		{name: "c", time: day(21, 9)},
+		{name: "d", time: day(22, 9)},
+		{name: "e", time: day(22, 12)},
+		{name: "f", time: day(22, 18)},
+	}
+
+	tests := []struct {
+		name      string
+		keepLast  int
+		keepDaily int
+		want      []string
+	}{
+		{"keep last", 2, 0, []string{"d", "c", "b", "a"}},
+		{"keep daily", 0, 2, []string{"e", "d", "b", "a"}},
+		{"keep last and daily", 2, 3, []string{"d", "a"}},
+		{"keep all", 10, 10, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []string
+			for _, b := range expired(backups, tt.keepLast, tt.keepDaily) {
+				got = append(got, b.name)
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("expired() = %v; want %v", got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Errorf("expired() = %v; want %v", got, tt.want)
+					break
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,118 @@
+package backup
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"net/url"
+	"path"
+	"strings"
+
+	"github.com/Primexz/lndnotify/internal/config"
+	"github.com/minio/minio-go/v7"
+	"github.com/minio/minio-go/v7/pkg/credentials"
+)
+
+// s3SumMetadata is the user metadata holding the hex encoded SHA-256 sum of a backup
+const s3SumMetadata = "Sha256"
+
+// s3Storage archives backups to an S3-compatible bucket, with a prefix per node
+type s3Storage struct {
+	client *minio.Client
+	bucket string
+	prefix string
+}
+
+func newS3Storage(cfg config.BackupS3Config) (*s3Storage, error) {
+	endpoint, err := url.Parse(cfg.Endpoint)
+	if err != nil {
+		return nil, fmt.Errorf("invalid s3 endpoint: %w", err)
+	}
+
+	client, err := minio.New(endpoint.Host, &minio.Options{
+		Creds:  credentials.NewStaticV4(cfg.AccessKeyID, cfg.SecretAccessKey, ""),
+		Secure: endpoint.Scheme == "https",
+		Region: cfg.Region,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("creating s3 client: %w", err)
+	}
+
+	return &s3Storage{
+		client: client,
+		bucket: cfg.Bucket,
+		prefix: strings.Trim(cfg.Prefix, "/"),
+	}, nil
+}
+
+func (s *s3Storage) String() string {
+	return "s3"
+}
+
+// nodePrefix returns the key prefix of the backups of a node, empty or ending with a slash
+func (s *s3Storage) nodePrefix(node string) string {
+	prefix := path.Join(s.prefix, node)
+	if prefix == "" {
+		return ""
+	}
+	return prefix + "/"
+}
+
+// put uploads a backup with its SHA-256 checksum, which is verified by the server, and stores
+// the sum in the object metadata
+func (s *s3Storage) put(ctx context.Context, node, name string, data []byte, sum string) error {
+	raw, err := hex.DecodeString(sum)
+	if err != nil {
+		return fmt.Errorf("invalid backup sum: %w", err)
+	}
+	checksum := base64.StdEncoding.EncodeToString(raw)
+
+	// The checksum is sent as a signed header, as trailing checksums are not supported by all
+	// S3-compatible servers
+	info, err := s.client.PutObject(ctx, s.bucket, s.nodePrefix(node)+name, bytes.NewReader(data), int64(len(data)), minio.PutObjectOptions{
+		ContentType: "application/octet-stream",
+		UserMetadata: map[string]string{
+			s3SumMetadata:           sum,
+			"X-Amz-Checksum-Sha256": checksum,
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("uploading backup: %w", err)
+	}
+
+	// Servers without checksum support don't return it
+	if info.ChecksumSHA256 != "" && info.ChecksumSHA256 != checksum {
+		return fmt.Errorf("uploaded backup is corrupted: checksum %s, expected %s", info.ChecksumSHA256, checksum)
+	}
+	return nil
+}
+
+func (s *s3Storage) sum(ctx context.Context, node, name string) (string, error) {
+	info, err := s.client.StatObject(ctx, s.bucket, s.nodePrefix(node)+name, minio.StatObjectOptions{})
+	if err != nil {
+		return "", fmt.Errorf("reading backup metadata: %w", err)
+	}
+	return info.UserMetadata[s3SumMetadata], nil
+}
+
+func (s *s3Storage) list(ctx context.Context, node string) ([]storedBackup, error) {
+	prefix := s.nodePrefix(node)
+
+	var backups []storedBackup
+	for object := range s.client.ListObjects(ctx, s.bucket, minio.ListObjectsOptions{Prefix: prefix}) {
+		if object.Err != nil {
+			return nil, fmt.Errorf("listing backups: %w", object.Err)
+		}
+		name := strings.TrimPrefix(object.Key, prefix)
+		if t, ok := parseFileName(name); ok {
+			backups = append(backups, storedBackup{name: name, time: t})
+		}
+	}
+	return backups, nil
+}
+
+func (s *s3Storage) remove(ctx context.Context, node, name string) error {
+	return s.client.RemoveObject(ctx, s.bucket, s.nodePrefix(node)+name, minio.RemoveObjectOptions{})
+}
@@ -0,0 +1,180 @@
+package backup
+
+import (
+	"bufio"
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/xml"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Primexz/lndnotify/internal/config"
+)
+
+// fakeS3 is an in-memory S3 bucket supporting the requests of the s3 storage
+type fakeS3 struct {
+	bucket  string
+	objects map[string]fakeObject
+	mu      sync.Mutex
+}
+
+type fakeObject struct {
+	data     []byte
+	sum      string
+	modified time.Time
+}
+
+func newFakeS3(t *testing.T, bucket string) (*fakeS3, *httptest.Server) {
+	t.Helper()
+
+	s := &fakeS3{bucket: bucket, objects: make(map[string]fakeObject)}
+	server := httptest.NewServer(s)
+	t.Cleanup(server.Close)
+	return s, server
+}
+
+func (s *fakeS3) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if !strings.HasPrefix(r.Header.Get("Authorization"), "AWS4-HMAC-SHA256 Credential=access/") {
+		http.Error(w, "missing signature", http.StatusForbidden)
+		return
+	}
+
+	key := strings.TrimPrefix(r.URL.Path, "/"+s.bucket+"/")
+	switch {
+	case r.Method == http.MethodPut:
+		data, err := readPayload(r)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		hash := sha256.Sum256(data)
+		checksum := base64.StdEncoding.EncodeToString(hash[:])
+		if got := r.Header.Get("X-Amz-Checksum-Sha256"); got != checksum {
+			http.Error(w, "checksum mismatch", http.StatusBadRequest)
+			return
+		}
+		s.objects[key] = fakeObject{data: data, sum: r.Header.Get("X-Amz-Meta-Sha256"), modified: time.Now()}
+		w.Header().Set("X-Amz-Checksum-Sha256", checksum)
+		w.Header().Set("ETag", `"etag"`)
+	case r.Method == http.MethodHead:
+		object, ok := s.objects[key]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Header().Set("X-Amz-Meta-Sha256", object.sum)
+		w.Header().Set("Last-Modified", object.modified.UTC().Format(http.TimeFormat))
+		w.Header().Set("Content-Length", "0")
+		w.Header().Set("ETag", `"etag"`)
+	case r.Method == http.MethodDelete:
+		delete(s.objects, key)
+		w.WriteHeader(http.StatusNoContent)
+	case r.Method == http.MethodGet && r.URL.Query().Get("list-type") == "2":
+		type content struct {
+			Key          string
+			LastModified string
+			Size         int
+		}
+		result := struct {
+			XMLName  xml.Name `xml:"ListBucketResult"`
+			Name     string
+			Prefix   string
+			Contents []content
+		}{Name: s.bucket, Prefix: r.URL.Query().Get("prefix")}
+		for key, object := range s.objects {
+			if strings.HasPrefix(key, result.Prefix) && !strings.Contains(strings.TrimPrefix(key, result.Prefix), "/") {
+				result.Contents = append(result.Contents, content{
+					Key:          key,
+					LastModified: object.modified.UTC().Format(time.RFC3339Nano),
+					Size:         len(object.data),
+				})
+			}
+		}
+		w.Header().Set("Content-Type", "application/xml")
+		xml.NewEncoder(w).Encode(result) // nolint:errcheck
+	default:
+		http.Error(w, "unsupported request", http.StatusNotImplemented)
+	}
+}
+
+// readPayload reads the body of a PUT request, decoding the chunks of streaming signatures
+func readPayload(r *http.Request) ([]byte, error) {
+	if r.Header.Get("X-Amz-Content-Sha256") != "STREAMING-AWS4-HMAC-SHA256-PAYLOAD" {
+		return io.ReadAll(r.Body)
+	}
+
+	var data []byte
+	body := bufio.NewReader(r.Body)
+	for {
+		header, err := body.ReadString('\n')
+		if err != nil {
+			return nil, err
+		}
+		size, err := strconv.ParseInt(strings.SplitN(header, ";", 2)[0], 16, 64)
+		if err != nil {
+			return nil, err
+		}
+		if size == 0 {
+			return data, nil
+		}
+		chunk := make([]byte, size+2)
+		if _, err := io.ReadFull(body, chunk); err != nil {
+			return nil, err
+		}
+		data = append(data, chunk[:size]...)
+	}
+}
+
+func TestS3Storage(t *testing.T) {
+	fake, server := newFakeS3(t, "backups")
+
+	s, err := newS3Storage(config.BackupS3Config{
+		Endpoint:        server.URL,
+		Bucket:          "backups",
+		Prefix:          "/lndnotify/",
+		Region:          "us-east-1",
+		AccessKeyID:     "access",
+		SecretAccessKey: "secret",
+	})
+	if err != nil {
+		t.Fatalf("newS3Storage() error = %v", err)
+	}
+
+	ctx := context.Background()
+	data := []byte("backup")
+	hash := sha256.Sum256(data)
+	sum := hexSum(hash)
+
+	if err := s.put(ctx, "alice", "channel_backup_20260426_120000.backup", data, sum); err != nil {
+		t.Fatalf("put() error = %v", err)
+	}
+	if _, ok := fake.objects["lndnotify/alice/channel_backup_20260426_120000.backup"]; !ok {
+		t.Fatalf("objects = %v; want backup under the node prefix", fake.objects)
+	}
+	fake.objects["lndnotify/alice/notes.txt"] = fakeObject{modified: time.Now()}
+
+	backups, err := s.list(ctx, "alice")
+	if err != nil || len(backups) != 1 || backups[0].name != "channel_backup_20260426_120000.backup" {
+		t.Fatalf("list() = %+v, %v; want the backup", backups, err)
+	}
+	if got, err := s.sum(ctx, "alice", backups[0].name); err != nil || got != sum {
+		t.Errorf("sum() = %q, %v; want %q", got, err, sum)
+	}
+
+	if err := s.remove(ctx, "alice", backups[0].name); err != nil {
+		t.Fatalf("remove() error = %v", err)
+	}
+	if backups, err := s.list(ctx, "alice"); err != nil || len(backups) != 0 {
+		t.Errorf("list() after remove = %+v, %v; want none", backups, err)
+	}
+}
@@ -40,7 +40,8 @@ type ChannelManager struct {
 	refreshInterval time.Duration
 
 	feeChangeCh chan FeeChangeEvent
-	refreshCh   chan struct{}
+	refreshSubs []chan struct{}
+	subMu       sync.Mutex
 }
 
 func NewChannelManager(client lnrpc.LightningClient) *ChannelManager {
@@ -53,7 +54,6 @@ func NewChannelManager(client lnrpc.LightningClient) *ChannelManager {
 		cancel:          cancel,
 		refreshInterval: 5 * time.Minute,
 		feeChangeCh:     make(chan FeeChangeEvent, 100),
-		refreshCh:       make(chan struct{}, 100),
 	}
 }
 
@@ -135,9 +135,15 @@ func (cm *ChannelManager) GetFeeChangeChannel() <-chan FeeChangeEvent {
 	return cm.feeChangeCh
 }
 
-// GetRefreshChannel returns the channel that signals when a refresh has occurred
-func (cm *ChannelManager) GetRefreshChannel() <-chan struct{} {
-	return cm.refreshCh
+// SubscribeRefresh returns a channel that signals when a refresh has occurred.
+// Signals are coalesced if the subscriber is still busy with the previous refresh.
+func (cm *ChannelManager) SubscribeRefresh() <-chan struct{} {
+	cm.subMu.Lock()
+	defer cm.subMu.Unlock()
+
+	ch := make(chan struct{}, 1)
+	cm.refreshSubs = append(cm.refreshSubs, ch)
+	return ch
 }
 
 func (cm *ChannelManager) refreshLoop() {
@@ -192,12 +198,25 @@ func (cm *ChannelManager) refreshChannels() error {
 		cm.checkFeeChanges(ch, chanEdge, oldEdge)
 	}
 
-	cm.refreshCh <- struct{}{}
+	cm.notifyRefresh()
 
 	log.WithField("channel_count", len(cm.channels)).Debug("channel state refreshed")
 	return nil
 }
 
+// notifyRefresh signals all refresh subscribers without blocking
+func (cm *ChannelManager) notifyRefresh() {
+	cm.subMu.Lock()
+	defer cm.subMu.Unlock()
+
+	for _, ch := range cm.refreshSubs {
+		select {
+		case ch <- struct{}{}:
+		default:
+		}
+	}
+}
+
 // checkFeeChanges compares old and new channel states to detect fee changes
 func (cm *ChannelManager) checkFeeChanges(ch *lnrpc.Channel, newEdge *lnrpc.ChannelEdge, oldEdge *lnrpc.ChannelEdge) {
 	if oldEdge == nil || newEdge == nil {
@@ -0,0 +1,207 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Primexz/lndnotify/internal/events"
+	"github.com/lightningnetwork/lnd/lnrpc"
+	log "github.com/sirupsen/logrus"
+	"golang.org/x/text/language"
+)
+
+// Message is a chat message received over a transport
+type Message struct {
+	ChatID string
+	Text   string
+}
+
+// Transport receives commands from a chat service and sends the responses
+type Transport interface {
+	// Receive blocks until new messages arrive, the poll times out or ctx is cancelled
+	Receive(ctx context.Context) ([]Message, error)
+
+	// Reply sends a response to the chat the message was received from
+	Reply(ctx context.Context, msg Message, text string) error
+}
+
+// Node provides the data of an LND node for the command responses
+type Node interface {
+	Node() events.NodeInfo
+	Channels() []*lnrpc.Channel
+	Info(ctx context.Context) (*lnrpc.GetInfoResponse, error)
+	PendingChannels(ctx context.Context) (*lnrpc.PendingChannelsResponse, error)
+	WalletBalance(ctx context.Context) (*lnrpc.WalletBalanceResponse, error)
+	ForwardingHistory(ctx context.Context, start, end time.Time) ([]*lnrpc.ForwardingEvent, error)
+}
+
+// handlerFunc renders the response of a command for a single node
+type handlerFunc func(ctx context.Context, node Node, args []string) (string, error)
+
+// command is a chat command with its description for /help
+type command struct {
+	description string
+	handler     handlerFunc
+}
+
+// Service answers chat commands with the state of the monitored nodes
+type Service struct {
+	transport Transport
+	nodes     []Node
+	lang      language.Tag
+	commands  map[string]command
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// NewService creates a command service for the given nodes. Numbers are formatted for lang.
+func NewService(transport Transport, nodes []Node, lang language.Tag) *Service {
+	ctx, cancel := context.WithCancel(context.Background())
+	s := &Service{
+		transport: transport,
+		nodes:     nodes,
+		lang:      lang,
+		ctx:       ctx,
+		cancel:    cancel,
+	}
+
+	s.commands = map[string]command{
+		"balance":  {"On-chain and channel balances", s.balance},
+		"channels": {"Open channels and their liquidity", s.channels},
+		"pending":  {"Pending channels", s.pending},
+		"fees":     {"Forwarding fees of a period, e.g. /fees 24h or /fees 7d", s.fees},
+		"status":   {"Node and sync status", s.status},
+	}
+	return s
+}
+
+// Start starts receiving commands
+func (s *Service) Start() {
+	log.Debug("starting command service")
+
+	s.wg.Add(1)
+	go s.run()
+}
+
+// Stop stops receiving commands
+func (s *Service) Stop() {
+	log.Debug("stopping command service")
+
+	s.cancel()
+	s.wg.Wait()
+}
+
+func (s *Service) run() {
+	defer s.wg.Done()
+
+	for {
+		messages, err := s.transport.Receive(s.ctx)
+		if err != nil {
+			if s.ctx.Err() != nil {
+				return
+			}
+			log.WithError(err).Error("error receiving commands, retrying")
+
+			select {
+			case <-s.ctx.Done():
+				return
+			case <-time.After(5 * time.Second):
+			}
+			continue
+		}
+
+		for _, msg := range messages {
+			response, ok := s.Handle(s.ctx, msg.Text)
+			if !ok {
+				continue
+			}
+			if err := s.transport.Reply(s.ctx, msg, response); err != nil {
+				log.WithError(err).Error("error sending command response")
+			}
+		}
+	}
+}
+
+// Handle executes a command like "/fees 24h" and returns the response. It returns
+// false if the text is not a command.
+func (s *Service) Handle(ctx context.Context, text string) (string, bool) {
+	fields := strings.Fields(text)
+	if len(fields) == 0 || !strings.HasPrefix(fields[0], "/") {
+		return "", false
+	}
+
+	// Telegram appends the bot name in group chats, e.g. /balance@lndnotify_bot
+	name, _, _ := strings.Cut(strings.TrimPrefix(fields[0], "/"), "@")
+	name = strings.ToLower(name)
+	args := fields[1:]
+
+	log.WithFields(log.Fields{"command": name, "args": args}).Info("received command")
+
+	cmd, ok := s.commands[name]
+	if !ok {
+		return s.help(), true
+	}
+
+	// An argument matching a node name limits the response to that node
+	nodes := s.nodes
+	var cmdArgs []string
+	for _, arg := range args {
+		if node := s.findNode(arg); node != nil {
+			nodes = []Node{node}
+			continue
+		}
+		cmdArgs = append(cmdArgs, arg)
+	}
+
+	var sections []string
+	for _, node := range nodes {
+		response, err := cmd.handler(ctx, node, cmdArgs)
+		if err != nil {
+			log.WithField("command", name).WithError(err).Error("error executing command")
+			response = fmt.Sprintf("❌ %v", err)
+		}
+
+		if len(s.nodes) > 1 {
+			response = nodeHeader(node) + "\n" + response
+		}
+		sections = append(sections, response)
+	}
+
+	return strings.Join(sections, "\n\n"), true
+}
+
+func (s *Service) help() string {
+	var b strings.Builder
+	b.WriteString("Available commands:\n")
+	for _, name := range []string{"balance", "channels", "pending", "fees", "status"} {
+		fmt.Fprintf(&b, "/%s - %s\n", name, s.commands[name].description)
+	}
+	if len(s.nodes) > 1 {
+		b.WriteString("\nAdd a node name to query a single node, e.g. /balance ")
+		b.WriteString(s.nodes[0].Node().Name)
+	}
+	return strings.TrimSpace(b.String())
+}
+
+// findNode returns the node with the given name, or nil
+func (s *Service) findNode(name string) Node {
+	for _, node := range s.nodes {
+		if node.Node().Name != "" && strings.EqualFold(node.Node().Name, name) {
+			return node
+		}
+	}
+	return nil
+}
+
+func nodeHeader(node Node) string {
+	info := node.Node()
+	if info.Alias == "" || info.Alias == info.Name {
+		return fmt.Sprintf("⚡️ %s", info.Name)
+	}
+	return fmt.Sprintf("⚡️ %s (%s)", info.Alias, info.Name)
+}
@@ -0,0 +1,201 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Primexz/lndnotify/pkg/format"
+	"github.com/lightningnetwork/lnd/lnrpc"
+)
+
+// defaultFeePeriod is used by /fees if no period is given
+const defaultFeePeriod = 24 * time.Hour
+
+func (s *Service) balance(ctx context.Context, node Node, _ []string) (string, error) {
+	wallet, err := node.WalletBalance(ctx)
+	if err != nil {
+		return "", fmt.Errorf("fetching wallet balance: %w", err)
+	}
+
+	var local, remote int64
+	for _, ch := range node.Channels() {
+		local += ch.LocalBalance
+		remote += ch.RemoteBalance
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "🔗 On-chain: %s sats", format.FormatBasic(float64(wallet.ConfirmedBalance), s.lang))
+	if wallet.UnconfirmedBalance != 0 {
+		fmt.Fprintf(&b, " (+%s sats unconfirmed)", format.FormatBasic(float64(wallet.UnconfirmedBalance), s.lang))
+	}
+	fmt.Fprintf(&b, "\n⚡️ Local: %s sats", format.FormatBasic(float64(local), s.lang))
+	fmt.Fprintf(&b, "\n⚡️ Remote: %s sats", format.FormatBasic(float64(remote), s.lang))
+	fmt.Fprintf(&b, "\n💰 Total: %s sats", format.FormatBasic(float64(wallet.ConfirmedBalance+local), s.lang))
+	return b.String(), nil
+}
+
+func (s *Service) channels(_ context.Context, node Node, _ []string) (string, error) {
+	channels := node.Channels()
+	if len(channels) == 0 {
+		return "No open channels", nil
+	}
+
+	// Channels with the least outbound liquidity first
+	sort.Slice(channels, func(i, j int) bool {
+		return localPercent(channels[i]) < localPercent(channels[j])
+	})
+
+	var active int
+	var lines []string
+	for _, ch := range channels {
+		status := "🔴"
+		if ch.Active {
+			status = "🟢"
+			active++
+		}
+
+		alias := ch.PeerAlias
+		if alias == "" {
+			alias = format.FormatPubKey(ch.RemotePubkey)
+		}
+
+		lines = append(lines, fmt.Sprintf("%s %s: %s / %s sats (%s%% local)",
+			status,
+			alias,
+			format.FormatBasic(float64(ch.LocalBalance), s.lang),
+			format.FormatBasic(float64(ch.Capacity), s.lang),
+			format.FormatBasic(localPercent(ch), s.lang),
+		))
+	}
+
+	header := fmt.Sprintf("📡 %d channels (%d active, %d inactive)", len(channels), active, len(channels)-active)
+	return header + "\n\n" + strings.Join(lines, "\n"), nil
+}
+
+func (s *Service) pending(ctx context.Context, node Node, _ []string) (string, error) {
+	resp, err := node.PendingChannels(ctx)
+	if err != nil {
+		return "", fmt.Errorf("fetching pending channels: %w", err)
+	}
+
+	var lines []string
+	for _, ch := range resp.PendingOpenChannels {
+		lines = append(lines, fmt.Sprintf("⏳ Opening with %s: %s sats",
+			format.FormatPubKey(ch.Channel.RemoteNodePub),
+			format.FormatBasic(float64(ch.Channel.Capacity), s.lang),
+		))
+	}
+	for _, ch := range resp.WaitingCloseChannels {
+		lines = append(lines, fmt.Sprintf("⏳ Closing with %s: %s sats in limbo",
+			format.FormatPubKey(ch.Channel.RemoteNodePub),
+			format.FormatBasic(float64(ch.LimboBalance), s.lang),
+		))
+	}
+	for _, ch := range resp.PendingForceClosingChannels {
+		lines = append(lines, fmt.Sprintf("🔴 Force closing with %s: %s sats in limbo, matures in %d blocks",
+			format.FormatPubKey(ch.Channel.RemoteNodePub),
+			format.FormatBasic(float64(ch.LimboBalance), s.lang),
+			ch.BlocksTilMaturity,
+		))
+	}
+
+	if len(lines) == 0 {
+		return "No pending channels", nil
+	}
+	return strings.Join(lines, "\n"), nil
+}
+
+func (s *Service) fees(ctx context.Context, node Node, args []string) (string, error) {
+	period := defaultFeePeriod
+	if len(args) > 0 {
+		var err error
+		if period, err = parsePeriod(args[0]); err != nil {
+			return "", err
+		}
+	}
+
+	end := time.Now()
+	forwards, err := node.ForwardingHistory(ctx, end.Add(-period), end)
+	if err != nil {
+		return "", fmt.Errorf("fetching forwarding history: %w", err)
+	}
+
+	var volumeMsat, feesMsat uint64
+	for _, fwd := range forwards {
+		volumeMsat += fwd.AmtOutMsat
+		feesMsat += fwd.FeeMsat
+	}
+	volume := float64(volumeMsat) / 1000
+	fees := float64(feesMsat) / 1000
+
+	return fmt.Sprintf("💰 Forwarding fees of the last %s\nForwards: %d\nVolume: %s sats\nEarned: %s sats (%s ppm)",
+		formatPeriod(period),
+		len(forwards),
+		format.FormatBasic(volume, s.lang),
+		format.FormatDetailed(fees, s.lang),
+		format.FormatRatePPM(fees, volume, s.lang),
+	), nil
+}
+
+func (s *Service) status(ctx context.Context, node Node, _ []string) (string, error) {
+	info, err := node.Info(ctx)
+	if err != nil {
+		return "", fmt.Errorf("fetching node info: %w", err)
+	}
+
+	return fmt.Sprintf("⚡️ %s (%s)\nVersion: %s\nBlock height: %d\nSynced to chain: %s\nSynced to graph: %s\nChannels: %d active, %d inactive, %d pending\nPeers: %d",
+		info.Alias,
+		format.FormatPubKey(info.IdentityPubkey),
+		info.Version,
+		info.BlockHeight,
+		checkmark(info.SyncedToChain),
+		checkmark(info.SyncedToGraph),
+		info.NumActiveChannels,
+		info.NumInactiveChannels,
+		info.NumPendingChannels,
+		info.NumPeers,
+	), nil
+}
+
+// parsePeriod parses a period like "24h", "90m" or "7d"
+func parsePeriod(s string) (time.Duration, error) {
+	if days, ok := strings.CutSuffix(s, "d"); ok {
+		n, err := strconv.Atoi(days)
+		if err != nil || n <= 0 {
+			return 0, fmt.Errorf("invalid period %q", s)
+		}
+		return time.Duration(n) * 24 * time.Hour, nil
+	}
+
+	d, err := time.ParseDuration(s)
+	if err != nil || d <= 0 {
+		return 0, fmt.Errorf("invalid period %q", s)
+	}
+	return d, nil
+}
+
+func formatPeriod(d time.Duration) string {
+	if d >= 24*time.Hour && d%(24*time.Hour) == 0 {
+		return fmt.Sprintf("%dd", d/(24*time.Hour))
+	}
+	return format.FormatDuration(d).String()
+}
+
+func localPercent(ch *lnrpc.Channel) float64 {
+	total := ch.LocalBalance + ch.RemoteBalance
+	if total <= 0 {
+		return 0
+	}
+	return float64(ch.LocalBalance) * 100 / float64(total)
+}
+
+func checkmark(ok bool) string {
+	if ok {
+		return "✅"
+	}
+	return "❌"
+}
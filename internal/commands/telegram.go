@@ -0,0 +1,214 @@
+package commands
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/nicholas-fedor/shoutrrr/pkg/services/chat/telegram"
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	// defaultTelegramAPIURL is the Telegram Bot API endpoint
+	defaultTelegramAPIURL = "https://api.telegram.org"
+
+	// telegramMaxMessageLength is the maximum length of a Telegram message in characters
+	telegramMaxMessageLength = 4096
+)
+
+// TelegramTransport receives commands with long polling of the Telegram Bot API. Only
+// messages from the chats of the configured provider URL are accepted.
+type TelegramTransport struct {
+	baseURL     string
+	chats       map[string]struct{}
+	pollTimeout time.Duration
+	offset      int64
+	client      *http.Client
+}
+
+type telegramResponse struct {
+	Ok          bool            `json:"ok"`
+	Description string          `json:"description"`
+	Result      json.RawMessage `json:"result"`
+}
+
+type telegramUpdate struct {
+	UpdateID    int64            `json:"update_id"`
+	Message     *telegramMessage `json:"message"`
+	ChannelPost *telegramMessage `json:"channel_post"`
+}
+
+type telegramMessage struct {
+	Text string `json:"text"`
+	Chat struct {
+		ID       int64  `json:"id"`
+		Username string `json:"username"`
+	} `json:"chat"`
+}
+
+// NewTelegramTransport creates a transport for the bot token and chats of a shoutrrr
+// telegram:// URL. If apiURL is empty, the public Telegram Bot API is used.
+func NewTelegramTransport(providerURL string, apiURL string, pollTimeout time.Duration) (*TelegramTransport, error) {
+	serviceURL, err := url.Parse(providerURL)
+	if err != nil {
+		return nil, fmt.Errorf("parsing provider URL: %w", err)
+	}
+
+	var cfg telegram.Config
+	if err := cfg.SetURL(serviceURL); err != nil {
+		return nil, fmt.Errorf("parsing telegram URL: %w", err)
+	}
+
+	if apiURL == "" {
+		apiURL = defaultTelegramAPIURL
+	}
+
+	chats := make(map[string]struct{}, len(cfg.Chats))
+	for _, chat := range cfg.Chats {
+		chats[chat] = struct{}{}
+	}
+
+	return &TelegramTransport{
+		baseURL:     fmt.Sprintf("%s/bot%s", apiURL, cfg.Token),
+		chats:       chats,
+		pollTimeout: pollTimeout,
+		client: &http.Client{
+			Timeout: pollTimeout + 10*time.Second,
+		},
+	}, nil
+}
+
+// Receive fetches new messages with a long poll of getUpdates
+func (t *TelegramTransport) Receive(ctx context.Context) ([]Message, error) {
+	query := url.Values{}
+	query.Set("offset", strconv.FormatInt(t.offset, 10))
+	query.Set("timeout", strconv.Itoa(int(t.pollTimeout.Seconds())))
+	query.Set("allowed_updates", `["message","channel_post"]`)
+
+	var updates []telegramUpdate
+	if err := t.call(ctx, http.MethodGet, "getUpdates?"+query.Encode(), nil, &updates); err != nil {
+		return nil, err
+	}
+
+	var messages []Message
+	for _, update := range updates {
+		t.offset = update.UpdateID + 1
+
+		msg := update.Message
+		if msg == nil {
+			msg = update.ChannelPost
+		}
+		if msg == nil || msg.Text == "" {
+			continue
+		}
+
+		chatID := strconv.FormatInt(msg.Chat.ID, 10)
+		if !t.allowed(chatID, msg.Chat.Username) {
+			log.WithField("chat", chatID).Warn("ignoring command from unknown telegram chat")
+			continue
+		}
+
+		messages = append(messages, Message{
+			ChatID: chatID,
+			Text:   msg.Text,
+		})
+	}
+	return messages, nil
+}
+
+// Reply sends the text to the chat of the message. Texts longer than the Telegram limit
+// are split into multiple messages.
+func (t *TelegramTransport) Reply(ctx context.Context, msg Message, text string) error {
+	for _, part := range splitMessage(text, telegramMaxMessageLength) {
+		payload := map[string]string{
+			"chat_id": msg.ChatID,
+			"text":    part,
+		}
+		if err := t.call(ctx, http.MethodPost, "sendMessage", payload, nil); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (t *TelegramTransport) allowed(chatID string, username string) bool {
+	if _, ok := t.chats[chatID]; ok {
+		return true
+	}
+	if username == "" {
+		return false
+	}
+	_, ok := t.chats["@"+username]
+	return ok
+}
+
+// call executes a Bot API method and decodes its result into result, if not nil
+func (t *TelegramTransport) call(ctx context.Context, method string, endpoint string, payload any, result any) error {
+	var data []byte
+	if payload != nil {
+		var err error
+		if data, err = json.Marshal(payload); err != nil {
+			return err
+		}
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, t.baseURL+"/"+endpoint, bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	if payload != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := t.client.Do(req)
+	if err != nil {
+		// The URL of the request contains the bot token and must not end up in the logs
+		var urlErr *url.Error
+		if errors.As(err, &urlErr) {
+			return fmt.Errorf("telegram %s: %w", endpoint, urlErr.Err)
+		}
+		return err
+	}
+	defer resp.Body.Close() // nolint:errcheck
+
+	var decoded telegramResponse
+	if err := json.NewDecoder(resp.Body).Decode(&decoded); err != nil {
+		return fmt.Errorf("decoding telegram response: %w", err)
+	}
+	if !decoded.Ok {
+		return fmt.Errorf("telegram error: status %d: %s", resp.StatusCode, decoded.Description)
+	}
+
+	if result == nil {
+		return nil
+	}
+	return json.Unmarshal(decoded.Result, result)
+}
+
+// splitMessage splits text into parts of at most limit characters, preferring line breaks
+func splitMessage(text string, limit int) []string {
+	runes := []rune(text)
+	var parts []string
+	for len(runes) > limit {
+		cut := limit
+		for i := limit; i > limit/2; i-- {
+			if runes[i] == '\n' {
+				cut = i
+				break
+			}
+		}
+		parts = append(parts, string(runes[:cut]))
+		runes = runes[cut:]
+		if len(runes) > 0 && runes[0] == '\n' {
+			runes = runes[1:]
+		}
+	}
+	return append(parts, string(runes))
+}
@@ -0,0 +1,250 @@
+package commands
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Primexz/lndnotify/internal/events"
+	"github.com/lightningnetwork/lnd/lnrpc"
+	"golang.org/x/text/language"
+)
+
+const testBotToken = "123456:test-token"
+
+// telegramStandIn is a local stand-in for the Telegram Bot API
+type telegramStandIn struct {
+	mu      sync.Mutex
+	updates []map[string]any
+	offsets []string
+	sent    []map[string]string
+}
+
+func (s *telegramStandIn) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	switch r.URL.Path {
+	case "/bot" + testBotToken + "/getUpdates":
+		s.offsets = append(s.offsets, r.URL.Query().Get("offset"))
+		updates := s.updates
+		s.updates = nil
+		writeTelegramResult(w, updates)
+	case "/bot" + testBotToken + "/sendMessage":
+		var payload map[string]string
+		if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		s.sent = append(s.sent, payload)
+		writeTelegramResult(w, map[string]any{"message_id": len(s.sent)})
+	default:
+		w.WriteHeader(http.StatusNotFound)
+		_ = json.NewEncoder(w).Encode(map[string]any{"ok": false, "description": "Not Found"})
+	}
+}
+
+func writeTelegramResult(w http.ResponseWriter, result any) {
+	_ = json.NewEncoder(w).Encode(map[string]any{"ok": true, "result": result})
+}
+
+func telegramUpdateMessage(updateID int64, chatID int64, text string) map[string]any {
+	return map[string]any{
+		"update_id": updateID,
+		"message": map[string]any{
+			"text": text,
+			"chat": map[string]any{"id": chatID},
+		},
+	}
+}
+
+func newTestTransport(t *testing.T, standIn *telegramStandIn) *TelegramTransport {
+	t.Helper()
+
+	server := httptest.NewServer(standIn)
+	t.Cleanup(server.Close)
+
+	transport, err := NewTelegramTransport("telegram://"+testBotToken+"@telegram?chats=42", server.URL, time.Second)
+	if err != nil {
+		t.Fatalf("NewTelegramTransport() error = %v", err)
+	}
+	return transport
+}
+
+func TestTelegramTransportReceive(t *testing.T) {
+	standIn := &telegramStandIn{
+		updates: []map[string]any{
+			telegramUpdateMessage(10, 42, "/balance"),
+			telegramUpdateMessage(11, 99, "/status"),
+			telegramUpdateMessage(12, 42, "/fees 7d"),
+		},
+	}
+	transport := newTestTransport(t, standIn)
+
+	messages, err := transport.Receive(context.Background())
+	if err != nil {
+		t.Fatalf("Receive() error = %v", err)
+	}
+
+	// Messages of chat 99 are not part of the provider URL and must be ignored
+	if len(messages) != 2 {
+		t.Fatalf("Receive() returned %d messages; want 2", len(messages))
+	}
+	if messages[0].ChatID != "42" || messages[0].Text != "/balance" {
+		t.Errorf("Receive()[0] = %+v; want chat 42 /balance", messages[0])
+	}
+	if messages[1].Text != "/fees 7d" {
+		t.Errorf("Receive()[1].Text = %q; want %q", messages[1].Text, "/fees 7d")
+	}
+
+	if _, err := transport.Receive(context.Background()); err != nil {
+		t.Fatalf("Receive() error = %v", err)
+	}
+	if got := standIn.offsets; len(got) != 2 || got[0] != "0" || got[1] != "13" {
+		t.Errorf("getUpdates offsets = %v; want [0 13]", got)
+	}
+}
+
+func TestTelegramTransportReply(t *testing.T) {
+	standIn := &telegramStandIn{}
+	transport := newTestTransport(t, standIn)
+
+	long := strings.Repeat("line\n", 1000)
+	if err := transport.Reply(context.Background(), Message{ChatID: "42"}, long); err != nil {
+		t.Fatalf("Reply() error = %v", err)
+	}
+
+	if len(standIn.sent) != 2 {
+		t.Fatalf("sendMessage called %d times; want 2", len(standIn.sent))
+	}
+	for _, sent := range standIn.sent {
+		if sent["chat_id"] != "42" {
+			t.Errorf("sendMessage chat_id = %q; want 42", sent["chat_id"])
+		}
+		if n := len([]rune(sent["text"])); n > telegramMaxMessageLength {
+			t.Errorf("sendMessage text length = %d; want <= %d", n, telegramMaxMessageLength)
+		}
+	}
+}
+
+func TestTelegramTransportError(t *testing.T) {
+	transport := newTestTransport(t, &telegramStandIn{})
+	transport.baseURL += "-invalid"
+
+	if _, err := transport.Receive(context.Background()); err == nil {
+		t.Error("Receive() error = nil; want error")
+	}
+}
+
+func TestTelegramTransportErrorRedactsToken(t *testing.T) {
+	transport := newTestTransport(t, &telegramStandIn{})
+	// Nothing listens on port 1, so the request fails before a response is received
+	transport.baseURL = "http://127.0.0.1:1/bot" + testBotToken
+
+	_, err := transport.Receive(context.Background())
+	if err == nil {
+		t.Fatal("Receive() error = nil; want error")
+	}
+	if strings.Contains(err.Error(), testBotToken) {
+		t.Errorf("Receive() error = %q; contains the bot token", err)
+	}
+
+	err = transport.Reply(context.Background(), Message{ChatID: "42"}, "text")
+	if err == nil {
+		t.Fatal("Reply() error = nil; want error")
+	}
+	if strings.Contains(err.Error(), testBotToken) {
+		t.Errorf("Reply() error = %q; contains the bot token", err)
+	}
+}
+
+// fakeNode is a node with static data
+type fakeNode struct {
+	name     string
+	channels []*lnrpc.Channel
+	forwards []*lnrpc.ForwardingEvent
+}
+
+func (n *fakeNode) Node() events.NodeInfo {
+	return events.NodeInfo{Name: n.name, Alias: n.name}
+}
+
+func (n *fakeNode) Channels() []*lnrpc.Channel {
+	return n.channels
+}
+
+func (n *fakeNode) Info(ctx context.Context) (*lnrpc.GetInfoResponse, error) {
+	return &lnrpc.GetInfoResponse{Alias: n.name, SyncedToChain: true}, nil
+}
+
+func (n *fakeNode) PendingChannels(ctx context.Context) (*lnrpc.PendingChannelsResponse, error) {
+	return &lnrpc.PendingChannelsResponse{}, nil
+}
+
+func (n *fakeNode) WalletBalance(ctx context.Context) (*lnrpc.WalletBalanceResponse, error) {
+	return &lnrpc.WalletBalanceResponse{ConfirmedBalance: 1500000}, nil
+}
+
+func (n *fakeNode) ForwardingHistory(ctx context.Context, start, end time.Time) ([]*lnrpc.ForwardingEvent, error) {
+	return n.forwards, nil
+}
+
+func TestServiceOverTelegram(t *testing.T) {
+	standIn := &telegramStandIn{
+		updates: []map[string]any{
+			telegramUpdateMessage(1, 42, "/balance"),
+			telegramUpdateMessage(2, 42, "/fees@lndnotify_bot 7d"),
+			telegramUpdateMessage(3, 42, "hello"),
+		},
+	}
+	transport := newTestTransport(t, standIn)
+
+	node := &fakeNode{
+		name: "alice",
+		channels: []*lnrpc.Channel{
+			{LocalBalance: 200000, RemoteBalance: 800000, Capacity: 1000000, Active: true},
+		},
+		forwards: []*lnrpc.ForwardingEvent{
+			{AmtOutMsat: 1000000000, FeeMsat: 100000},
+			{AmtOutMsat: 1000000000, FeeMsat: 150500},
+		},
+	}
+
+	service := NewService(transport, []Node{node}, language.English)
+	service.Start()
+
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		standIn.mu.Lock()
+		sent := len(standIn.sent)
+		standIn.mu.Unlock()
+		if sent >= 2 || time.Now().After(deadline) {
+			break
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	service.Stop()
+
+	if len(standIn.sent) != 2 {
+		t.Fatalf("sendMessage called %d times; want 2", len(standIn.sent))
+	}
+
+	balance := standIn.sent[0]["text"]
+	for _, want := range []string{"On-chain: 1,500,000 sats", "Local: 200,000 sats", "Remote: 800,000 sats"} {
+		if !strings.Contains(balance, want) {
+			t.Errorf("/balance response %q does not contain %q", balance, want)
+		}
+	}
+
+	fees := standIn.sent[1]["text"]
+	for _, want := range []string{"last 7d", "Forwards: 2", "Volume: 2,000,000 sats", "Earned: 250.5 sats (125 ppm)"} {
+		if !strings.Contains(fees, want) {
+			t.Errorf("/fees response %q does not contain %q", fees, want)
+		}
+	}
+}
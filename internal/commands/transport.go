@@ -0,0 +1,23 @@
+package commands
+
+import (
+	"fmt"
+	"net/url"
+
+	"github.com/Primexz/lndnotify/internal/config"
+)
+
+// NewTransport creates the command transport for the URL of the configured provider
+func NewTransport(cfg config.CommandsConfig, providerURL string) (Transport, error) {
+	serviceURL, err := url.Parse(providerURL)
+	if err != nil {
+		return nil, fmt.Errorf("parsing provider URL: %w", err)
+	}
+
+	switch serviceURL.Scheme {
+	case "telegram":
+		return NewTelegramTransport(providerURL, cfg.APIURL, cfg.PollTimeout)
+	default:
+		return nil, fmt.Errorf("commands are not supported for %s providers", serviceURL.Scheme)
+	}
+}
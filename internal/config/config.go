@@ -2,20 +2,148 @@ package config
 
 import (
 	"fmt"
+	"net/url"
 	"os"
+	"strings"
 	"time"
 
+	"filippo.io/age"
 	"golang.org/x/text/language"
 	"gopkg.in/yaml.v3"
 )
 
 // Config represents the root configuration structure
 type Config struct {
-	LND           LNDConfig          `yaml:"lnd" validate:"required"`
+	LND           LNDConfig          `yaml:"lnd"`
+	Nodes         []NodeConfig       `yaml:"nodes"`
 	Notifications NotificationConfig `yaml:"notifications" validate:"required"`
 	Events        EventFlags         `yaml:"events"`
 	EventConfig   EventConfig        `yaml:"event_config"`
 	LogLevel      string             `yaml:"log_level" validate:"omitempty,oneof=panic fatal error warn info debug trace"`
+	StateDir      string             `yaml:"state_dir"`
+	Metrics       MetricsConfig      `yaml:"metrics"`
+	Commands      CommandsConfig     `yaml:"commands"`
+	WatchConfig   bool               `yaml:"watch_config"`
+	Fiat          FiatConfig         `yaml:"fiat"`
+	Throttle      ThrottleConfig     `yaml:"throttle"`
+	MQTT          MQTTConfig         `yaml:"mqtt"`
+	API           APIConfig          `yaml:"api"`
+	Backup        BackupConfig       `yaml:"backup"`
+
+	// Filters holds an expression per event type. Events are only sent if it evaluates to true.
+	Filters map[string]string `yaml:"filters"`
+}
+
+// MetricsConfig holds the Prometheus metrics settings
+type MetricsConfig struct {
+	ListenAddress string `yaml:"listen_address"`
+}
+
+// FiatConfig holds the settings of the fiat conversion of sat amounts. It is disabled if no currency is set.
+type FiatConfig struct {
+	Currency string `yaml:"currency"`
+
+	// Source is "mempool" for a mempool.space compatible price API or "fixed" for a fixed rate
+	Source          string        `yaml:"source"`
+	URL             string        `yaml:"url"`
+	Rate            float64       `yaml:"rate"`
+	RefreshInterval time.Duration `yaml:"refresh_interval"`
+}
+
+// ThrottleConfig holds the deduplication and rate limits of events. Rates are in events per minute, 0 is unlimited.
+type ThrottleConfig struct {
+	Enabled bool `yaml:"enabled"`
+
+	// Window is the default suppression window of duplicate events
+	Window time.Duration `yaml:"window"`
+	Rate   float64       `yaml:"rate"`
+	Burst  int           `yaml:"burst"`
+
+	Events map[string]EventThrottleConfig `yaml:"events"`
+}
+
+// EventThrottleConfig holds the deduplication keys and the rate limit of an event type.
+// Events with the same values of the key template variables are duplicates.
+type EventThrottleConfig struct {
+	Keys   []string      `yaml:"keys"`
+	Window time.Duration `yaml:"window"`
+	Rate   float64       `yaml:"rate"`
+	Burst  int           `yaml:"burst"`
+}
+
+// defaultThrottleKeys are the deduplication keys of event types that tend to repeat. They
+// apply to event types without configured keys, also if the event type is not configured.
+var defaultThrottleKeys = map[string][]string{
+	"failed_htlc_event":         {"InChanId", "OutChanId", "WireFailure"},
+	"peer_online_event":         {"PeerPubKey"},
+	"peer_offline_event":        {"PeerPubKey"},
+	"channel_status_up_event":   {"ChannelPoint"},
+	"channel_status_down_event": {"ChannelPoint"},
+}
+
+// APIConfig holds the settings of the HTTP status API. It is disabled if no listen address is set.
+type APIConfig struct {
+	ListenAddress string `yaml:"listen_address"`
+	// HistorySize is the number of recent events kept for /api/events
+	HistorySize int `yaml:"history_size"`
+}
+
+// BackupConfig holds the archival of channel backups. Each storage is disabled if not configured.
+type BackupConfig struct {
+	Local BackupLocalConfig `yaml:"local"`
+	S3    BackupS3Config    `yaml:"s3"`
+
+	// KeepLast is the number of most recent backups kept in each storage
+	KeepLast *int `yaml:"keep_last"`
+	// KeepDaily is the number of days whose last backup is kept in addition to the most recent ones
+	KeepDaily *int `yaml:"keep_daily"`
+}
+
+// BackupLocalConfig holds the directory channel backups are archived to
+type BackupLocalConfig struct {
+	Directory string `yaml:"directory"`
+}
+
+// BackupS3Config holds the S3-compatible bucket channel backups are archived to
+type BackupS3Config struct {
+	// Endpoint is the URL of the S3 API, e.g. https://s3.eu-central-1.amazonaws.com or http://localhost:9000 for MinIO
+	Endpoint        string `yaml:"endpoint"`
+	Bucket          string `yaml:"bucket"`
+	Prefix          string `yaml:"prefix"`
+	Region          string `yaml:"region"`
+	AccessKeyID     string `yaml:"access_key_id"`
+	SecretAccessKey string `yaml:"secret_access_key"`
+}
+
+// MQTTConfig holds the settings of the MQTT publisher. It is disabled if no broker is set.
+type MQTTConfig struct {
+	// Broker is the URL of the broker, e.g. mqtt://localhost:1883 or mqtts://broker:8883 for TLS
+	Broker      string        `yaml:"broker"`
+	ClientID    string        `yaml:"client_id"`
+	Username    string        `yaml:"username"`
+	Password    string        `yaml:"password"`
+	TopicPrefix string        `yaml:"topic_prefix"`
+	QoS         byte          `yaml:"qos"`
+	TLS         MQTTTLSConfig `yaml:"tls"`
+}
+
+// MQTTTLSConfig holds the TLS settings of the MQTT connection. CertFile and KeyFile are
+// used for client certificate authentication.
+type MQTTTLSConfig struct {
+	CAFile             string `yaml:"ca_file"`
+	CertFile           string `yaml:"cert_file"`
+	KeyFile            string `yaml:"key_file"`
+	InsecureSkipVerify bool   `yaml:"insecure_skip_verify"`
+}
+
+// CommandsConfig holds the settings of the interactive chat commands
+type CommandsConfig struct {
+	Enabled bool `yaml:"enabled"`
+
+	// Provider is the name of the notification provider whose chat accepts commands
+	Provider    string        `yaml:"provider"`
+	APIURL      string        `yaml:"api_url"`
+	PollTimeout time.Duration `yaml:"poll_timeout"`
 }
 
 // LNDConfig holds the LND node connection settings
@@ -26,12 +154,23 @@ type LNDConfig struct {
 	MacaroonPath string `yaml:"macaroon_path" validate:"required,file"`
 }
 
+// NodeConfig holds the connection settings of one of multiple monitored LND nodes
+type NodeConfig struct {
+	Name      string `yaml:"name"`
+	LNDConfig `yaml:",inline"`
+
+	// RawEvents optionally overrides single event flags of the global events section
+	RawEvents yaml.Node  `yaml:"events"`
+	Events    EventFlags `yaml:"-"`
+}
+
 // NotificationConfig holds notification service settings
 type NotificationConfig struct {
 	Providers  []ProviderConfig     `yaml:"providers" validate:"required,min=1"`
 	Templates  NotificationTemplate `yaml:"templates"`
 	Formatting Formatting           `yaml:"formatting"`
 	Batching   BatchingConfig       `yaml:"batching"`
+	Outbox     OutboxConfig         `yaml:"outbox"`
 }
 
 // BatchingConfig holds batching configuration
@@ -41,6 +180,15 @@ type BatchingConfig struct {
 	MaxSize       int           `yaml:"max_size"`
 }
 
+// OutboxConfig holds the settings of the persistent notification outbox
+type OutboxConfig struct {
+	Enabled         bool          `yaml:"enabled"`
+	DataDir         string        `yaml:"data_dir"`
+	MaxAttempts     int           `yaml:"max_attempts"`
+	InitialInterval time.Duration `yaml:"initial_interval"`
+	MaxInterval     time.Duration `yaml:"max_interval"`
+}
+
 type Formatting struct {
 	Locale LanguageTag `yaml:"locale"`
 }
@@ -49,6 +197,55 @@ type Formatting struct {
 type ProviderConfig struct {
 	URL  string `yaml:"url" validate:"required"`
 	Name string `yaml:"name"`
+	// Type is "shoutrrr" (default) for a shoutrrr service URL or "webhook" to POST JSON events to the URL.
+	Type string `yaml:"type"`
+
+	// Events limits the provider to the listed event types. If empty, all events are sent.
+	Events []string `yaml:"events"`
+	// ExcludeEvents lists event types that are never sent to the provider.
+	ExcludeEvents []string `yaml:"exclude_events"`
+	// Filters holds an expression per event type that must evaluate to true for the event to be sent to the provider.
+	Filters map[string]string `yaml:"filters"`
+	// MinSeverity is the lowest severity (info, warning or critical) of events sent to the provider.
+	MinSeverity string `yaml:"min_severity"`
+	// SeverityPriority maps the severity of events to the priority on ntfy and Pushover and to
+	// silent notifications on Telegram. Params set in the URL take precedence.
+	SeverityPriority bool `yaml:"severity_priority"`
+	// QuietHours holds notifications back during the configured periods.
+	QuietHours QuietHoursConfig `yaml:"quiet_hours"`
+	// Webhook holds the settings of webhook providers.
+	Webhook WebhookConfig `yaml:"webhook"`
+}
+
+const (
+	ProviderTypeShoutrrr = "shoutrrr"
+	ProviderTypeWebhook  = "webhook"
+)
+
+// WebhookConfig holds the settings of a webhook provider. If Secret is set, the body is signed
+// with HMAC-SHA256. Failed requests are retried up to MaxAttempts times in total.
+type WebhookConfig struct {
+	Secret      string            `yaml:"secret"`
+	Headers     map[string]string `yaml:"headers"`
+	Timeout     time.Duration     `yaml:"timeout"`
+	MaxAttempts int               `yaml:"max_attempts"`
+}
+
+// QuietHoursConfig holds the do-not-disturb schedule of a provider. During quiet hours, notifications
+// are held and sent as one message when the quiet hours end. Critical events and BypassEvents are
+// always sent immediately.
+type QuietHoursConfig struct {
+	Timezone     string             `yaml:"timezone"`
+	Schedule     []QuietHoursPeriod `yaml:"schedule"`
+	BypassEvents []string           `yaml:"bypass_events"`
+}
+
+// QuietHoursPeriod is a daily period of quiet hours. If End is before Start, the period ends on the
+// next day. Days lists the weekdays the period starts on, every day if empty.
+type QuietHoursPeriod struct {
+	Days  []string `yaml:"days"`
+	Start string   `yaml:"start"`
+	End   string   `yaml:"end"`
 }
 
 // NotificationTemplate holds customizable message templates
@@ -64,7 +261,9 @@ type NotificationTemplate struct {
 	ChannelStatusUp      string `yaml:"channel_status_up_event"`
 	ChannelStatusDown    string `yaml:"channel_status_down_event"`
 	FailedHtlc           string `yaml:"failed_htlc_event"`
+	FailedHtlcDigest     string `yaml:"failed_htlc_digest_event"`
 	Forward              string `yaml:"forward_event"`
+	ForwardDigest        string `yaml:"forward_digest_event"`
 	Healthy              string `yaml:"healthy_event"`
 	Unhealthy            string `yaml:"unhealthy_event"`
 	InvoiceSettled       string `yaml:"invoice_settled_event"`
@@ -80,6 +279,9 @@ type NotificationTemplate struct {
 	LndUpdateAvailable   string `yaml:"lnd_update_available_event"`
 	HTLCExpiration       string `yaml:"htlc_expiration_event"`
 	AliasChanged         string `yaml:"alias_changed_event"`
+	NodeSummary          string `yaml:"node_summary_event"`
+	LiquidityImbalance   string `yaml:"liquidity_imbalance_event"`
+	LiquidityRecovered   string `yaml:"liquidity_recovered_event"`
 }
 
 // EventFlags controls which events to monitor (feature flags)
@@ -104,16 +306,13 @@ type EventFlags struct {
 	LndUpdateEvents      bool `yaml:"lnd_update_events"`
 	HTLCExpirationEvents bool `yaml:"htlc_expiration_events"`
 	AliasChangedEvents   bool `yaml:"alias_changed_events"`
+	NodeSummaryEvents    bool `yaml:"node_summary_events"`
+	LiquidityEvents      bool `yaml:"liquidity_events"`
 }
 
 // EventConfig contains specific configuration for each event type
 type EventConfig struct {
-	FailedHtlcEvent struct {
-		MinAmount uint64 `yaml:"min_amount"`
-	} `yaml:"failed_htlc_event"`
-	ForwardEvent struct {
-		MinAmount uint64 `yaml:"min_amount"`
-	} `yaml:"forward_event"`
+	ForwardEvent ForwardEventConfig `yaml:"forward_event"`
 	InvoiceEvent struct {
 		MinAmount   uint64 `yaml:"min_amount"`
 		SkipKeysend *bool  `yaml:"skip_keysend"`
@@ -141,6 +340,133 @@ type EventConfig struct {
 	HTLCExpirationEvent struct {
 		RemainingBlocks int32 `yaml:"remaining_blocks"`
 	} `yaml:"htlc_expiration_event"`
+	FailedHtlcEvent  FailedHtlcEventConfig  `yaml:"failed_htlc_event"`
+	NodeSummaryEvent NodeSummaryEventConfig `yaml:"node_summary_event"`
+	LiquidityEvent   LiquidityEventConfig   `yaml:"liquidity_event"`
+	BackupEvent      BackupEventConfig      `yaml:"backup_multi_event"`
+}
+
+// BackupEventConfig holds the encryption of the channel backup attached to backup notifications.
+// If age recipients or a passphrase are set, the attachment is encrypted with age.
+type BackupEventConfig struct {
+	// Recipients are age public keys (age1...) that can decrypt the attachment
+	Recipients []string `yaml:"recipients"`
+	Passphrase string   `yaml:"passphrase"`
+}
+
+// Encrypted reports whether backup attachments are encrypted
+func (c BackupEventConfig) Encrypted() bool {
+	return len(c.Recipients) > 0 || c.Passphrase != ""
+}
+
+// FailedHtlcEventConfig holds the settings of failed HTLC notifications. Mode is "individual" for one
+// notification per failure, "digest" for a single digest per DigestInterval or "both".
+type FailedHtlcEventConfig struct {
+	MinAmount      uint64        `yaml:"min_amount"`
+	Mode           string        `yaml:"mode"`
+	DigestInterval time.Duration `yaml:"digest_interval"`
+}
+
+// Individual reports whether a notification is sent for every failed HTLC
+func (c FailedHtlcEventConfig) Individual() bool {
+	return c.Mode != "digest"
+}
+
+// Digest reports whether failed HTLCs are collected into a digest
+func (c FailedHtlcEventConfig) Digest() bool {
+	return c.Mode == "digest" || c.Mode == "both"
+}
+
+// ForwardEventConfig holds the settings of forward notifications. Mode is "individual" for one
+// notification per forward, "digest" for a single digest per DigestInterval or "both".
+type ForwardEventConfig struct {
+	MinAmount      uint64        `yaml:"min_amount"`
+	Mode           string        `yaml:"mode"`
+	DigestInterval time.Duration `yaml:"digest_interval"`
+}
+
+// Individual reports whether a notification is sent for every forward
+func (c ForwardEventConfig) Individual() bool {
+	return c.Mode != "digest"
+}
+
+// Digest reports whether forwards are collected into a digest
+func (c ForwardEventConfig) Digest() bool {
+	return c.Mode == "digest" || c.Mode == "both"
+}
+
+// NodeSummaryEventConfig holds the schedule of the node summary report
+type NodeSummaryEventConfig struct {
+	Schedule string `yaml:"schedule" validate:"omitempty,oneof=daily weekly"`
+	Time     string `yaml:"time"`
+	Weekday  string `yaml:"weekday"`
+	Timezone string `yaml:"timezone"`
+}
+
+// Location returns the time zone of the schedule. An empty timezone is the local time of the host.
+func (c NodeSummaryEventConfig) Location() (*time.Location, error) {
+	if c.Timezone == "" {
+		return time.Local, nil
+	}
+	return time.LoadLocation(c.Timezone)
+}
+
+// LiquidityEventConfig holds the local balance thresholds of the liquidity alerts in percent
+// of the channel balance. Channels can override the global thresholds by channel id or peer.
+type LiquidityEventConfig struct {
+	MinLocalPercent   *float64                 `yaml:"min_local_percent"`
+	MaxLocalPercent   *float64                 `yaml:"max_local_percent"`
+	HysteresisPercent *float64                 `yaml:"hysteresis_percent"`
+	Overrides         []LiquidityThresholdRule `yaml:"overrides"`
+}
+
+// LiquidityThresholdRule overrides the liquidity thresholds of a channel or of all channels with a peer
+type LiquidityThresholdRule struct {
+	ChanId          uint64   `yaml:"chan_id"`
+	PeerPubkey      string   `yaml:"peer_pubkey"`
+	MinLocalPercent *float64 `yaml:"min_local_percent"`
+	MaxLocalPercent *float64 `yaml:"max_local_percent"`
+}
+
+// Thresholds returns the minimum and maximum local balance percent of a channel.
+// Channel id rules take precedence over peer rules, which take precedence over the global thresholds.
+func (c LiquidityEventConfig) Thresholds(chanId uint64, peerPubkey string) (float64, float64) {
+	minLocal, maxLocal := *c.MinLocalPercent, *c.MaxLocalPercent
+
+	apply := func(rule LiquidityThresholdRule) {
+		if rule.MinLocalPercent != nil {
+			minLocal = *rule.MinLocalPercent
+		}
+		if rule.MaxLocalPercent != nil {
+			maxLocal = *rule.MaxLocalPercent
+		}
+	}
+
+	for _, rule := range c.Overrides {
+		if rule.ChanId == 0 && rule.PeerPubkey == peerPubkey {
+			apply(rule)
+		}
+	}
+	for _, rule := range c.Overrides {
+		if rule.ChanId != 0 && rule.ChanId == chanId {
+			apply(rule)
+		}
+	}
+
+	return minLocal, maxLocal
+}
+
+// withDefaults returns the config with the default thresholds for the unset values
+func (c LiquidityEventConfig) withDefaults() LiquidityEventConfig {
+	setDefault := func(p **float64, value float64) {
+		if *p == nil {
+			*p = &value
+		}
+	}
+	setDefault(&c.MinLocalPercent, 10)
+	setDefault(&c.MaxLocalPercent, 90)
+	setDefault(&c.HysteresisPercent, 5)
+	return c
 }
 
 // LoadConfig loads configuration from a YAML file
@@ -161,36 +487,289 @@ func LoadConfig(path string) (*Config, error) {
 	}
 
 	cfg.setDefaults()
+
+	if err := cfg.resolveNodes(); err != nil {
+		return nil, fmt.Errorf("validating config: %w", err)
+	}
 	return &cfg, nil
 }
 
 // Validate the configuration fields
 func (c *Config) validate() error {
 	// Basic validation
-	if c.LND.Host == "" {
+	if len(c.Nodes) == 0 {
+		if err := c.LND.validate(); err != nil {
+			return err
+		}
+	}
+	names := make(map[string]struct{})
+	for _, node := range c.Nodes {
+		if node.Name == "" {
+			return fmt.Errorf("node name is required")
+		}
+		if _, exists := names[node.Name]; exists {
+			return fmt.Errorf("duplicate node name %q", node.Name)
+		}
+		names[node.Name] = struct{}{}
+
+		if err := node.LNDConfig.validate(); err != nil {
+			return fmt.Errorf("node %q: %w", node.Name, err)
+		}
+	}
+	if len(c.Notifications.Providers) == 0 {
+		return fmt.Errorf("at least one notification provider is required")
+	}
+	for _, p := range c.Notifications.Providers {
+		if p.URL == "" {
+			return fmt.Errorf("notification provider URL is required")
+		}
+		switch p.Type {
+		case "", ProviderTypeShoutrrr:
+		case ProviderTypeWebhook:
+			if !strings.HasPrefix(p.URL, "http://") && !strings.HasPrefix(p.URL, "https://") {
+				return fmt.Errorf("provider %q: webhook URL must be an http or https URL", p.Name)
+			}
+		default:
+			return fmt.Errorf("provider %q: unknown provider type: %s", p.Name, p.Type)
+		}
+		if err := p.QuietHours.validate(); err != nil {
+			return fmt.Errorf("provider %q: %w", p.Name, err)
+		}
+	}
+
+	if c.Commands.Enabled {
+		if c.Commands.Provider == "" {
+			return fmt.Errorf("commands provider is required")
+		}
+		provider, ok := c.Notifications.Provider(c.Commands.Provider)
+		if !ok {
+			return fmt.Errorf("commands provider %q not found", c.Commands.Provider)
+		}
+		if provider.Type == ProviderTypeWebhook {
+			return fmt.Errorf("commands provider %q must not be a webhook", c.Commands.Provider)
+		}
+	}
+
+	if c.Throttle.Rate < 0 || c.Throttle.Window < 0 {
+		return fmt.Errorf("throttle rate and window must not be negative")
+	}
+	for name, event := range c.Throttle.Events {
+		if event.Rate < 0 || event.Window < 0 {
+			return fmt.Errorf("throttle rate and window of %s must not be negative", name)
+		}
+	}
+
+	if c.Fiat.Currency != "" {
+		switch c.Fiat.Source {
+		case "", "mempool":
+		case "fixed":
+			if c.Fiat.Rate <= 0 {
+				return fmt.Errorf("fiat rate must be greater than 0 for the fixed source")
+			}
+		default:
+			return fmt.Errorf("fiat source must be mempool or fixed")
+		}
+	}
+
+	if c.API.HistorySize < 0 {
+		return fmt.Errorf("api history size must not be negative")
+	}
+
+	if c.MQTT.Broker != "" {
+		u, err := url.Parse(c.MQTT.Broker)
+		if err != nil {
+			return fmt.Errorf("invalid mqtt broker URL: %w", err)
+		}
+		switch u.Scheme {
+		case "mqtt", "tcp", "mqtts", "ssl", "tls", "ws", "wss":
+		default:
+			return fmt.Errorf("mqtt broker URL must use mqtt, mqtts, tcp, ssl, tls, ws or wss")
+		}
+		if c.MQTT.QoS > 2 {
+			return fmt.Errorf("mqtt qos must be 0, 1 or 2")
+		}
+		if (c.MQTT.TLS.CertFile == "") != (c.MQTT.TLS.KeyFile == "") {
+			return fmt.Errorf("mqtt tls cert_file and key_file must be set together")
+		}
+	}
+
+	if c.Backup.S3.Endpoint != "" {
+		u, err := url.Parse(c.Backup.S3.Endpoint)
+		if err != nil {
+			return fmt.Errorf("invalid backup s3 endpoint: %w", err)
+		}
+		if u.Scheme != "http" && u.Scheme != "https" {
+			return fmt.Errorf("backup s3 endpoint must use http or https")
+		}
+		if c.Backup.S3.Bucket == "" {
+			return fmt.Errorf("backup s3 bucket is required")
+		}
+	}
+	keepLast, keepDaily := c.Backup.KeepLast, c.Backup.KeepDaily
+	if (keepLast != nil && *keepLast < 0) || (keepDaily != nil && *keepDaily < 0) {
+		return fmt.Errorf("backup keep_last and keep_daily must not be negative")
+	}
+	if keepLast != nil && *keepLast == 0 && keepDaily != nil && *keepDaily == 0 {
+		return fmt.Errorf("backup keep_last and keep_daily must not both be 0")
+	}
+
+	failedHtlc := c.EventConfig.FailedHtlcEvent
+	switch failedHtlc.Mode {
+	case "", "individual", "digest", "both":
+	default:
+		return fmt.Errorf("failed htlc mode must be individual, digest or both")
+	}
+	if failedHtlc.DigestInterval < 0 {
+		return fmt.Errorf("failed htlc digest interval must not be negative")
+	}
+	forward := c.EventConfig.ForwardEvent
+	switch forward.Mode {
+	case "", "individual", "digest", "both":
+	default:
+		return fmt.Errorf("forward mode must be individual, digest or both")
+	}
+	if forward.DigestInterval < 0 {
+		return fmt.Errorf("forward digest interval must not be negative")
+	}
+
+	summary := c.EventConfig.NodeSummaryEvent
+	if summary.Schedule != "" && summary.Schedule != "daily" && summary.Schedule != "weekly" {
+		return fmt.Errorf("node summary schedule must be daily or weekly")
+	}
+	if summary.Time != "" {
+		if _, err := time.Parse("15:04", summary.Time); err != nil {
+			return fmt.Errorf("invalid node summary time %q: %w", summary.Time, err)
+		}
+	}
+	if summary.Weekday != "" {
+		if _, err := ParseWeekday(summary.Weekday); err != nil {
+			return err
+		}
+	}
+	if _, err := summary.Location(); err != nil {
+		return fmt.Errorf("invalid node summary timezone %q: %w", summary.Timezone, err)
+	}
+
+	backup := c.EventConfig.BackupEvent
+	if len(backup.Recipients) > 0 && backup.Passphrase != "" {
+		return fmt.Errorf("backup encryption can use either recipients or a passphrase, not both")
+	}
+	for _, recipient := range backup.Recipients {
+		if _, err := age.ParseX25519Recipient(recipient); err != nil {
+			return fmt.Errorf("invalid backup encryption recipient %q: %w", recipient, err)
+		}
+	}
+
+	liquidity := c.EventConfig.LiquidityEvent.withDefaults()
+	if err := validateLiquidityThresholds(*liquidity.MinLocalPercent, *liquidity.MaxLocalPercent); err != nil {
+		return err
+	}
+	if *liquidity.HysteresisPercent < 0 || *liquidity.HysteresisPercent > 50 {
+		return fmt.Errorf("liquidity hysteresis must be between 0 and 50 percent")
+	}
+	for _, rule := range liquidity.Overrides {
+		if rule.ChanId == 0 && rule.PeerPubkey == "" {
+			return fmt.Errorf("liquidity override requires a chan_id or peer_pubkey")
+		}
+		// Overrides may only set one threshold, so validate the merged thresholds
+		if err := validateLiquidityThresholds(liquidity.Thresholds(rule.ChanId, rule.PeerPubkey)); err != nil {
+			return fmt.Errorf("liquidity override for chan_id %d peer_pubkey %q: %w", rule.ChanId, rule.PeerPubkey, err)
+		}
+	}
+
+	return nil
+}
+
+// Provider returns the provider with the given name
+func (c NotificationConfig) Provider(name string) (ProviderConfig, bool) {
+	for _, p := range c.Providers {
+		if p.Name == name {
+			return p, true
+		}
+	}
+	return ProviderConfig{}, false
+}
+
+func (c LNDConfig) validate() error {
+	if c.Host == "" {
 		return fmt.Errorf("LND host is required")
 	}
-	if c.LND.Port == 0 {
+	if c.Port == 0 {
 		return fmt.Errorf("LND port is required")
 	}
-	if c.LND.TLSCertPath == "" {
+	if c.TLSCertPath == "" {
 		return fmt.Errorf("LND TLS certificate path is required")
 	}
-	if c.LND.MacaroonPath == "" {
+	if c.MacaroonPath == "" {
 		return fmt.Errorf("LND macaroon path is required")
 	}
-	if len(c.Notifications.Providers) == 0 {
-		return fmt.Errorf("at least one notification provider is required")
+	return nil
+}
+
+// resolveNodes applies the global event flags and per-node overrides to every node.
+// A config without a nodes list monitors the single node of the lnd section.
+func (c *Config) resolveNodes() error {
+	if len(c.Nodes) == 0 {
+		c.Nodes = []NodeConfig{{LNDConfig: c.LND}}
 	}
-	for _, p := range c.Notifications.Providers {
-		if p.URL == "" {
-			return fmt.Errorf("notification provider URL is required")
+
+	for i := range c.Nodes {
+		node := &c.Nodes[i]
+		node.Events = c.Events
+		if node.RawEvents.IsZero() {
+			continue
+		}
+		if err := node.RawEvents.Decode(&node.Events); err != nil {
+			return fmt.Errorf("parsing events of node %q: %w", node.Name, err)
 		}
 	}
+	return nil
+}
 
+// ForNode returns a copy of the config with the connection settings and event flags of the given node
+func (c *Config) ForNode(node NodeConfig) *Config {
+	nodeCfg := *c
+	nodeCfg.LND = node.LNDConfig
+	nodeCfg.Events = node.Events
+	return &nodeCfg
+}
+
+func (q QuietHoursConfig) validate() error {
+	if _, err := time.LoadLocation(q.Timezone); err != nil {
+		return fmt.Errorf("invalid quiet hours timezone %q: %w", q.Timezone, err)
+	}
+	for _, period := range q.Schedule {
+		for _, t := range []string{period.Start, period.End} {
+			if _, err := time.Parse("15:04", t); err != nil {
+				return fmt.Errorf("invalid quiet hours time %q: %w", t, err)
+			}
+		}
+		if period.Start == period.End {
+			return fmt.Errorf("quiet hours start and end must differ")
+		}
+		for _, day := range period.Days {
+			if _, err := ParseWeekday(day); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func validateLiquidityThresholds(minLocal, maxLocal float64) error {
+	if minLocal < 0 || minLocal > 100 || maxLocal < 0 || maxLocal > 100 {
+		return fmt.Errorf("liquidity thresholds must be between 0 and 100 percent")
+	}
+	if minLocal >= maxLocal {
+		return fmt.Errorf("liquidity min_local_percent must be lower than max_local_percent")
+	}
 	return nil
 }
 
+// defaultNodePrefix starts every default template with the name of the node, which is only
+// set if a nodes list is configured
+const defaultNodePrefix = "{{with .NodeName}}[{{.}}] {{end}}"
+
 // Set default templates if not specified
 func (c *Config) setDefaults() {
 	if c.LogLevel == "" {
@@ -207,85 +786,100 @@ func (c *Config) setDefaults() {
 	}
 
 	if c.Notifications.Templates.BackupMulti == "" {
-		c.Notifications.Templates.BackupMulti = "❗️ Channel backup received for {{.NumChanPoints}} channels\n\nChannel Points:\n{{range .ChanPoints}}- {{.}}\n{{end}}\nFilename: {{.Filename}}\n SHA256: {{.Sha256Sum}}"
+		c.Notifications.Templates.BackupMulti = defaultNodePrefix + "❗️ Channel backup received for {{.NumChanPoints}} channels\n\n{{if not .Encrypted}}Channel Points:\n{{range .ChanPoints}}- {{.}}\n{{end}}\n{{end}}Filename: {{.Filename}}\n SHA256: {{.Sha256Sum}}{{if .Encrypted}}\n🔒 Encrypted with age{{end}}"
 	}
 	if c.Notifications.Templates.ChannelClose == "" {
-		c.Notifications.Templates.ChannelClose = "🔒 Channel closed with {{.PeerAlias}}\nCapacity {{.Capacity}} sats\nSettled balance {{.SettledBalance}} sats\n\nChannel Point: {{.ChannelPoint}}\nClose Type: {{if eq .CloseType 0}}🤝 Cooperatively {{if .CloseInitiator}}Local{{else}}Remote{{end}}{{else if eq .CloseType 1}}🔴 Force Local{{else if eq .CloseType 2}}🔴 Force Remote{{else if eq .CloseType 3}}🚨 Breach{{else}}💀 Other{{end}}"
+		c.Notifications.Templates.ChannelClose = defaultNodePrefix + "🔒 Channel closed with {{.PeerAlias}}\nCapacity {{.Capacity}} sats\nSettled balance {{.SettledBalance}} sats\n\nChannel Point: {{.ChannelPoint}}\nClose Type: {{if eq .CloseType 0}}🤝 Cooperatively {{if .CloseInitiator}}Local{{else}}Remote{{end}}{{else if eq .CloseType 1}}🔴 Force Local{{else if eq .CloseType 2}}🔴 Force Remote{{else if eq .CloseType 3}}🚨 Breach{{else}}💀 Other{{end}}"
 	}
 	if c.Notifications.Templates.ChannelClosing == "" {
-		c.Notifications.Templates.ChannelClosing = "⏳ Closing channel with {{.PeerAlias}}\nCapacity {{.Capacity}} sats\nLimbo: {{.LimboBalance}} sats\n\nClosing TxID: {{.ClosingTxid}}\nRaw TX: {{.ClosingTxHex}}"
+		c.Notifications.Templates.ChannelClosing = defaultNodePrefix + "⏳ Closing channel with {{.PeerAlias}}\nCapacity {{.Capacity}} sats\nLimbo: {{.LimboBalance}} sats\n\nClosing TxID: {{.ClosingTxid}}\nRaw TX: {{.ClosingTxHex}}"
 	}
 	if c.Notifications.Templates.ChannelFeeChange == "" {
-		c.Notifications.Templates.ChannelFeeChange = "✏️ Fee change detected on channel with {{.PeerAlias}} ({{.PeerPubkeyShort}})\nCapacity: {{.Capacity}} sats\n\nFee Rate: {{if ne .OldFeeRate .NewFeeRate}}{{.OldFeeRate}} -> {{.NewFeeRate}} ({{.FeeRateChange}} ppm, {{.FeeRateChangePercent}}){{else}}{{.OldFeeRate}}{{end}} ppm\nBase Fee: {{if ne .OldBaseFee .NewBaseFee}}{{.OldBaseFee}} -> {{.NewBaseFee}} ({{.BaseFeeChange}} sats, {{.BaseFeeChangePercent}}){{else}}{{.OldBaseFee}}{{end}} sats\nInbound Fee Rate: {{if ne .OldInboundFeeRate .NewInboundFeeRate}}{{.OldInboundFeeRate}} -> {{.NewInboundFeeRate}} ({{.InboundFeeRateChange}} ppm, {{.InboundFeeRateChangePercent}}){{else}}{{.OldInboundFeeRate}}{{end}} ppm\nInbound Base Fee: {{if ne .OldInboundBaseFee .NewInboundBaseFee}}{{.OldInboundBaseFee}} -> {{.NewInboundBaseFee}} ({{.InboundBaseFeeChange}} sats, {{.InboundBaseFeeChangePercent}}){{else}}{{.OldInboundBaseFee}}{{end}} sats"
+		c.Notifications.Templates.ChannelFeeChange = defaultNodePrefix + "✏️ Fee change detected on channel with {{.PeerAlias}} ({{.PeerPubkeyShort}})\nCapacity: {{.Capacity}} sats\n\nFee Rate: {{if ne .OldFeeRate .NewFeeRate}}{{.OldFeeRate}} -> {{.NewFeeRate}} ({{.FeeRateChange}} ppm, {{.FeeRateChangePercent}}){{else}}{{.OldFeeRate}}{{end}} ppm\nBase Fee: {{if ne .OldBaseFee .NewBaseFee}}{{.OldBaseFee}} -> {{.NewBaseFee}} ({{.BaseFeeChange}} sats, {{.BaseFeeChangePercent}}){{else}}{{.OldBaseFee}}{{end}} sats\nInbound Fee Rate: {{if ne .OldInboundFeeRate .NewInboundFeeRate}}{{.OldInboundFeeRate}} -> {{.NewInboundFeeRate}} ({{.InboundFeeRateChange}} ppm, {{.InboundFeeRateChangePercent}}){{else}}{{.OldInboundFeeRate}}{{end}} ppm\nInbound Base Fee: {{if ne .OldInboundBaseFee .NewInboundBaseFee}}{{.OldInboundBaseFee}} -> {{.NewInboundBaseFee}} ({{.InboundBaseFeeChange}} sats, {{.InboundBaseFeeChangePercent}}){{else}}{{.OldInboundBaseFee}}{{end}} sats"
 	}
 	if c.Notifications.Templates.ChannelOpen == "" {
-		c.Notifications.Templates.ChannelOpen = "🚀 Channel opened with {{.PeerAlias}}\nCapacity {{.Capacity}} sats"
+		c.Notifications.Templates.ChannelOpen = defaultNodePrefix + "🚀 Channel opened with {{.PeerAlias}}\nCapacity {{.Capacity}} sats"
 	}
 	if c.Notifications.Templates.ChannelOpening == "" {
-		c.Notifications.Templates.ChannelOpening = "{{if .Initiator}}⏳ Opening new {{.Capacity}} sats channel to {{.PeerAlias}}{{else}}⏳ Accepting new {{.Capacity}} sats channel from {{.PeerAlias}}{{end}}"
+		c.Notifications.Templates.ChannelOpening = defaultNodePrefix + "{{if .Initiator}}⏳ Opening new {{.Capacity}} sats channel to {{.PeerAlias}}{{else}}⏳ Accepting new {{.Capacity}} sats channel from {{.PeerAlias}}{{end}}"
 	}
 	if c.Notifications.Templates.FailedHtlc == "" {
-		c.Notifications.Templates.FailedHtlc = "❌ Failed HTLC of {{.Amount}} sats\n{{.InChanAlias}} -> {{.OutChanAlias}}\nReason: {{.WireFailure}} ({{.FailureDetail}})\nActual Outbound: {{.OutChanLiquidity}} sats\nMissed Fee: {{.MissedFee}} sats\nLocal liquidity failure: {{if .IsLocalLiquidityFailure}}✅{{else}}❌{{end}}"
+		c.Notifications.Templates.FailedHtlc = defaultNodePrefix + "❌ Failed HTLC of {{.Amount}} sats\n{{.InChanAlias}} -> {{.OutChanAlias}}\nReason: {{.WireFailure}} ({{.FailureDetail}})\nActual Outbound: {{.OutChanLiquidity}} sats\nMissed Fee: {{.MissedFee}} sats\nLocal liquidity failure: {{if .IsLocalLiquidityFailure}}✅{{else}}❌{{end}}"
 	}
 	if c.Notifications.Templates.Forward == "" {
-		c.Notifications.Templates.Forward = "💰 Forwarded {{.Amount}} sats\n{{.PeerAliasIn}} -> {{.PeerAliasOut}}\nEarned {{.Fee}} sats ({{.FeeRate}} ppm)"
+		c.Notifications.Templates.Forward = defaultNodePrefix + "💰 Forwarded {{.Amount}} sats{{if .AmountFiat}} (≈ {{.AmountFiat}}){{end}}\n{{.PeerAliasIn}} -> {{.PeerAliasOut}}\nEarned {{.Fee}} sats ({{.FeeRate}} ppm)"
 	}
 	if c.Notifications.Templates.InvoiceSettled == "" {
-		c.Notifications.Templates.InvoiceSettled = "💵 Invoice settled: {{or .Memo \"No Memo\"}} for {{.Value}} sats"
+		c.Notifications.Templates.InvoiceSettled = defaultNodePrefix + "💵 Invoice settled: {{or .Memo \"No Memo\"}} for {{.Value}} sats{{if .ValueFiat}} (≈ {{.ValueFiat}}){{end}}"
 	}
 	if c.Notifications.Templates.Keysend == "" {
-		c.Notifications.Templates.Keysend = "📨 Keysend received:\n\n{{.Msg}}\n\nChannel In: {{.InChanAlias}} ({{.InChanId}})"
+		c.Notifications.Templates.Keysend = defaultNodePrefix + "📨 Keysend received:\n\n{{.Msg}}\n\nChannel In: {{.InChanAlias}} ({{.InChanId}})"
 	}
 	if c.Notifications.Templates.ChainSyncLost == "" {
-		c.Notifications.Templates.ChainSyncLost = "⚠️ Chain is out of sync since {{.Duration}}"
+		c.Notifications.Templates.ChainSyncLost = defaultNodePrefix + "⚠️ Chain is out of sync since {{.Duration}}"
 	}
 	if c.Notifications.Templates.ChainSyncRestored == "" {
-		c.Notifications.Templates.ChainSyncRestored = "✅ Chain is back in sync after {{.Duration}}"
+		c.Notifications.Templates.ChainSyncRestored = defaultNodePrefix + "✅ Chain is back in sync after {{.Duration}}"
 	}
 	if c.Notifications.Templates.OnChainMempool == "" {
-		c.Notifications.Templates.OnChainMempool = "🔗 Discovered On-Chain transaction in mempool: {{.Amount}} sats\nFee: {{.TotalFees}} sats\n\nOutputs:\n{{range .Outputs}}- {{.Amount}} sats to {{.Address}} ({{.OutputType}}{{if .IsOurAddress}}, ours{{end}})\n{{end}}\nView on explorer: {{.TransactionURL}}\nTxID: {{.TxHash}}"
+		c.Notifications.Templates.OnChainMempool = defaultNodePrefix + "🔗 Discovered On-Chain transaction in mempool: {{.Amount}} sats\nFee: {{.TotalFees}} sats\n\nOutputs:\n{{range .Outputs}}- {{.Amount}} sats to {{.Address}} ({{.OutputType}}{{if .IsOurAddress}}, ours{{end}})\n{{end}}\nView on explorer: {{.TransactionURL}}\nTxID: {{.TxHash}}"
 	}
 	if c.Notifications.Templates.OnChainConfirmed == "" {
-		c.Notifications.Templates.OnChainConfirmed = "🔗 Confirmed On-Chain transaction: {{.Amount}} sats\nFee: {{.TotalFees}} sats\n\nView on explorer: {{.TransactionURL}}\nTxID: {{.TxHash}}"
+		c.Notifications.Templates.OnChainConfirmed = defaultNodePrefix + "🔗 Confirmed On-Chain transaction: {{.Amount}} sats\nFee: {{.TotalFees}} sats\n\nView on explorer: {{.TransactionURL}}\nTxID: {{.TxHash}}"
 	}
 	if c.Notifications.Templates.PaymentSucceeded == "" {
-		c.Notifications.Templates.PaymentSucceeded = "⚡️ Payment: {{.Amount}} sats (fee: {{.Fee}}) to {{.Receiver}}{{if .Memo}} - {{.Memo}}{{end}}{{range .HtlcInfo}}\n  HTLC: {{.Amount}} via {{.FirstHop}} (fee: {{.Fee}}){{end}}\nHash: {{.PaymentHash}}"
+		c.Notifications.Templates.PaymentSucceeded = defaultNodePrefix + "⚡️ Payment: {{.Amount}} sats (fee: {{.Fee}}) to {{.Receiver}}{{if .Memo}} - {{.Memo}}{{end}}{{range .HtlcInfo}}\n  HTLC: {{.Amount}} via {{.FirstHop}} (fee: {{.Fee}}){{end}}\nHash: {{.PaymentHash}}"
 	}
 	if c.Notifications.Templates.PeerOffline == "" {
-		c.Notifications.Templates.PeerOffline = `{{if .PeerAlias}}⚠️ Peer {{.PeerAlias}} ({{.PeerPubkeyShort}}) went offline{{else}}⚠️ Peer {{.PeerPubKey}} went offline{{end}}`
+		c.Notifications.Templates.PeerOffline = defaultNodePrefix + `{{if .PeerAlias}}⚠️ Peer {{.PeerAlias}} ({{.PeerPubkeyShort}}) went offline{{else}}⚠️ Peer {{.PeerPubKey}} went offline{{end}}`
 	}
 	if c.Notifications.Templates.PeerOnline == "" {
-		c.Notifications.Templates.PeerOnline = `{{if .PeerAlias}}✅ Peer {{.PeerAlias}} ({{.PeerPubkeyShort}}) is online{{else}}✅ Peer {{.PeerPubKey}} is online{{end}}`
+		c.Notifications.Templates.PeerOnline = defaultNodePrefix + `{{if .PeerAlias}}✅ Peer {{.PeerAlias}} ({{.PeerPubkeyShort}}) is online{{else}}✅ Peer {{.PeerPubKey}} is online{{end}}`
 	}
 	if c.Notifications.Templates.RebalancingSucceeded == "" {
-		c.Notifications.Templates.RebalancingSucceeded = "{{range .HtlcInfo}}☯️ Rebalanced {{.Amount}} sats {{.FirstHop}} → {{.PenultHop}}\nFee: {{.Fee}} sats ({{.FeeRate}} ppm)\nRoute: {{range $i, $hop := .HopInfo}}{{if $i}} -> {{end}}{{$hop.Alias}} ({{$hop.FeeRate}} ppm){{end}}\n\n{{end}}"
+		c.Notifications.Templates.RebalancingSucceeded = defaultNodePrefix + "{{range .HtlcInfo}}☯️ Rebalanced {{.Amount}} sats {{.FirstHop}} → {{.PenultHop}}\nFee: {{.Fee}} sats ({{.FeeRate}} ppm)\nRoute: {{range $i, $hop := .HopInfo}}{{if $i}} -> {{end}}{{$hop.Alias}} ({{$hop.FeeRate}} ppm){{end}}\n\n{{end}}"
 	}
 	if c.Notifications.Templates.ChannelStatusUp == "" {
-		c.Notifications.Templates.ChannelStatusUp = "🟢 Channel with {{.PeerAlias}} ({{.PeerPubkeyShort}}) is back online after {{.Duration}}\nCapacity {{.Capacity}} sats"
+		c.Notifications.Templates.ChannelStatusUp = defaultNodePrefix + "🟢 Channel with {{.PeerAlias}} ({{.PeerPubkeyShort}}) is back online after {{.Duration}}\nCapacity {{.Capacity}} sats"
 	}
 	if c.Notifications.Templates.ChannelStatusDown == "" {
-		c.Notifications.Templates.ChannelStatusDown = "🔴 Channel with {{.PeerAlias}} ({{.PeerPubkeyShort}}) is down since {{.Duration}}\nCapacity {{.Capacity}} sats"
+		c.Notifications.Templates.ChannelStatusDown = defaultNodePrefix + "🔴 Channel with {{.PeerAlias}} ({{.PeerPubkeyShort}}) is down since {{.Duration}}\nCapacity {{.Capacity}} sats"
 	}
 	if c.Notifications.Templates.TLSCertExpiry == "" {
-		c.Notifications.Templates.TLSCertExpiry = "⚠️ LND TLS certificate is expiring soon on {{.ExpiryDate}} (in {{.TimeUntilExpiry}})"
+		c.Notifications.Templates.TLSCertExpiry = defaultNodePrefix + "⚠️ LND TLS certificate is expiring soon on {{.ExpiryDate}} (in {{.TimeUntilExpiry}})"
 	}
 	if c.Notifications.Templates.WalletState == "" {
-		c.Notifications.Templates.WalletState = "👛 Wallet state changed: {{.OldState}} -> {{.NewState}}"
+		c.Notifications.Templates.WalletState = defaultNodePrefix + "👛 Wallet state changed: {{.OldState}} -> {{.NewState}}"
 	}
 	if c.Notifications.Templates.Healthy == "" {
-		c.Notifications.Templates.Healthy = "✅ LND node is healthy again"
+		c.Notifications.Templates.Healthy = defaultNodePrefix + "✅ LND node is healthy again"
 	}
 	if c.Notifications.Templates.Unhealthy == "" {
-		c.Notifications.Templates.Unhealthy = "❌ LND node is unhealthy\nError: {{.Err}}"
+		c.Notifications.Templates.Unhealthy = defaultNodePrefix + "❌ LND node is unhealthy\nError: {{.Err}}"
 	}
 	if c.Notifications.Templates.LndUpdateAvailable == "" {
-		c.Notifications.Templates.LndUpdateAvailable = "⬆️ New LND version available: {{.LatestVersion}}\nYou are currently running version: {{.CurrentVersion}}"
+		c.Notifications.Templates.LndUpdateAvailable = defaultNodePrefix + "⬆️ New LND version available: {{.LatestVersion}}\nYou are currently running version: {{.CurrentVersion}}"
 	}
 	if c.Notifications.Templates.HTLCExpiration == "" {
-		c.Notifications.Templates.HTLCExpiration = "⏰ HTLC expiring soon: {{.RemainingBlocks}} blocks remaining (~{{.RemainingTime}})\n{{.PeerAlias}} ({{.PeerPubkeyShort}})\nHTLC Amount: {{.HTLCAmount}} sats"
+		c.Notifications.Templates.HTLCExpiration = defaultNodePrefix + "⏰ HTLC expiring soon: {{.RemainingBlocks}} blocks remaining (~{{.RemainingTime}})\n{{.PeerAlias}} ({{.PeerPubkeyShort}})\nHTLC Amount: {{.HTLCAmount}} sats"
 	}
 	if c.Notifications.Templates.AliasChanged == "" {
-		c.Notifications.Templates.AliasChanged = "📝 Alias changed: {{.OldAlias}} -> {{.NewAlias}}"
+		c.Notifications.Templates.AliasChanged = defaultNodePrefix + "📝 Alias changed: {{.OldAlias}} -> {{.NewAlias}}"
+	}
+	if c.Notifications.Templates.FailedHtlcDigest == "" {
+		c.Notifications.Templates.FailedHtlcDigest = defaultNodePrefix + "📉 Failed HTLC digest\n{{.Start}} - {{.End}}\n\n❌ {{.Count}} failed HTLCs ({{.Amount}} sats)\nMissed fees: {{.MissedFee}} sats{{if .MissedFeeFiat}} (≈ {{.MissedFeeFiat}}){{end}}\nLocal liquidity failures: {{.LocalLiquidityFailures}}{{range .Groups}}\n- {{.OutChanAlias}} ({{.FailureDetail}}): {{.Count}}x, {{.Amount}} sats, missed {{.MissedFee}} sats{{end}}"
+	}
+	if c.Notifications.Templates.ForwardDigest == "" {
+		c.Notifications.Templates.ForwardDigest = defaultNodePrefix + "💰 Forward digest\n{{.Start}} - {{.End}}\n\n{{.Count}} forwards ({{.Volume}} sats)\nEarned {{.Fees}} sats{{if .FeesFiat}} (≈ {{.FeesFiat}}){{end}} ({{.FeeRate}} ppm){{if .ChannelPairs}}\n{{range $i, $p := .ChannelPairs}}\n{{add $i 1}}. {{$p.InAlias}} -> {{$p.OutAlias}}: {{$p.Fees}} sats ({{$p.Count}} forwards, {{$p.Volume}} sats){{end}}{{end}}"
+	}
+	if c.Notifications.Templates.NodeSummary == "" {
+		c.Notifications.Templates.NodeSummary = defaultNodePrefix + "📊 {{.Period}} node summary\n{{.Start}} - {{.End}}\n\n💰 Forwards: {{.ForwardCount}} ({{.ForwardVolume}} sats)\nEarned {{.ForwardFees}} sats ({{.ForwardFeeRate}} ppm)\n☯️ Rebalancing: {{.RebalanceCount}} (cost {{.RebalanceCost}} sats)\nNet profit: {{.NetProfit}} sats\n\n💵 Invoices received: {{.InvoiceCount}} ({{.InvoiceAmount}} sats)\n⚡️ Payments sent: {{.PaymentCount}} ({{.PaymentAmount}} sats, fee {{.PaymentFees}} sats)\n🚀 Channels opened: {{.ChannelsOpened}}\n🔒 Channels closed: {{.ChannelsClosed}}\n🔗 On-chain balance: {{.OnChainBalance}} sats{{if .TopChannelPairs}}\n\nTop channel pairs:{{range .TopChannelPairs}}\n- {{.InAlias}} -> {{.OutAlias}}: {{.Fees}} sats ({{.Count}} forwards, {{.Volume}} sats){{end}}{{end}}"
+	}
+	if c.Notifications.Templates.LiquidityImbalance == "" {
+		c.Notifications.Templates.LiquidityImbalance = defaultNodePrefix + "{{if .LowLocal}}🪫 Low outbound liquidity{{else}}🔋 Low inbound liquidity{{end}} on channel with {{.PeerAlias}} ({{.PeerPubkeyShort}})\nLocal: {{.LocalBalance}} sats ({{.LocalPercent}}%)\nRemote: {{.RemoteBalance}} sats\nThresholds: {{.MinLocalPercent}}% - {{.MaxLocalPercent}}%"
+	}
+	if c.Notifications.Templates.LiquidityRecovered == "" {
+		c.Notifications.Templates.LiquidityRecovered = defaultNodePrefix + "⚖️ Liquidity recovered on channel with {{.PeerAlias}} ({{.PeerPubkeyShort}})\nLocal: {{.LocalBalance}} sats ({{.LocalPercent}}%)\nRemote: {{.RemoteBalance}} sats"
 	}
 
 	if c.EventConfig.ChainLostEvent.Threshold == 0 {
@@ -294,12 +888,34 @@ func (c *Config) setDefaults() {
 	if c.EventConfig.ChainLostEvent.WarningInterval == 0 {
 		c.EventConfig.ChainLostEvent.WarningInterval = 15 * time.Minute
 	}
+	if c.EventConfig.FailedHtlcEvent.Mode == "" {
+		c.EventConfig.FailedHtlcEvent.Mode = "individual"
+	}
+	if c.EventConfig.FailedHtlcEvent.DigestInterval == 0 {
+		c.EventConfig.FailedHtlcEvent.DigestInterval = time.Hour
+	}
+	if c.EventConfig.ForwardEvent.Mode == "" {
+		c.EventConfig.ForwardEvent.Mode = "individual"
+	}
+	if c.EventConfig.ForwardEvent.DigestInterval == 0 {
+		c.EventConfig.ForwardEvent.DigestInterval = time.Hour
+	}
 	if c.EventConfig.ChannelStatusEvent.MinDowntime == 0 {
 		c.EventConfig.ChannelStatusEvent.MinDowntime = 10 * time.Minute
 	}
 	if c.EventConfig.TLSCertExpiryEvent.Threshold == 0 {
 		c.EventConfig.TLSCertExpiryEvent.Threshold = 7 * 24 * time.Hour
 	}
+	if c.EventConfig.NodeSummaryEvent.Schedule == "" {
+		c.EventConfig.NodeSummaryEvent.Schedule = "daily"
+	}
+	if c.EventConfig.NodeSummaryEvent.Time == "" {
+		c.EventConfig.NodeSummaryEvent.Time = "08:00"
+	}
+	if c.EventConfig.NodeSummaryEvent.Weekday == "" {
+		c.EventConfig.NodeSummaryEvent.Weekday = "monday"
+	}
+	c.EventConfig.LiquidityEvent = c.EventConfig.LiquidityEvent.withDefaults()
 	if c.EventConfig.InvoiceEvent.SkipKeysend == nil {
 		defaultSkip := true
 		c.EventConfig.InvoiceEvent.SkipKeysend = &defaultSkip
@@ -312,4 +928,86 @@ func (c *Config) setDefaults() {
 	if c.Notifications.Batching.MaxSize == 0 {
 		c.Notifications.Batching.MaxSize = 10
 	}
+
+	// Set default commands configuration
+	if c.Commands.PollTimeout == 0 {
+		c.Commands.PollTimeout = 30 * time.Second
+	}
+
+	// Set default throttle configuration
+	if c.Throttle.Window == 0 {
+		c.Throttle.Window = 10 * time.Minute
+	}
+	if c.Throttle.Events == nil {
+		c.Throttle.Events = make(map[string]EventThrottleConfig)
+	}
+	for name, keys := range defaultThrottleKeys {
+		event := c.Throttle.Events[name]
+		if event.Keys == nil {
+			event.Keys = keys
+		}
+		c.Throttle.Events[name] = event
+	}
+
+	// Set default API configuration
+	if c.API.HistorySize == 0 {
+		c.API.HistorySize = 100
+	}
+
+	// Set default MQTT configuration
+	if c.MQTT.ClientID == "" {
+		c.MQTT.ClientID = "lndnotify"
+	}
+	if c.MQTT.TopicPrefix == "" {
+		c.MQTT.TopicPrefix = "lndnotify"
+	}
+
+	// Set default backup configuration
+	if c.Backup.KeepLast == nil {
+		defaultKeepLast := 10
+		c.Backup.KeepLast = &defaultKeepLast
+	}
+	if c.Backup.KeepDaily == nil {
+		defaultKeepDaily := 30
+		c.Backup.KeepDaily = &defaultKeepDaily
+	}
+
+	// Set default fiat configuration
+	if c.Fiat.Source == "" {
+		c.Fiat.Source = "mempool"
+	}
+	if c.Fiat.URL == "" {
+		c.Fiat.URL = "https://mempool.space/api/v1/prices"
+	}
+	if c.Fiat.RefreshInterval == 0 {
+		c.Fiat.RefreshInterval = 10 * time.Minute
+	}
+
+	// Set default provider configuration
+	for i := range c.Notifications.Providers {
+		p := &c.Notifications.Providers[i]
+		if p.Type == "" {
+			p.Type = ProviderTypeShoutrrr
+		}
+		if p.Webhook.Timeout == 0 {
+			p.Webhook.Timeout = 10 * time.Second
+		}
+		if p.Webhook.MaxAttempts == 0 {
+			p.Webhook.MaxAttempts = 3
+		}
+	}
+
+	// Set default outbox configuration
+	if c.Notifications.Outbox.DataDir == "" {
+		c.Notifications.Outbox.DataDir = "outbox"
+	}
+	if c.Notifications.Outbox.MaxAttempts == 0 {
+		c.Notifications.Outbox.MaxAttempts = 10
+	}
+	if c.Notifications.Outbox.InitialInterval == 0 {
+		c.Notifications.Outbox.InitialInterval = 5 * time.Second
+	}
+	if c.Notifications.Outbox.MaxInterval == 0 {
+		c.Notifications.Outbox.MaxInterval = 10 * time.Minute
+	}
 }
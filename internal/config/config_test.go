@@ -0,0 +1,95 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+	_ "time/tzdata"
+)
+
+// baseConfig is the smallest valid config, tests append their sections to it
+const baseConfig = `
+lnd:
+  host: localhost
+  port: 10009
+  tls_cert_path: tls.cert
+  macaroon_path: admin.macaroon
+notifications:
+  providers:
+    - url: "generic://example.com"
+      name: main
+`
+
+func loadTestConfig(t *testing.T, extra string) (*Config, error) {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(path, []byte(baseConfig+extra), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return LoadConfig(path)
+}
+
+func TestBackupRetention(t *testing.T) {
+	tests := []struct {
+		name          string
+		extra         string
+		wantKeepLast  int
+		wantKeepDaily int
+		wantErr       string
+	}{
+		{"defaults", "", 10, 30, ""},
+		{"no daily backups", "backup:\n  keep_daily: 0\n", 10, 0, ""},
+		{"daily backups only", "backup:\n  keep_last: 0\n  keep_daily: 7\n", 0, 7, ""},
+		{"negative", "backup:\n  keep_last: -1\n", 0, 0, "must not be negative"},
+		{"nothing kept", "backup:\n  keep_last: 0\n  keep_daily: 0\n", 0, 0, "must not both be 0"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg, err := loadTestConfig(t, tt.extra)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("LoadConfig() error = %v; want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("LoadConfig() error = %v", err)
+			}
+			if *cfg.Backup.KeepLast != tt.wantKeepLast || *cfg.Backup.KeepDaily != tt.wantKeepDaily {
+				t.Errorf("keep_last, keep_daily = %d, %d; want %d, %d", *cfg.Backup.KeepLast, *cfg.Backup.KeepDaily, tt.wantKeepLast, tt.wantKeepDaily)
+			}
+		})
+	}
+}
+
+func TestNodeSummaryLocation(t *testing.T) {
+	tests := []struct {
+		name     string
+		timezone string
+		want     string
+		wantErr  bool
+	}{
+		{"local time if empty", "", time.Local.String(), false},
+		{"named timezone", "Europe/Berlin", "Europe/Berlin", false},
+		{"invalid timezone", "Mars/Olympus", "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			loc, err := NodeSummaryEventConfig{Timezone: tt.timezone}.Location()
+			if tt.wantErr {
+				if err == nil {
+					t.Error("Location() error = nil; want error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Location() error = %v", err)
+			}
+			if loc.String() != tt.want {
+				t.Errorf("Location() = %v; want %v", loc, tt.want)
+			}
+		})
+	}
+}
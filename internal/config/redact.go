@@ -0,0 +1,68 @@
+package config
+
+import (
+	"net/url"
+	"strings"
+)
+
+const redacted = "REDACTED"
+
+// Redacted returns a copy of the config with secrets replaced, e.g. to display it. Provider
+// URLs keep their scheme and host, as tokens are part of the user info, path or query.
+func (c Config) Redacted() Config {
+	providers := make([]ProviderConfig, len(c.Notifications.Providers))
+	for i, p := range c.Notifications.Providers {
+		p.URL = redactURL(p.URL)
+		if p.Webhook.Secret != "" {
+			p.Webhook.Secret = redacted
+		}
+		if len(p.Webhook.Headers) > 0 {
+			headers := make(map[string]string, len(p.Webhook.Headers))
+			for name := range p.Webhook.Headers {
+				headers[name] = redacted
+			}
+			p.Webhook.Headers = headers
+		}
+		providers[i] = p
+	}
+	c.Notifications.Providers = providers
+
+	c.MQTT.Broker = redactURL(c.MQTT.Broker)
+	if c.MQTT.Password != "" {
+		c.MQTT.Password = redacted
+	}
+
+	if c.Backup.S3.AccessKeyID != "" {
+		c.Backup.S3.AccessKeyID = redacted
+	}
+	if c.Backup.S3.SecretAccessKey != "" {
+		c.Backup.S3.SecretAccessKey = redacted
+	}
+	if c.EventConfig.BackupEvent.Passphrase != "" {
+		c.EventConfig.BackupEvent.Passphrase = redacted
+	}
+	return c
+}
+
+// redactURL removes the user info, path and query of a URL
+func redactURL(raw string) string {
+	if raw == "" {
+		return ""
+	}
+
+	u, err := url.Parse(raw)
+	if err != nil || u.Scheme == "" {
+		return redacted
+	}
+
+	var b strings.Builder
+	b.WriteString(u.Scheme + "://")
+	if u.User != nil {
+		b.WriteString(redacted + "@")
+	}
+	b.WriteString(u.Host)
+	if (u.Path != "" && u.Path != "/") || u.RawQuery != "" {
+		b.WriteString("/" + redacted)
+	}
+	return b.String()
+}
@@ -8,12 +8,14 @@ import (
 )
 
 type AliasChangedEvent struct {
+	eventNode
 	timestamp time.Time
 	oldAlias  string
 	newAlias  string
 }
 
 type AliasChangedTemplate struct {
+	NodeTemplate
 	OldAlias string
 	NewAlias string
 }
@@ -34,10 +36,11 @@ func (e *AliasChangedEvent) Timestamp() time.Time {
 	return e.timestamp
 }
 
-func (e *AliasChangedEvent) GetTemplateData(lang language.Tag) interface{} {
+func (e *AliasChangedEvent) GetTemplateData(cfg *config.Config, lang language.Tag, fiat FiatConverter) interface{} {
 	return &AliasChangedTemplate{
-		OldAlias: e.oldAlias,
-		NewAlias: e.newAlias,
+		NodeTemplate: e.nodeTemplate(),
+		OldAlias:     e.oldAlias,
+		NewAlias:     e.newAlias,
 	}
 }
 
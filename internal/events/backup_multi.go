@@ -6,22 +6,29 @@ import (
 	"fmt"
 	"time"
 
+	"github.com/Primexz/lndnotify/internal/backup"
 	"github.com/Primexz/lndnotify/internal/config"
 	"github.com/Primexz/lndnotify/pkg/uploader"
 	"github.com/lightningnetwork/lnd/lnrpc"
 	"golang.org/x/text/language"
+
+	log "github.com/sirupsen/logrus"
 )
 
 type BackupMultiEvent struct {
+	eventNode
 	Backup    *lnrpc.MultiChanBackup
 	timestamp time.Time
 }
 
 type BackupMultiTemplate struct {
+	NodeTemplate
 	ChanPoints    []string
 	NumChanPoints int
 	Filename      string
 	Sha256Sum     string
+	// Encrypted is set if the attached file is encrypted with age
+	Encrypted bool
 }
 
 func NewBackupMultiEvent(backup *lnrpc.MultiChanBackup) *BackupMultiEvent {
@@ -39,22 +46,30 @@ func (e *BackupMultiEvent) Timestamp() time.Time {
 	return e.timestamp
 }
 
-func (e *BackupMultiEvent) GetTemplateData(lang language.Tag) interface{} {
+func (e *BackupMultiEvent) GetTemplateData(cfg *config.Config, lang language.Tag, fiat FiatConverter) interface{} {
+	encrypted := cfg.EventConfig.BackupEvent.Encrypted()
+
+	// The channel points of an encrypted backup are left out, so the provider doesn't
+	// learn the channels from the message or the webhook payload
 	var chanPoints []string
-	for _, cp := range e.Backup.ChanPoints {
-		txHex := hex.EncodeToString(cp.GetFundingTxidBytes())
-		chanPoint := fmt.Sprintf("%s:%d", txHex, cp.OutputIndex)
-		chanPoints = append(chanPoints, chanPoint)
+	if !encrypted {
+		for _, cp := range e.Backup.ChanPoints {
+			txHex := hex.EncodeToString(cp.GetFundingTxidBytes())
+			chanPoint := fmt.Sprintf("%s:%d", txHex, cp.OutputIndex)
+			chanPoints = append(chanPoints, chanPoint)
+		}
 	}
 
 	hash := sha256.Sum256(e.Backup.MultiChanBackup)
 	sha256sum := hex.EncodeToString(hash[:])
 
 	return &BackupMultiTemplate{
+		NodeTemplate:  e.nodeTemplate(),
 		NumChanPoints: len(e.Backup.ChanPoints),
 		ChanPoints:    chanPoints,
-		Filename:      e.getFileName(),
+		Filename:      e.getFileName(cfg),
 		Sha256Sum:     sha256sum,
+		Encrypted:     encrypted,
 	}
 }
 
@@ -62,14 +77,29 @@ func (e *BackupMultiEvent) ShouldProcess(cfg *config.Config) bool {
 	return cfg.Events.BackupEvents
 }
 
-func (e *BackupMultiEvent) getFileName() string {
+func (e *BackupMultiEvent) getFileName(cfg *config.Config) string {
 	timestamp := e.timestamp.Format("20060102_150405")
-	return "channel_backup_" + timestamp + ".backup"
+	name := "channel_backup_" + timestamp + ".backup"
+	if cfg.EventConfig.BackupEvent.Encrypted() {
+		name += backup.EncryptedSuffix
+	}
+	return name
 }
 
-func (e *BackupMultiEvent) GetFile() *uploader.File {
+// GetFile returns the backup file, encrypted if configured. It returns nil if the backup
+// cannot be encrypted, so the unencrypted backup is never sent.
+func (e *BackupMultiEvent) GetFile(cfg *config.Config) *uploader.File {
+	data := e.Backup.MultiChanBackup
+	if cfg.EventConfig.BackupEvent.Encrypted() {
+		var err error
+		if data, err = backup.Encrypt(data, cfg.EventConfig.BackupEvent); err != nil {
+			log.WithError(err).Error("error encrypting channel backup, sending the notification without it")
+			return nil
+		}
+	}
+
 	return &uploader.File{
-		Data:     e.Backup.MultiChanBackup,
-		Filename: e.getFileName(),
+		Data:     data,
+		Filename: e.getFileName(cfg),
 	}
 }
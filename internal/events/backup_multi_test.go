@@ -0,0 +1,67 @@
+package events
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"text/template"
+
+	"github.com/Primexz/lndnotify/internal/config"
+	"github.com/lightningnetwork/lnd/lnrpc"
+	"golang.org/x/text/language"
+)
+
+func TestBackupMultiEncryptedChanPoints(t *testing.T) {
+	backup := &lnrpc.MultiChanBackup{
+		ChanPoints: []*lnrpc.ChannelPoint{{
+			FundingTxid: &lnrpc.ChannelPoint_FundingTxidBytes{FundingTxidBytes: bytes.Repeat([]byte{0xab}, 32)},
+			OutputIndex: 1,
+		}},
+		MultiChanBackup: []byte("backup"),
+	}
+	chanPoint := strings.Repeat("ab", 32) + ":1"
+
+	tests := []struct {
+		name       string
+		encryption string
+		wantPoints bool
+	}{
+		{"plain", "", true},
+		{"encrypted", "  backup_multi_event:\n    passphrase: secret\n", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "config.yaml")
+			data := "lnd:\n  host: localhost\n  port: 10009\n  tls_cert_path: tls.cert\n  macaroon_path: admin.macaroon\n" +
+				"notifications:\n  providers:\n    - url: \"generic://example.com\"\n      name: main\n" +
+				"event_config:\n" + tt.encryption
+			if err := os.WriteFile(path, []byte(data), 0o600); err != nil {
+				t.Fatal(err)
+			}
+			cfg, err := config.LoadConfig(path)
+			if err != nil {
+				t.Fatalf("LoadConfig() error = %v", err)
+			}
+
+			tmplData := NewBackupMultiEvent(backup).GetTemplateData(cfg, language.English, nil).(*BackupMultiTemplate)
+			if got := len(tmplData.ChanPoints) > 0; got != tt.wantPoints {
+				t.Errorf("ChanPoints = %v; want channel points %v", tmplData.ChanPoints, tt.wantPoints)
+			}
+			if tmplData.NumChanPoints != 1 {
+				t.Errorf("NumChanPoints = %d; want 1", tmplData.NumChanPoints)
+			}
+
+			// The default template must not list the channel points of an encrypted backup
+			tmpl := template.Must(template.New("backup").Parse(cfg.Notifications.Templates.BackupMulti))
+			var buf bytes.Buffer
+			if err := tmpl.Execute(&buf, tmplData); err != nil {
+				t.Fatalf("Execute() error = %v", err)
+			}
+			if got := strings.Contains(buf.String(), chanPoint); got != tt.wantPoints {
+				t.Errorf("rendered message contains channel point = %v; want %v\n%s", got, tt.wantPoints, buf.String())
+			}
+		})
+	}
+}
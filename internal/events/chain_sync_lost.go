@@ -9,11 +9,13 @@ import (
 )
 
 type ChainSyncLostEvent struct {
+	eventNode
 	Duration  time.Duration
 	timestamp time.Time
 }
 
 type ChainSyncLostTemplate struct {
+	NodeTemplate
 	Duration time.Duration
 }
 
@@ -32,12 +34,17 @@ func (e *ChainSyncLostEvent) Timestamp() time.Time {
 	return e.timestamp
 }
 
-func (e *ChainSyncLostEvent) GetTemplateData(lang language.Tag) interface{} {
+func (e *ChainSyncLostEvent) GetTemplateData(cfg *config.Config, lang language.Tag, fiat FiatConverter) interface{} {
 	return &ChainSyncLostTemplate{
-		Duration: format.FormatDuration(e.Duration),
+		NodeTemplate: e.nodeTemplate(),
+		Duration:     format.FormatDuration(e.Duration),
 	}
 }
 
 func (e *ChainSyncLostEvent) ShouldProcess(cfg *config.Config) bool {
 	return cfg.Events.ChainSyncEvents
 }
+
+func (e *ChainSyncLostEvent) Severity() Severity {
+	return SeverityWarning
+}
@@ -9,11 +9,13 @@ import (
 )
 
 type ChainSyncRestoredEvent struct {
+	eventNode
 	Duration  time.Duration
 	timestamp time.Time
 }
 
 type ChainSyncRestoredTemplate struct {
+	NodeTemplate
 	Duration time.Duration
 }
 
@@ -32,9 +34,10 @@ func (e *ChainSyncRestoredEvent) Timestamp() time.Time {
 	return e.timestamp
 }
 
-func (e *ChainSyncRestoredEvent) GetTemplateData(lang language.Tag) interface{} {
+func (e *ChainSyncRestoredEvent) GetTemplateData(cfg *config.Config, lang language.Tag, fiat FiatConverter) interface{} {
 	return &ChainSyncRestoredTemplate{
-		Duration: format.FormatDuration(e.Duration),
+		NodeTemplate: e.nodeTemplate(),
+		Duration:     format.FormatDuration(e.Duration),
 	}
 }
 
@@ -10,22 +10,26 @@ import (
 )
 
 type ChannelCloseEvent struct {
+	eventNode
 	Node      *lnrpc.LightningNode
 	Channel   *lnrpc.ChannelCloseSummary
 	timestamp time.Time
 }
 
 type ChannelCloseTemplate struct {
-	PeerAlias       string
-	PeerPubKey      string
-	PeerPubkeyShort string
-	SettledBalance  string
-	ChanId          uint64
-	ChannelPoint    string
-	RemotePubkey    string
-	Capacity        string
-	CloseInitiator  bool
-	CloseType       int32
+	NodeTemplate
+	PeerAlias          string
+	PeerPubKey         string
+	PeerPubkeyShort    string
+	SettledBalance     format.Number
+	SettledBalanceFiat string
+	ChanId             uint64
+	ChannelPoint       string
+	RemotePubkey       string
+	Capacity           format.Number
+	CapacityFiat       string
+	CloseInitiator     bool
+	CloseType          int32
 }
 
 func NewChannelCloseEvent(node *lnrpc.LightningNode, channel *lnrpc.ChannelCloseSummary) *ChannelCloseEvent {
@@ -44,21 +48,33 @@ func (e *ChannelCloseEvent) Timestamp() time.Time {
 	return e.timestamp
 }
 
-func (e *ChannelCloseEvent) GetTemplateData(lang language.Tag) interface{} {
+func (e *ChannelCloseEvent) GetTemplateData(cfg *config.Config, lang language.Tag, fiat FiatConverter) interface{} {
 	return &ChannelCloseTemplate{
-		PeerAlias:       e.Node.Alias,
-		PeerPubKey:      e.Node.PubKey,
-		PeerPubkeyShort: format.FormatPubKey(e.Node.PubKey),
-		ChanId:          e.Channel.ChanId,
-		ChannelPoint:    e.Channel.ChannelPoint,
-		Capacity:        format.FormatBasic(float64(e.Channel.Capacity), lang),
-		RemotePubkey:    e.Channel.RemotePubkey,
-		SettledBalance:  format.FormatBasic(float64(e.Channel.SettledBalance), lang),
-		CloseInitiator:  e.Channel.CloseInitiator == lnrpc.Initiator_INITIATOR_LOCAL,
-		CloseType:       int32(e.Channel.CloseType),
+		NodeTemplate:       e.nodeTemplate(),
+		PeerAlias:          e.Node.Alias,
+		PeerPubKey:         e.Node.PubKey,
+		PeerPubkeyShort:    format.FormatPubKey(e.Node.PubKey),
+		ChanId:             e.Channel.ChanId,
+		ChannelPoint:       e.Channel.ChannelPoint,
+		Capacity:           format.Basic(float64(e.Channel.Capacity), lang),
+		CapacityFiat:       formatFiat(fiat, float64(e.Channel.Capacity), lang),
+		RemotePubkey:       e.Channel.RemotePubkey,
+		SettledBalance:     format.Basic(float64(e.Channel.SettledBalance), lang),
+		SettledBalanceFiat: formatFiat(fiat, float64(e.Channel.SettledBalance), lang),
+		CloseInitiator:     e.Channel.CloseInitiator == lnrpc.Initiator_INITIATOR_LOCAL,
+		CloseType:          int32(e.Channel.CloseType),
 	}
 }
 
 func (e *ChannelCloseEvent) ShouldProcess(cfg *config.Config) bool {
 	return cfg.Events.ChannelEvents
 }
+
+// Severity is critical for force and breach closes, which lock funds or indicate a cheating attempt
+func (e *ChannelCloseEvent) Severity() Severity {
+	switch e.Channel.GetCloseType() {
+	case lnrpc.ChannelCloseSummary_LOCAL_FORCE_CLOSE, lnrpc.ChannelCloseSummary_REMOTE_FORCE_CLOSE, lnrpc.ChannelCloseSummary_BREACH_CLOSE:
+		return SeverityCritical
+	}
+	return SeverityInfo
+}
@@ -10,18 +10,21 @@ import (
 )
 
 type ChannelClosingEvent struct {
+	eventNode
 	Channel   *lnrpc.PendingChannelsResponse_WaitingCloseChannel
 	getAlias  func(pubKey string) string
 	timestamp time.Time
 }
 
 type ChannelClosingTemplate struct {
+	NodeTemplate
 	PeerAlias       string
 	PeerPubKey      string
 	PeerPubkeyShort string
 	ChannelPoint    string
-	Capacity        string
-	LimboBalance    string
+	Capacity        format.Number
+	CapacityFiat    string
+	LimboBalance    format.Number
 	ClosingTxid     string
 	ClosingTxHex    string
 }
@@ -42,16 +45,18 @@ func (e *ChannelClosingEvent) Timestamp() time.Time {
 	return e.timestamp
 }
 
-func (e *ChannelClosingEvent) GetTemplateData(lang language.Tag) interface{} {
+func (e *ChannelClosingEvent) GetTemplateData(cfg *config.Config, lang language.Tag, fiat FiatConverter) interface{} {
 	remotePubkey := e.Channel.Channel.RemoteNodePub
 
 	return &ChannelClosingTemplate{
+		NodeTemplate:    e.nodeTemplate(),
 		PeerAlias:       e.getAlias(remotePubkey),
 		PeerPubKey:      remotePubkey,
 		PeerPubkeyShort: format.FormatPubKey(remotePubkey),
 		ChannelPoint:    e.Channel.Channel.ChannelPoint,
-		Capacity:        format.FormatBasic(float64(e.Channel.Channel.Capacity), lang),
-		LimboBalance:    format.FormatBasic(float64(e.Channel.LimboBalance), lang),
+		Capacity:        format.Basic(float64(e.Channel.Channel.Capacity), lang),
+		CapacityFiat:    formatFiat(fiat, float64(e.Channel.Channel.Capacity), lang),
+		LimboBalance:    format.Basic(float64(e.Channel.LimboBalance), lang),
 		ClosingTxid:     e.Channel.ClosingTxid,
 		ClosingTxHex:    e.Channel.ClosingTxHex,
 	}
@@ -10,36 +10,38 @@ import (
 )
 
 type ChannelFeeChangeEvent struct {
+	eventNode
 	FeeChange channelmanager.FeeChangeEvent
 	getAlias  func(pubKey string) string
 	timestamp time.Time
 }
 
 type ChannelFeeChangeTemplate struct {
+	NodeTemplate
 	PeerAlias       string
 	PeerPubKey      string
 	PeerPubkeyShort string
 	ChannelPoint    string
-	Capacity        string
+	Capacity        format.Number
 	ChanId          uint64
 
-	OldFeeRate           string
-	NewFeeRate           string
+	OldFeeRate           format.Number
+	NewFeeRate           format.Number
 	FeeRateChange        string
 	FeeRateChangePercent string
 
-	OldBaseFee           string
-	NewBaseFee           string
+	OldBaseFee           format.Number
+	NewBaseFee           format.Number
 	BaseFeeChange        string
 	BaseFeeChangePercent string
 
-	OldInboundFeeRate           string
-	NewInboundFeeRate           string
+	OldInboundFeeRate           format.Number
+	NewInboundFeeRate           format.Number
 	InboundFeeRateChange        string
 	InboundFeeRateChangePercent string
 
-	OldInboundBaseFee           string
-	NewInboundBaseFee           string
+	OldInboundBaseFee           format.Number
+	NewInboundBaseFee           format.Number
 	InboundBaseFeeChange        string
 	InboundBaseFeeChangePercent string
 }
@@ -60,7 +62,7 @@ func (e *ChannelFeeChangeEvent) Timestamp() time.Time {
 	return e.timestamp
 }
 
-func (e *ChannelFeeChangeEvent) GetTemplateData(lang language.Tag) interface{} {
+func (e *ChannelFeeChangeEvent) GetTemplateData(cfg *config.Config, lang language.Tag, fiat FiatConverter) interface{} {
 	ch := e.FeeChange.Channel
 	feeChange := e.FeeChange
 
@@ -70,30 +72,31 @@ func (e *ChannelFeeChangeEvent) GetTemplateData(lang language.Tag) interface{} {
 	newInboundBaseFeeInSats := float64(feeChange.NewInboundBaseFee) / 1000
 
 	return &ChannelFeeChangeTemplate{
+		NodeTemplate:    e.nodeTemplate(),
 		PeerAlias:       e.getAlias(ch.RemotePubkey),
 		PeerPubKey:      ch.RemotePubkey,
 		PeerPubkeyShort: format.FormatPubKey(ch.RemotePubkey),
 		ChannelPoint:    ch.ChannelPoint,
-		Capacity:        format.FormatBasic(float64(ch.Capacity), lang),
+		Capacity:        format.Basic(float64(ch.Capacity), lang),
 		ChanId:          ch.ChanId,
 
-		OldFeeRate:           format.FormatBasic(float64(feeChange.OldFeeRate), lang),
-		NewFeeRate:           format.FormatBasic(float64(feeChange.NewFeeRate), lang),
+		OldFeeRate:           format.Basic(float64(feeChange.OldFeeRate), lang),
+		NewFeeRate:           format.Basic(float64(feeChange.NewFeeRate), lang),
 		FeeRateChange:        format.CalculateAbsoluteChange(feeChange.OldFeeRate, feeChange.NewFeeRate),
 		FeeRateChangePercent: format.CalculatePercentageChange(feeChange.OldFeeRate, feeChange.NewFeeRate),
 
-		OldBaseFee:           format.FormatBasic(oldBaseFeeInSats, lang),
-		NewBaseFee:           format.FormatBasic(newBaseFeeInSats, lang),
+		OldBaseFee:           format.Basic(oldBaseFeeInSats, lang),
+		NewBaseFee:           format.Basic(newBaseFeeInSats, lang),
 		BaseFeeChange:        format.CalculateAbsoluteChange(int64(oldBaseFeeInSats), int64(newBaseFeeInSats)),
 		BaseFeeChangePercent: format.CalculatePercentageChange(int64(oldBaseFeeInSats), int64(newBaseFeeInSats)),
 
-		OldInboundFeeRate:           format.FormatBasic(float64(feeChange.OldInboundFeeRate), lang),
-		NewInboundFeeRate:           format.FormatBasic(float64(feeChange.NewInboundFeeRate), lang),
+		OldInboundFeeRate:           format.Basic(float64(feeChange.OldInboundFeeRate), lang),
+		NewInboundFeeRate:           format.Basic(float64(feeChange.NewInboundFeeRate), lang),
 		InboundFeeRateChange:        format.CalculateAbsoluteChange(int64(feeChange.OldInboundFeeRate), int64(feeChange.NewInboundFeeRate)),
 		InboundFeeRateChangePercent: format.CalculatePercentageChange(int64(feeChange.OldInboundFeeRate), int64(feeChange.NewInboundFeeRate)),
 
-		OldInboundBaseFee:           format.FormatBasic(oldInboundBaseFeeInSats, lang),
-		NewInboundBaseFee:           format.FormatBasic(newInboundBaseFeeInSats, lang),
+		OldInboundBaseFee:           format.Basic(oldInboundBaseFeeInSats, lang),
+		NewInboundBaseFee:           format.Basic(newInboundBaseFeeInSats, lang),
 		InboundBaseFeeChange:        format.CalculateAbsoluteChange(int64(oldInboundBaseFeeInSats), int64(newInboundBaseFeeInSats)),
 		InboundBaseFeeChangePercent: format.CalculatePercentageChange(int64(oldInboundBaseFeeInSats), int64(newInboundBaseFeeInSats)),
 	}
@@ -10,20 +10,23 @@ import (
 )
 
 type ChannelOpenEvent struct {
+	eventNode
 	Node      *lnrpc.LightningNode
 	Channel   *lnrpc.Channel
 	timestamp time.Time
 }
 
 type ChannelOpenTemplate struct {
+	NodeTemplate
 	PeerAlias       string
 	PeerPubKey      string
 	PeerPubkeyShort string
-	SettledBalance  string
+	SettledBalance  format.Number
 	ChanId          uint64
 	ChannelPoint    string
 	RemotePubkey    string
-	Capacity        string
+	Capacity        format.Number
+	CapacityFiat    string
 }
 
 func NewChannelOpenEvent(node *lnrpc.LightningNode, channel *lnrpc.Channel) *ChannelOpenEvent {
@@ -42,14 +45,16 @@ func (e *ChannelOpenEvent) Timestamp() time.Time {
 	return e.timestamp
 }
 
-func (e *ChannelOpenEvent) GetTemplateData(lang language.Tag) interface{} {
+func (e *ChannelOpenEvent) GetTemplateData(cfg *config.Config, lang language.Tag, fiat FiatConverter) interface{} {
 	return &ChannelOpenTemplate{
+		NodeTemplate:    e.nodeTemplate(),
 		PeerAlias:       e.Node.Alias,
 		PeerPubKey:      e.Node.PubKey,
 		PeerPubkeyShort: format.FormatPubKey(e.Node.PubKey),
 		ChanId:          e.Channel.ChanId,
 		ChannelPoint:    e.Channel.ChannelPoint,
-		Capacity:        format.FormatBasic(float64(e.Channel.Capacity), lang),
+		Capacity:        format.Basic(float64(e.Channel.Capacity), lang),
+		CapacityFiat:    formatFiat(fiat, float64(e.Channel.Capacity), lang),
 		RemotePubkey:    e.Channel.RemotePubkey,
 	}
 }
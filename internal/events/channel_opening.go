@@ -10,17 +10,20 @@ import (
 )
 
 type ChannelOpeningEvent struct {
+	eventNode
 	Channel   *lnrpc.PendingChannelsResponse_PendingOpenChannel
 	getAlias  func(pubKey string) string
 	timestamp time.Time
 }
 
 type ChannelOpeningTemplate struct {
+	NodeTemplate
 	PeerAlias       string
 	PeerPubKey      string
 	PeerPubkeyShort string
 	ChannelPoint    string
-	Capacity        string
+	Capacity        format.Number
+	CapacityFiat    string
 	Initiator       bool
 	IsPrivate       bool
 }
@@ -43,16 +46,18 @@ func (e *ChannelOpeningEvent) Timestamp() time.Time {
 	return e.timestamp
 }
 
-func (e *ChannelOpeningEvent) GetTemplateData(lang language.Tag) interface{} {
+func (e *ChannelOpeningEvent) GetTemplateData(cfg *config.Config, lang language.Tag, fiat FiatConverter) interface{} {
 	remotePubkey := e.Channel.Channel.RemoteNodePub
 	initiator := e.Channel.Channel.Initiator == lnrpc.Initiator_INITIATOR_LOCAL
 
 	return &ChannelOpeningTemplate{
+		NodeTemplate:    e.nodeTemplate(),
 		PeerAlias:       e.getAlias(remotePubkey),
 		PeerPubKey:      remotePubkey,
 		PeerPubkeyShort: format.FormatPubKey(remotePubkey),
 		ChannelPoint:    e.Channel.Channel.ChannelPoint,
-		Capacity:        format.FormatBasic(float64(e.Channel.Channel.Capacity), lang),
+		Capacity:        format.Basic(float64(e.Channel.Channel.Capacity), lang),
+		CapacityFiat:    formatFiat(fiat, float64(e.Channel.Channel.Capacity), lang),
 		Initiator:       initiator,
 		IsPrivate:       e.Channel.Channel.Private,
 	}
@@ -10,6 +10,7 @@ import (
 )
 
 type ChannelStatusDownEvent struct {
+	eventNode
 	Channel   *lnrpc.Channel
 	Duration  time.Duration
 	getAlias  func(pubKey string) string
@@ -17,11 +18,12 @@ type ChannelStatusDownEvent struct {
 }
 
 type ChannelStatusDownEventTemplate struct {
+	NodeTemplate
 	PeerAlias       string
 	PeerPubKey      string
 	PeerPubkeyShort string
 	ChannelPoint    string
-	Capacity        string
+	Capacity        format.Number
 	Duration        time.Duration
 }
 
@@ -42,15 +44,16 @@ func (e *ChannelStatusDownEvent) Timestamp() time.Time {
 	return e.timestamp
 }
 
-func (e *ChannelStatusDownEvent) GetTemplateData(lang language.Tag) interface{} {
+func (e *ChannelStatusDownEvent) GetTemplateData(cfg *config.Config, lang language.Tag, fiat FiatConverter) interface{} {
 	remotePubkey := e.Channel.RemotePubkey
 
 	return &ChannelStatusDownEventTemplate{
+		NodeTemplate:    e.nodeTemplate(),
 		PeerAlias:       e.getAlias(remotePubkey),
 		PeerPubKey:      remotePubkey,
 		PeerPubkeyShort: format.FormatPubKey(remotePubkey),
 		ChannelPoint:    e.Channel.ChannelPoint,
-		Capacity:        format.FormatBasic(float64(e.Channel.Capacity), lang),
+		Capacity:        format.Basic(float64(e.Channel.Capacity), lang),
 		Duration:        format.FormatDuration(e.Duration),
 	}
 }
@@ -58,3 +61,7 @@ func (e *ChannelStatusDownEvent) GetTemplateData(lang language.Tag) interface{}
 func (e *ChannelStatusDownEvent) ShouldProcess(cfg *config.Config) bool {
 	return cfg.Events.ChannelStatusEvents
 }
+
+func (e *ChannelStatusDownEvent) Severity() Severity {
+	return SeverityWarning
+}
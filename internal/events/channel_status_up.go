@@ -10,6 +10,7 @@ import (
 )
 
 type ChannelStatusUpEvent struct {
+	eventNode
 	Channel   *lnrpc.Channel
 	Duration  time.Duration
 	getAlias  func(pubKey string) string
@@ -17,11 +18,12 @@ type ChannelStatusUpEvent struct {
 }
 
 type ChannelStatusUpTemplate struct {
+	NodeTemplate
 	PeerAlias       string
 	PeerPubKey      string
 	PeerPubkeyShort string
 	ChannelPoint    string
-	Capacity        string
+	Capacity        format.Number
 	Duration        time.Duration
 }
 
@@ -42,15 +44,16 @@ func (e *ChannelStatusUpEvent) Timestamp() time.Time {
 	return e.timestamp
 }
 
-func (e *ChannelStatusUpEvent) GetTemplateData(lang language.Tag) interface{} {
+func (e *ChannelStatusUpEvent) GetTemplateData(cfg *config.Config, lang language.Tag, fiat FiatConverter) interface{} {
 	remotePubkey := e.Channel.RemotePubkey
 
 	return &ChannelStatusUpTemplate{
+		NodeTemplate:    e.nodeTemplate(),
 		PeerAlias:       e.getAlias(remotePubkey),
 		PeerPubKey:      remotePubkey,
 		PeerPubkeyShort: format.FormatPubKey(remotePubkey),
 		ChannelPoint:    e.Channel.ChannelPoint,
-		Capacity:        format.FormatBasic(float64(e.Channel.Capacity), lang),
+		Capacity:        format.Basic(float64(e.Channel.Capacity), lang),
 		Duration:        format.FormatDuration(e.Duration),
 	}
 }
@@ -0,0 +1,117 @@
+package events
+
+import (
+	"time"
+
+	"github.com/Primexz/lndnotify/internal/config"
+	"github.com/Primexz/lndnotify/pkg/format"
+	"golang.org/x/text/language"
+)
+
+// FailedHtlcDigest holds the link failures of forwards collected over a digest window
+type FailedHtlcDigest struct {
+	Start time.Time
+	End   time.Time
+
+	Count                  int
+	AmountMsat             uint64
+	MissedFeeMsat          uint64
+	LocalLiquidityFailures int
+
+	// Groups holds the failures per outgoing channel and failure detail, highest missed fee first
+	Groups []FailedHtlcGroup
+}
+
+// FailedHtlcGroup holds the failures of an outgoing channel with the same failure detail
+type FailedHtlcGroup struct {
+	OutChanId              uint64
+	OutChanAlias           string
+	FailureDetail          string
+	Count                  int
+	AmountMsat             uint64
+	MissedFeeMsat          uint64
+	LocalLiquidityFailures int
+}
+
+type FailedHtlcDigestEvent struct {
+	eventNode
+	Digest    *FailedHtlcDigest
+	timestamp time.Time
+}
+
+type FailedHtlcDigestTemplate struct {
+	NodeTemplate
+	Start string
+	End   string
+
+	Count                  int
+	Amount                 format.Number
+	AmountFiat             string
+	MissedFee              format.Number
+	MissedFeeFiat          string
+	LocalLiquidityFailures int
+
+	Groups []FailedHtlcGroupTemplate
+}
+
+type FailedHtlcGroupTemplate struct {
+	OutChanId              uint64
+	OutChanAlias           string
+	FailureDetail          string
+	Count                  int
+	Amount                 format.Number
+	MissedFee              format.Number
+	LocalLiquidityFailures int
+}
+
+func NewFailedHtlcDigestEvent(digest *FailedHtlcDigest) *FailedHtlcDigestEvent {
+	return &FailedHtlcDigestEvent{
+		Digest:    digest,
+		timestamp: time.Now(),
+	}
+}
+
+func (e *FailedHtlcDigestEvent) Type() EventType {
+	return Event_FAILED_HTLC_DIGEST
+}
+
+func (e *FailedHtlcDigestEvent) Timestamp() time.Time {
+	return e.timestamp
+}
+
+func (e *FailedHtlcDigestEvent) GetTemplateData(cfg *config.Config, lang language.Tag, fiat FiatConverter) interface{} {
+	d := e.Digest
+
+	amount := float64(d.AmountMsat) / 1000
+	missedFee := float64(d.MissedFeeMsat) / 1000
+
+	groups := make([]FailedHtlcGroupTemplate, 0, len(d.Groups))
+	for _, g := range d.Groups {
+		groups = append(groups, FailedHtlcGroupTemplate{
+			OutChanId:              g.OutChanId,
+			OutChanAlias:           g.OutChanAlias,
+			FailureDetail:          g.FailureDetail,
+			Count:                  g.Count,
+			Amount:                 format.Basic(float64(g.AmountMsat)/1000, lang),
+			MissedFee:              format.Detailed(float64(g.MissedFeeMsat)/1000, lang),
+			LocalLiquidityFailures: g.LocalLiquidityFailures,
+		})
+	}
+
+	return &FailedHtlcDigestTemplate{
+		NodeTemplate:           e.nodeTemplate(),
+		Start:                  d.Start.Format("2006-01-02 15:04"),
+		End:                    d.End.Format("2006-01-02 15:04"),
+		Count:                  d.Count,
+		Amount:                 format.Basic(amount, lang),
+		AmountFiat:             formatFiat(fiat, amount, lang),
+		MissedFee:              format.Detailed(missedFee, lang),
+		MissedFeeFiat:          formatFiat(fiat, missedFee, lang),
+		LocalLiquidityFailures: d.LocalLiquidityFailures,
+		Groups:                 groups,
+	}
+}
+
+func (e *FailedHtlcDigestEvent) ShouldProcess(cfg *config.Config) bool {
+	return cfg.Events.FailedHtlc
+}
@@ -12,6 +12,7 @@ import (
 )
 
 type FailedHtlcLinkEvent struct {
+	eventNode
 	HtlcEvent      *routerrpc.HtlcEvent
 	FailEvent      *routerrpc.LinkFailEvent
 	channelManager *channelmanager.ChannelManager
@@ -19,17 +20,18 @@ type FailedHtlcLinkEvent struct {
 }
 
 type FailedHtlcLinkTemplate struct {
+	NodeTemplate
 	OutChanId               uint64
 	InChanId                uint64
 	InChanAlias             string
 	OutChanAlias            string
-	OutChanLiquidity        string
-	MissingOutChanLiquidity string
+	OutChanLiquidity        format.Number
+	MissingOutChanLiquidity format.Number
 	IsLocalLiquidityFailure bool
-	Amount                  string
+	Amount                  format.Number
 	WireFailure             string
 	FailureDetail           string
-	MissedFee               string
+	MissedFee               format.Number
 }
 
 func NewFailedHtlcLinkEvent(htlcEvent *routerrpc.HtlcEvent, failEvent *routerrpc.LinkFailEvent, cm *channelmanager.ChannelManager) *FailedHtlcLinkEvent {
@@ -49,7 +51,7 @@ func (e *FailedHtlcLinkEvent) Timestamp() time.Time {
 	return e.timestamp
 }
 
-func (e *FailedHtlcLinkEvent) GetTemplateData(lang language.Tag) interface{} {
+func (e *FailedHtlcLinkEvent) GetTemplateData(cfg *config.Config, lang language.Tag, fiat FiatConverter) interface{} {
 	failInfo := e.FailEvent.GetInfo()
 	inChanId := e.HtlcEvent.GetIncomingChannelId()
 	outChanId := e.HtlcEvent.GetOutgoingChannelId()
@@ -71,25 +73,21 @@ func (e *FailedHtlcLinkEvent) GetTemplateData(lang language.Tag) interface{} {
 	}
 
 	return &FailedHtlcLinkTemplate{
+		NodeTemplate:            e.nodeTemplate(),
 		InChanId:                inChanId,
 		OutChanId:               outChanId,
 		InChanAlias:             inChanAlias,
 		OutChanAlias:            outChanAlias,
-		OutChanLiquidity:        format.FormatBasic(float64(outChanLiquidity), lang),
-		MissingOutChanLiquidity: format.FormatDetailed(float64(failInfo.GetOutgoingAmtMsat())/1000-float64(outChanLiquidity), lang),
+		OutChanLiquidity:        format.Basic(float64(outChanLiquidity), lang),
+		MissingOutChanLiquidity: format.Detailed(float64(failInfo.GetOutgoingAmtMsat())/1000-float64(outChanLiquidity), lang),
 		IsLocalLiquidityFailure: float64(failInfo.GetOutgoingAmtMsat()/1000) > float64(outChanLiquidity),
-		Amount:                  format.FormatBasic(float64(failInfo.GetOutgoingAmtMsat())/1000, lang),
+		Amount:                  format.Basic(float64(failInfo.GetOutgoingAmtMsat())/1000, lang),
 		WireFailure:             e.FailEvent.GetWireFailure().String(),
 		FailureDetail:           e.FailEvent.GetFailureDetail().String(),
-		MissedFee:               format.FormatDetailed((float64(failInfo.GetIncomingAmtMsat()-failInfo.GetOutgoingAmtMsat()))/1000, lang),
+		MissedFee:               format.Detailed((float64(failInfo.GetIncomingAmtMsat()-failInfo.GetOutgoingAmtMsat()))/1000, lang),
 	}
 }
 
 func (e *FailedHtlcLinkEvent) ShouldProcess(cfg *config.Config) bool {
-	if !cfg.Events.FailedHtlc {
-		return false
-	}
-	failInfo := e.FailEvent.GetInfo()
-	amountSats := uint64(failInfo.GetOutgoingAmtMsat() / 1000)
-	return amountSats >= cfg.EventConfig.FailedHtlcEvent.MinAmount
+	return cfg.Events.FailedHtlc
 }
@@ -0,0 +1,17 @@
+package events
+
+import "golang.org/x/text/language"
+
+// FiatConverter formats the fiat value of a sat amount, e.g. "812.34 EUR"
+type FiatConverter interface {
+	Format(sats float64, lang language.Tag) string
+}
+
+// formatFiat returns the fiat value of a sat amount, or an empty string if fiat conversion
+// is disabled (nil converter) or no price is known yet
+func formatFiat(fiat FiatConverter, sats float64, lang language.Tag) string {
+	if fiat == nil {
+		return ""
+	}
+	return fiat.Format(sats, lang)
+}
@@ -10,17 +10,21 @@ import (
 )
 
 type ForwardEvent struct {
+	eventNode
 	Forward   *lnrpc.ForwardingEvent
 	timestamp time.Time
 }
 
 type ForwardTemplate struct {
+	NodeTemplate
 	PeerAliasIn  string
 	PeerAliasOut string
-	Amount       string
-	AmountOut    string
-	Fee          string
-	FeeRate      string
+	Amount       format.Number
+	AmountOut    format.Number
+	Fee          format.Number
+	FeeRate      format.Number
+	AmountFiat   string
+	FeeFiat      string
 }
 
 func NewForwardEvent(forward *lnrpc.ForwardingEvent) *ForwardEvent {
@@ -38,24 +42,24 @@ func (e *ForwardEvent) Timestamp() time.Time {
 	return e.timestamp
 }
 
-func (e *ForwardEvent) GetTemplateData(lang language.Tag) interface{} {
+func (e *ForwardEvent) GetTemplateData(cfg *config.Config, lang language.Tag, fiat FiatConverter) interface{} {
 	amtInSats := float64(e.Forward.AmtInMsat) / 1000
 	amtOutSats := float64(e.Forward.AmtOutMsat) / 1000
 	feeSats := float64(e.Forward.FeeMsat) / 1000
 
 	return &ForwardTemplate{
+		NodeTemplate: e.nodeTemplate(),
 		PeerAliasIn:  e.Forward.PeerAliasIn,
 		PeerAliasOut: e.Forward.PeerAliasOut,
-		Amount:       format.FormatBasic(amtInSats, lang),
-		AmountOut:    format.FormatBasic(amtOutSats, lang),
-		Fee:          format.FormatDetailed(feeSats, lang),
-		FeeRate:      format.FormatRatePPM(feeSats, amtOutSats, lang),
+		Amount:       format.Basic(amtInSats, lang),
+		AmountOut:    format.Basic(amtOutSats, lang),
+		Fee:          format.Detailed(feeSats, lang),
+		FeeRate:      format.RatePPM(feeSats, amtOutSats, lang),
+		AmountFiat:   formatFiat(fiat, amtInSats, lang),
+		FeeFiat:      formatFiat(fiat, feeSats, lang),
 	}
 }
 
 func (e *ForwardEvent) ShouldProcess(cfg *config.Config) bool {
-	if !cfg.Events.ForwardEvents {
-		return false
-	}
-	return e.Forward.AmtOut >= cfg.EventConfig.ForwardEvent.MinAmount
+	return cfg.Events.ForwardEvents
 }
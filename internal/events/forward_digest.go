@@ -0,0 +1,82 @@
+package events
+
+import (
+	"time"
+
+	"github.com/Primexz/lndnotify/internal/config"
+	"github.com/Primexz/lndnotify/pkg/format"
+	"golang.org/x/text/language"
+)
+
+// ForwardDigest holds the forwards collected over a digest window
+type ForwardDigest struct {
+	Start time.Time
+	End   time.Time
+
+	Count      int
+	VolumeMsat uint64
+	FeesMsat   uint64
+
+	// ChannelPairs holds the channel pairs with the highest earned fees, highest first
+	ChannelPairs []ChannelPairSummary
+}
+
+type ForwardDigestEvent struct {
+	eventNode
+	Digest    *ForwardDigest
+	timestamp time.Time
+}
+
+type ForwardDigestTemplate struct {
+	NodeTemplate
+	Start string
+	End   string
+
+	Count      int
+	Volume     format.Number
+	VolumeFiat string
+	Fees       format.Number
+	FeesFiat   string
+	FeeRate    format.Number
+
+	ChannelPairs []ChannelPairTemplate
+}
+
+func NewForwardDigestEvent(digest *ForwardDigest) *ForwardDigestEvent {
+	return &ForwardDigestEvent{
+		Digest:    digest,
+		timestamp: time.Now(),
+	}
+}
+
+func (e *ForwardDigestEvent) Type() EventType {
+	return Event_FORWARD_DIGEST
+}
+
+func (e *ForwardDigestEvent) Timestamp() time.Time {
+	return e.timestamp
+}
+
+func (e *ForwardDigestEvent) GetTemplateData(cfg *config.Config, lang language.Tag, fiat FiatConverter) interface{} {
+	d := e.Digest
+
+	volume := float64(d.VolumeMsat) / 1000
+	fees := float64(d.FeesMsat) / 1000
+
+	return &ForwardDigestTemplate{
+		NodeTemplate: e.nodeTemplate(),
+		Start:        d.Start.Format("2006-01-02 15:04"),
+		End:          d.End.Format("2006-01-02 15:04"),
+		Count:        d.Count,
+		Volume:       format.Basic(volume, lang),
+		VolumeFiat:   formatFiat(fiat, volume, lang),
+		Fees:         format.Detailed(fees, lang),
+		FeesFiat:     formatFiat(fiat, fees, lang),
+		FeeRate:      format.RatePPM(fees, volume, lang),
+		ChannelPairs: channelPairTemplates(d.ChannelPairs, lang),
+	}
+}
+
+func (e *ForwardDigestEvent) ShouldProcess(cfg *config.Config) bool {
+	return cfg.Events.ForwardEvents
+}
@@ -8,10 +8,12 @@ import (
 )
 
 type HealthyEvent struct {
+	eventNode
 	timestamp time.Time
 }
 
 type HealthyTemplate struct {
+	NodeTemplate
 }
 
 func NewLndHealthyEvent() *HealthyEvent {
@@ -28,8 +30,10 @@ func (e *HealthyEvent) Timestamp() time.Time {
 	return e.timestamp
 }
 
-func (e *HealthyEvent) GetTemplateData(lang language.Tag) interface{} {
-	return &HealthyTemplate{}
+func (e *HealthyEvent) GetTemplateData(cfg *config.Config, lang language.Tag, fiat FiatConverter) interface{} {
+	return &HealthyTemplate{
+		NodeTemplate: e.nodeTemplate(),
+	}
 }
 
 func (e *HealthyEvent) ShouldProcess(cfg *config.Config) bool {
@@ -11,6 +11,7 @@ import (
 )
 
 type HTLCExpirationEvent struct {
+	eventNode
 	timestamp       time.Time
 	htlc            *lnrpc.HTLC
 	channel         *lnrpc.Channel
@@ -18,11 +19,12 @@ type HTLCExpirationEvent struct {
 }
 
 type HTLCExpirationTemplate struct {
+	NodeTemplate
 	PeerAlias       string
 	PeerPubKey      string
 	PeerPubkeyShort string
 	ChannelPoint    string
-	HTLCAmount      string
+	HTLCAmount      format.Number
 	RemainingBlocks int32
 	RemainingTime   time.Duration
 }
@@ -44,13 +46,14 @@ func (e *HTLCExpirationEvent) Timestamp() time.Time {
 	return e.timestamp
 }
 
-func (e *HTLCExpirationEvent) GetTemplateData(lang language.Tag) interface{} {
+func (e *HTLCExpirationEvent) GetTemplateData(cfg *config.Config, lang language.Tag, fiat FiatConverter) interface{} {
 	return &HTLCExpirationTemplate{
+		NodeTemplate:    e.nodeTemplate(),
 		PeerAlias:       e.channel.PeerAlias,
 		PeerPubKey:      e.channel.RemotePubkey,
 		PeerPubkeyShort: format.FormatPubKey(e.channel.RemotePubkey),
 		ChannelPoint:    e.channel.ChannelPoint,
-		HTLCAmount:      format.FormatBasic(float64(e.htlc.Amount), lang),
+		HTLCAmount:      format.Basic(float64(e.htlc.Amount), lang),
 		RemainingBlocks: e.remainingBlocks,
 		RemainingTime:   format.FormatDuration(chainutil.BlockCountToDuration(e.remainingBlocks)),
 	}
@@ -63,3 +66,7 @@ func (e *HTLCExpirationEvent) ShouldProcess(cfg *config.Config) bool {
 
 	return e.remainingBlocks <= cfg.EventConfig.HTLCExpirationEvent.RemainingBlocks
 }
+
+func (e *HTLCExpirationEvent) Severity() Severity {
+	return SeverityWarning
+}
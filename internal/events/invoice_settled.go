@@ -10,20 +10,26 @@ import (
 )
 
 type InvoiceSettledEvent struct {
+	eventNode
 	Invoice   *lnrpc.Invoice
+	CatchUp   bool
 	timestamp time.Time
 }
 
 type InvoiceSettledTemplate struct {
+	NodeTemplate
 	Memo           string
-	Value          string
+	Value          format.Number
+	ValueFiat      string
 	IsKeysend      bool
 	PaymentRequest string
+	CatchUp        bool
 }
 
-func NewInvoiceSettledEvent(invoice *lnrpc.Invoice) *InvoiceSettledEvent {
+func NewInvoiceSettledEvent(invoice *lnrpc.Invoice, catchUp bool) *InvoiceSettledEvent {
 	return &InvoiceSettledEvent{
 		Invoice:   invoice,
+		CatchUp:   catchUp,
 		timestamp: time.Now(),
 	}
 }
@@ -36,12 +42,15 @@ func (e *InvoiceSettledEvent) Timestamp() time.Time {
 	return e.timestamp
 }
 
-func (e *InvoiceSettledEvent) GetTemplateData(lang language.Tag) interface{} {
+func (e *InvoiceSettledEvent) GetTemplateData(cfg *config.Config, lang language.Tag, fiat FiatConverter) interface{} {
 	return &InvoiceSettledTemplate{
+		NodeTemplate:   e.nodeTemplate(),
 		Memo:           e.Invoice.Memo,
-		Value:          format.FormatBasic(float64(e.Invoice.Value), lang),
+		Value:          format.Basic(float64(e.Invoice.Value), lang),
+		ValueFiat:      formatFiat(fiat, float64(e.Invoice.Value), lang),
 		IsKeysend:      e.Invoice.IsKeysend,
 		PaymentRequest: e.Invoice.PaymentRequest,
+		CatchUp:        e.CatchUp,
 	}
 }
 
@@ -53,6 +62,5 @@ func (e *InvoiceSettledEvent) ShouldProcess(cfg *config.Config) bool {
 	if skipKeysend != nil && *skipKeysend && e.Invoice.IsKeysend {
 		return false
 	}
-
-	return uint64(e.Invoice.Value) >= cfg.EventConfig.InvoiceEvent.MinAmount
+	return true
 }
@@ -10,24 +10,29 @@ import (
 )
 
 type KeysendEvent struct {
+	eventNode
 	Msg       string
 	Channel   *lnrpc.Channel
 	Htlc      *lnrpc.InvoiceHTLC
+	CatchUp   bool
 	timestamp time.Time
 }
 
 type KeysendTemplate struct {
+	NodeTemplate
 	Msg         string
 	InChanAlias string
 	InChanId    uint64
-	Amount      string
+	Amount      format.Number
+	CatchUp     bool
 }
 
-func NewKeysendEvent(msg string, channel *lnrpc.Channel, htlc *lnrpc.InvoiceHTLC) *KeysendEvent {
+func NewKeysendEvent(msg string, channel *lnrpc.Channel, htlc *lnrpc.InvoiceHTLC, catchUp bool) *KeysendEvent {
 	return &KeysendEvent{
 		Msg:       msg,
 		Channel:   channel,
 		Htlc:      htlc,
+		CatchUp:   catchUp,
 		timestamp: time.Now(),
 	}
 }
@@ -40,17 +45,19 @@ func (e *KeysendEvent) Timestamp() time.Time {
 	return e.timestamp
 }
 
-func (e *KeysendEvent) GetTemplateData(lang language.Tag) interface{} {
+func (e *KeysendEvent) GetTemplateData(cfg *config.Config, lang language.Tag, fiat FiatConverter) interface{} {
 	var inChanAlias string
 	if e.Channel != nil {
 		inChanAlias = e.Channel.PeerAlias
 	}
 
 	return &KeysendTemplate{
-		Msg:         e.Msg,
-		InChanAlias: inChanAlias,
-		InChanId:    e.Htlc.ChanId,
-		Amount:      format.FormatDetailed(float64(e.Htlc.AmtMsat/1000), lang),
+		NodeTemplate: e.nodeTemplate(),
+		Msg:          e.Msg,
+		InChanAlias:  inChanAlias,
+		InChanId:     e.Htlc.ChanId,
+		Amount:       format.Detailed(float64(e.Htlc.AmtMsat/1000), lang),
+		CatchUp:      e.CatchUp,
 	}
 }
 
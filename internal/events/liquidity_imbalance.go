@@ -0,0 +1,81 @@
+package events
+
+import (
+	"time"
+
+	"github.com/Primexz/lndnotify/internal/config"
+	"github.com/Primexz/lndnotify/pkg/format"
+	"github.com/lightningnetwork/lnd/lnrpc"
+	"golang.org/x/text/language"
+)
+
+type LiquidityImbalanceEvent struct {
+	eventNode
+	Channel         *lnrpc.Channel
+	LocalPercent    float64
+	MinLocalPercent float64
+	MaxLocalPercent float64
+	timestamp       time.Time
+}
+
+type LiquidityImbalanceTemplate struct {
+	NodeTemplate
+	PeerAlias       string
+	PeerPubKey      string
+	PeerPubkeyShort string
+	ChannelPoint    string
+	ChanId          uint64
+	Capacity        format.Number
+	LocalBalance    format.Number
+	RemoteBalance   format.Number
+	LocalPercent    format.Number
+	MinLocalPercent format.Number
+	MaxLocalPercent format.Number
+	LowLocal        bool
+	HighLocal       bool
+}
+
+func NewLiquidityImbalanceEvent(channel *lnrpc.Channel, localPercent, minLocalPercent, maxLocalPercent float64) *LiquidityImbalanceEvent {
+	return &LiquidityImbalanceEvent{
+		Channel:         channel,
+		LocalPercent:    localPercent,
+		MinLocalPercent: minLocalPercent,
+		MaxLocalPercent: maxLocalPercent,
+		timestamp:       time.Now(),
+	}
+}
+
+func (e *LiquidityImbalanceEvent) Type() EventType {
+	return Event_LIQUIDITY_IMBALANCE
+}
+
+func (e *LiquidityImbalanceEvent) Timestamp() time.Time {
+	return e.timestamp
+}
+
+func (e *LiquidityImbalanceEvent) GetTemplateData(cfg *config.Config, lang language.Tag, fiat FiatConverter) interface{} {
+	return &LiquidityImbalanceTemplate{
+		NodeTemplate:    e.nodeTemplate(),
+		PeerAlias:       e.Channel.PeerAlias,
+		PeerPubKey:      e.Channel.RemotePubkey,
+		PeerPubkeyShort: format.FormatPubKey(e.Channel.RemotePubkey),
+		ChannelPoint:    e.Channel.ChannelPoint,
+		ChanId:          e.Channel.ChanId,
+		Capacity:        format.Basic(float64(e.Channel.Capacity), lang),
+		LocalBalance:    format.Basic(float64(e.Channel.LocalBalance), lang),
+		RemoteBalance:   format.Basic(float64(e.Channel.RemoteBalance), lang),
+		LocalPercent:    format.Basic(e.LocalPercent, lang),
+		MinLocalPercent: format.Detailed(e.MinLocalPercent, lang),
+		MaxLocalPercent: format.Detailed(e.MaxLocalPercent, lang),
+		LowLocal:        e.LocalPercent < e.MinLocalPercent,
+		HighLocal:       e.LocalPercent > e.MaxLocalPercent,
+	}
+}
+
+func (e *LiquidityImbalanceEvent) ShouldProcess(cfg *config.Config) bool {
+	return cfg.Events.LiquidityEvents
+}
+
+func (e *LiquidityImbalanceEvent) Severity() Severity {
+	return SeverityWarning
+}
@@ -0,0 +1,65 @@
+package events
+
+import (
+	"time"
+
+	"github.com/Primexz/lndnotify/internal/config"
+	"github.com/Primexz/lndnotify/pkg/format"
+	"github.com/lightningnetwork/lnd/lnrpc"
+	"golang.org/x/text/language"
+)
+
+type LiquidityRecoveredEvent struct {
+	eventNode
+	Channel      *lnrpc.Channel
+	LocalPercent float64
+	timestamp    time.Time
+}
+
+type LiquidityRecoveredTemplate struct {
+	NodeTemplate
+	PeerAlias       string
+	PeerPubKey      string
+	PeerPubkeyShort string
+	ChannelPoint    string
+	ChanId          uint64
+	Capacity        format.Number
+	LocalBalance    format.Number
+	RemoteBalance   format.Number
+	LocalPercent    format.Number
+}
+
+func NewLiquidityRecoveredEvent(channel *lnrpc.Channel, localPercent float64) *LiquidityRecoveredEvent {
+	return &LiquidityRecoveredEvent{
+		Channel:      channel,
+		LocalPercent: localPercent,
+		timestamp:    time.Now(),
+	}
+}
+
+func (e *LiquidityRecoveredEvent) Type() EventType {
+	return Event_LIQUIDITY_RECOVERED
+}
+
+func (e *LiquidityRecoveredEvent) Timestamp() time.Time {
+	return e.timestamp
+}
+
+func (e *LiquidityRecoveredEvent) GetTemplateData(cfg *config.Config, lang language.Tag, fiat FiatConverter) interface{} {
+	return &LiquidityRecoveredTemplate{
+		NodeTemplate:    e.nodeTemplate(),
+		PeerAlias:       e.Channel.PeerAlias,
+		PeerPubKey:      e.Channel.RemotePubkey,
+		PeerPubkeyShort: format.FormatPubKey(e.Channel.RemotePubkey),
+		ChannelPoint:    e.Channel.ChannelPoint,
+		ChanId:          e.Channel.ChanId,
+		Capacity:        format.Basic(float64(e.Channel.Capacity), lang),
+		LocalBalance:    format.Basic(float64(e.Channel.LocalBalance), lang),
+		RemoteBalance:   format.Basic(float64(e.Channel.RemoteBalance), lang),
+		LocalPercent:    format.Basic(e.LocalPercent, lang),
+	}
+}
+
+func (e *LiquidityRecoveredEvent) ShouldProcess(cfg *config.Config) bool {
+	return cfg.Events.LiquidityEvents
+}
@@ -9,12 +9,14 @@ import (
 )
 
 type LndUpdateAvailableEvent struct {
+	eventNode
 	LatestVersion  *lndversion.LndVersion
 	CurrentVersion *lndversion.LndVersion
 	timestamp      time.Time
 }
 
 type LndUpdateAvailableTemplate struct {
+	NodeTemplate
 	LatestVersion  *lndversion.LndVersion
 	CurrentVersion *lndversion.LndVersion
 }
@@ -35,8 +37,9 @@ func (e *LndUpdateAvailableEvent) Timestamp() time.Time {
 	return e.timestamp
 }
 
-func (e *LndUpdateAvailableEvent) GetTemplateData(lang language.Tag) interface{} {
+func (e *LndUpdateAvailableEvent) GetTemplateData(cfg *config.Config, lang language.Tag, fiat FiatConverter) interface{} {
 	return &LndUpdateAvailableTemplate{
+		NodeTemplate:   e.nodeTemplate(),
 		LatestVersion:  e.LatestVersion,
 		CurrentVersion: e.CurrentVersion,
 	}
@@ -0,0 +1,40 @@
+package events
+
+// NodeInfo identifies the LND node an event was received from
+type NodeInfo struct {
+	Name  string
+	Alias string
+}
+
+// NodeTemplate is embedded in the template data of every event
+type NodeTemplate struct {
+	NodeName  string
+	NodeAlias string
+}
+
+// eventNode is embedded in every event to keep track of its node
+type eventNode struct {
+	node NodeInfo
+}
+
+// SetSource sets the node the event was received from
+func (e *eventNode) SetSource(node NodeInfo) {
+	e.node = node
+}
+
+// Source returns the node the event was received from
+func (e *eventNode) Source() NodeInfo {
+	return e.node
+}
+
+// Severity returns the default severity of events. Events that can be more severe override it.
+func (e *eventNode) Severity() Severity {
+	return SeverityInfo
+}
+
+func (e *eventNode) nodeTemplate() NodeTemplate {
+	return NodeTemplate{
+		NodeName:  e.node.Name,
+		NodeAlias: e.node.Alias,
+	}
+}
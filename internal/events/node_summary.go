@@ -0,0 +1,169 @@
+package events
+
+import (
+	"time"
+
+	"github.com/Primexz/lndnotify/internal/config"
+	"github.com/Primexz/lndnotify/pkg/format"
+	"golang.org/x/text/language"
+)
+
+// NodeSummary holds the statistics of a node over a reporting period
+type NodeSummary struct {
+	Period string
+	Start  time.Time
+	End    time.Time
+
+	ForwardCount      int
+	ForwardVolumeMsat uint64
+	ForwardFeesMsat   uint64
+
+	RebalanceCount    int
+	RebalanceCostMsat int64
+
+	InvoiceCount      int
+	InvoiceAmountMsat int64
+
+	PaymentCount      int
+	PaymentAmountMsat int64
+	PaymentFeesMsat   int64
+
+	ChannelsOpened int
+	ChannelsClosed int
+
+	// TopChannelPairs holds the channel pairs with the highest earned fees, highest first
+	TopChannelPairs []ChannelPairSummary
+
+	OnChainBalance            int64
+	OnChainUnconfirmedBalance int64
+}
+
+// ChannelPairSummary holds the forwarding statistics of an in -> out channel pair
+type ChannelPairSummary struct {
+	InChanId   uint64
+	OutChanId  uint64
+	InAlias    string
+	OutAlias   string
+	Count      int
+	VolumeMsat uint64
+	FeesMsat   uint64
+}
+
+type NodeSummaryEvent struct {
+	eventNode
+	Summary   *NodeSummary
+	timestamp time.Time
+}
+
+type NodeSummaryTemplate struct {
+	NodeTemplate
+	Period string
+	Start  string
+	End    string
+
+	ForwardCount   int
+	ForwardVolume  format.Number
+	ForwardFees    format.Number
+	ForwardFeeRate format.Number
+
+	RebalanceCount int
+	RebalanceCost  format.Number
+	NetProfit      format.Number
+
+	InvoiceCount  int
+	InvoiceAmount format.Number
+
+	PaymentCount  int
+	PaymentAmount format.Number
+	PaymentFees   format.Number
+
+	ChannelsOpened int
+	ChannelsClosed int
+
+	TopChannelPairs []ChannelPairTemplate
+
+	OnChainBalance            format.Number
+	OnChainUnconfirmedBalance format.Number
+}
+
+type ChannelPairTemplate struct {
+	InChanId  uint64
+	OutChanId uint64
+	InAlias   string
+	OutAlias  string
+	Count     int
+	Volume    format.Number
+	Fees      format.Number
+	FeeRate   format.Number
+}
+
+func NewNodeSummaryEvent(summary *NodeSummary) *NodeSummaryEvent {
+	return &NodeSummaryEvent{
+		Summary:   summary,
+		timestamp: time.Now(),
+	}
+}
+
+func (e *NodeSummaryEvent) Type() EventType {
+	return Event_NODE_SUMMARY
+}
+
+func (e *NodeSummaryEvent) Timestamp() time.Time {
+	return e.timestamp
+}
+
+func (e *NodeSummaryEvent) GetTemplateData(cfg *config.Config, lang language.Tag, fiat FiatConverter) interface{} {
+	s := e.Summary
+
+	forwardVolume := float64(s.ForwardVolumeMsat) / 1000
+	forwardFees := float64(s.ForwardFeesMsat) / 1000
+	rebalanceCost := float64(s.RebalanceCostMsat) / 1000
+
+	return &NodeSummaryTemplate{
+		NodeTemplate:              e.nodeTemplate(),
+		Period:                    s.Period,
+		Start:                     s.Start.Format("2006-01-02 15:04"),
+		End:                       s.End.Format("2006-01-02 15:04"),
+		ForwardCount:              s.ForwardCount,
+		ForwardVolume:             format.Basic(forwardVolume, lang),
+		ForwardFees:               format.Detailed(forwardFees, lang),
+		ForwardFeeRate:            format.RatePPM(forwardFees, forwardVolume, lang),
+		RebalanceCount:            s.RebalanceCount,
+		RebalanceCost:             format.Detailed(rebalanceCost, lang),
+		NetProfit:                 format.Detailed(forwardFees-rebalanceCost, lang),
+		InvoiceCount:              s.InvoiceCount,
+		InvoiceAmount:             format.Basic(float64(s.InvoiceAmountMsat)/1000, lang),
+		PaymentCount:              s.PaymentCount,
+		PaymentAmount:             format.Basic(float64(s.PaymentAmountMsat)/1000, lang),
+		PaymentFees:               format.Detailed(float64(s.PaymentFeesMsat)/1000, lang),
+		ChannelsOpened:            s.ChannelsOpened,
+		ChannelsClosed:            s.ChannelsClosed,
+		TopChannelPairs:           channelPairTemplates(s.TopChannelPairs, lang),
+		OnChainBalance:            format.Basic(float64(s.OnChainBalance), lang),
+		OnChainUnconfirmedBalance: format.Basic(float64(s.OnChainUnconfirmedBalance), lang),
+	}
+}
+
+func channelPairTemplates(pairs []ChannelPairSummary, lang language.Tag) []ChannelPairTemplate {
+	templates := make([]ChannelPairTemplate, 0, len(pairs))
+	for _, p := range pairs {
+		volume := float64(p.VolumeMsat) / 1000
+		fees := float64(p.FeesMsat) / 1000
+
+		templates = append(templates, ChannelPairTemplate{
+			InChanId:  p.InChanId,
+			OutChanId: p.OutChanId,
+			InAlias:   p.InAlias,
+			OutAlias:  p.OutAlias,
+			Count:     p.Count,
+			Volume:    format.Basic(volume, lang),
+			Fees:      format.Detailed(fees, lang),
+			FeeRate:   format.RatePPM(fees, volume, lang),
+		})
+	}
+	return templates
+}
+
+func (e *NodeSummaryEvent) ShouldProcess(cfg *config.Config) bool {
+	return cfg.Events.NodeSummaryEvents
+}
@@ -14,23 +14,27 @@ import (
 )
 
 type OnChainTransactionEvent struct {
+	eventNode
 	Event     *lnrpc.Transaction
 	cfg       *config.Config
 	timestamp time.Time
 }
 
 type OnChainTransactionTemplate struct {
+	NodeTemplate
 	TxHash         string
 	RawTxHex       string
-	Amount         string
-	TotalFees      string
+	Amount         format.Number
+	TotalFees      format.Number
+	AmountFiat     string
+	FeeFiat        string
 	Confirmed      bool
 	Outputs        []OnChainOutput
 	TransactionURL string
 }
 
 type OnChainOutput struct {
-	Amount       string
+	Amount       format.Number
 	Address      string
 	OutputType   string
 	IsOurAddress bool
@@ -56,11 +60,11 @@ func (e *OnChainTransactionEvent) Timestamp() time.Time {
 	return e.timestamp
 }
 
-func (e *OnChainTransactionEvent) GetTemplateData(lang language.Tag) interface{} {
+func (e *OnChainTransactionEvent) GetTemplateData(cfg *config.Config, lang language.Tag, fiat FiatConverter) interface{} {
 	outputs := make([]OnChainOutput, 0, len(e.Event.OutputDetails))
 	for _, output := range e.Event.OutputDetails {
 		outputs = append(outputs, OnChainOutput{
-			Amount:       format.FormatBasic(float64(output.Amount), lang),
+			Amount:       format.Basic(float64(output.Amount), lang),
 			OutputType:   output.OutputType.String(),
 			IsOurAddress: output.IsOurAddress,
 			Address:      output.Address,
@@ -74,22 +78,21 @@ func (e *OnChainTransactionEvent) GetTemplateData(lang language.Tag) interface{}
 	}
 
 	return &OnChainTransactionTemplate{
+		NodeTemplate:   e.nodeTemplate(),
 		TxHash:         e.Event.TxHash,
 		RawTxHex:       e.Event.RawTxHex,
 		Outputs:        outputs,
-		Amount:         format.FormatBasic(float64(e.Event.Amount), lang),
-		TotalFees:      format.FormatDetailed(float64(e.Event.TotalFees), lang),
+		Amount:         format.Basic(float64(e.Event.Amount), lang),
+		TotalFees:      format.Detailed(float64(e.Event.TotalFees), lang),
+		AmountFiat:     formatFiat(fiat, float64(e.Event.Amount), lang),
+		FeeFiat:        formatFiat(fiat, float64(e.Event.TotalFees), lang),
 		Confirmed:      e.Event.NumConfirmations > 0,
 		TransactionURL: transactionURL,
 	}
 }
 
 func (e *OnChainTransactionEvent) ShouldProcess(cfg *config.Config) bool {
-	if !cfg.Events.OnChainEvents {
-		return false
-	}
-
-	return uint64(e.Event.Amount) >= cfg.EventConfig.OnChainEvent.MinAmount
+	return cfg.Events.OnChainEvents
 }
 
 func (e *OnChainTransactionEvent) generateTransactionURL(txHash string) (string, error) {
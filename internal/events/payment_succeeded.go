@@ -10,47 +10,54 @@ import (
 )
 
 type PaymentSucceededEvent struct {
+	eventNode
 	Payment       *lnrpc.Payment
 	PayReq        *lnrpc.PayReq
 	IsRebalancing bool
+	CatchUp       bool
 	getAlias      func(pubKey string) string
 	timestamp     time.Time
 }
 
 type PaymentSucceededTemplate struct {
+	NodeTemplate
 	PaymentHash string
-	Amount      string
-	Fee         string
-	FeeRate     string
+	Amount      format.Number
+	Fee         format.Number
+	FeeRate     format.Number
+	AmountFiat  string
+	FeeFiat     string
 	HtlcInfo    []PaymentHtlcInfo
 	Receiver    string
 	Memo        string
+	CatchUp     bool
 }
 
 type PaymentHtlcInfo struct {
 	FirstHop  string
 	PenultHop string
 	HopInfo   []PaymentHopInfo
-	Amount    string
-	Fee       string
-	FeeRate   string
+	Amount    format.Number
+	Fee       format.Number
+	FeeRate   format.Number
 }
 
 type PaymentHopInfo struct {
 	Pubkey  string
 	Alias   string
-	Amount  string
-	Fee     string
-	FeeRate string
+	Amount  format.Number
+	Fee     format.Number
+	FeeRate format.Number
 }
 
 func NewPaymentSucceededEvent(payment *lnrpc.Payment, payReq *lnrpc.PayReq,
-	isRebalancing bool, getAlias func(pubKey string) string) *PaymentSucceededEvent {
+	isRebalancing bool, catchUp bool, getAlias func(pubKey string) string) *PaymentSucceededEvent {
 
 	return &PaymentSucceededEvent{
 		Payment:       payment,
 		PayReq:        payReq,
 		IsRebalancing: isRebalancing,
+		CatchUp:       catchUp,
 		getAlias:      getAlias,
 		timestamp:     time.Now(),
 	}
@@ -67,7 +74,7 @@ func (e *PaymentSucceededEvent) Timestamp() time.Time {
 	return e.timestamp
 }
 
-func (e *PaymentSucceededEvent) GetTemplateData(lang language.Tag) interface{} {
+func (e *PaymentSucceededEvent) GetTemplateData(cfg *config.Config, lang language.Tag, fiat FiatConverter) interface{} {
 	amountSats := float64(e.Payment.ValueMsat) / 1000
 	feeSats := float64(e.Payment.FeeMsat) / 1000
 
@@ -104,9 +111,9 @@ func (e *PaymentSucceededEvent) GetTemplateData(lang language.Tag) interface{} {
 			hopInfo = append(hopInfo, PaymentHopInfo{
 				Pubkey:  hop.PubKey,
 				Alias:   e.getAlias(hop.PubKey),
-				Amount:  format.FormatBasic(amountSats, lang),
-				Fee:     format.FormatDetailed(feeSats, lang),
-				FeeRate: format.FormatRatePPM(feeSats, amountSats, lang),
+				Amount:  format.Basic(amountSats, lang),
+				Fee:     format.Detailed(feeSats, lang),
+				FeeRate: format.RatePPM(feeSats, amountSats, lang),
 			})
 		}
 
@@ -123,36 +130,34 @@ func (e *PaymentSucceededEvent) GetTemplateData(lang language.Tag) interface{} {
 			FirstHop:  e.getAlias(firstHop.PubKey),
 			PenultHop: penultHop,
 			HopInfo:   hopInfo,
-			Fee:       format.FormatDetailed(feeSats, lang),
-			FeeRate:   format.FormatRatePPM(feeSats, amountSats, lang),
-			Amount:    format.FormatBasic(amountSats, lang),
+			Fee:       format.Detailed(feeSats, lang),
+			FeeRate:   format.RatePPM(feeSats, amountSats, lang),
+			Amount:    format.Basic(amountSats, lang),
 		})
 	}
 
 	return &PaymentSucceededTemplate{
-		PaymentHash: e.Payment.PaymentHash,
-		Amount:      format.FormatBasic(amountSats, lang),
-		Fee:         format.FormatDetailed(feeSats, lang),
-		FeeRate:     format.FormatRatePPM(feeSats, amountSats, lang),
-		HtlcInfo:    htlcInfo,
-		Receiver:    receiver,
-		Memo:        memo,
+		NodeTemplate: e.nodeTemplate(),
+		PaymentHash:  e.Payment.PaymentHash,
+		Amount:       format.Basic(amountSats, lang),
+		Fee:          format.Detailed(feeSats, lang),
+		FeeRate:      format.RatePPM(feeSats, amountSats, lang),
+		AmountFiat:   formatFiat(fiat, amountSats, lang),
+		FeeFiat:      formatFiat(fiat, feeSats, lang),
+		HtlcInfo:     htlcInfo,
+		Receiver:     receiver,
+		Memo:         memo,
+		CatchUp:      e.CatchUp,
 	}
 }
 
 func (e *PaymentSucceededEvent) ShouldProcess(cfg *config.Config) bool {
 	switch e.Type() {
 	case Event_REBALANCING_SUCCEEDED:
-		if !cfg.Events.RebalancingEvents {
-			return false
-		}
-		return uint64(e.Payment.ValueSat) >= cfg.EventConfig.RebalancingEvent.MinAmount
+		return cfg.Events.RebalancingEvents
 
 	case Event_PAYMENT_SUCCEEDED:
-		if !cfg.Events.PaymentEvents {
-			return false
-		}
-		return uint64(e.Payment.ValueSat) >= cfg.EventConfig.PaymentEvent.MinAmount
+		return cfg.Events.PaymentEvents
 
 	default:
 		return false
@@ -10,12 +10,14 @@ import (
 )
 
 type PeerOfflineEvent struct {
+	eventNode
 	NodeInfo  *lnrpc.NodeInfo
 	Event     *lnrpc.PeerEvent
 	timestamp time.Time
 }
 
 type PeerOfflineTemplate struct {
+	NodeTemplate
 	PeerAlias       string
 	PeerPubKey      string
 	PeerPubkeyShort string
@@ -38,13 +40,14 @@ func (e *PeerOfflineEvent) Timestamp() time.Time {
 	return e.timestamp
 }
 
-func (e *PeerOfflineEvent) GetTemplateData(lang language.Tag) interface{} {
+func (e *PeerOfflineEvent) GetTemplateData(cfg *config.Config, lang language.Tag, fiat FiatConverter) interface{} {
 	var alias string
 	if e.NodeInfo != nil {
 		alias = e.NodeInfo.Node.Alias
 	}
 
 	return &PeerOfflineTemplate{
+		NodeTemplate:    e.nodeTemplate(),
 		PeerAlias:       alias,
 		PeerPubKey:      e.Event.GetPubKey(),
 		PeerPubkeyShort: format.FormatPubKey(e.Event.GetPubKey()),
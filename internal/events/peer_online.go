@@ -10,12 +10,14 @@ import (
 )
 
 type PeerOnlineEvent struct {
+	eventNode
 	NodeInfo  *lnrpc.NodeInfo
 	Event     *lnrpc.PeerEvent
 	timestamp time.Time
 }
 
 type PeerOnlineTemplate struct {
+	NodeTemplate
 	PeerAlias       string
 	PeerPubKey      string
 	PeerPubkeyShort string
@@ -38,13 +40,14 @@ func (e *PeerOnlineEvent) Timestamp() time.Time {
 	return e.timestamp
 }
 
-func (e *PeerOnlineEvent) GetTemplateData(lang language.Tag) interface{} {
+func (e *PeerOnlineEvent) GetTemplateData(cfg *config.Config, lang language.Tag, fiat FiatConverter) interface{} {
 	var alias string
 	if e.NodeInfo != nil {
 		alias = e.NodeInfo.Node.Alias
 	}
 
 	return &PeerOnlineTemplate{
+		NodeTemplate:    e.nodeTemplate(),
 		PeerAlias:       alias,
 		PeerPubKey:      e.Event.GetPubKey(),
 		PeerPubkeyShort: format.FormatPubKey(e.Event.GetPubKey()),
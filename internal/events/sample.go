@@ -0,0 +1,354 @@
+package events
+
+import (
+	"time"
+
+	"github.com/Primexz/lndnotify/pkg/format"
+	"github.com/Primexz/lndnotify/pkg/lndversion"
+	"golang.org/x/text/language"
+	"golang.org/x/text/message"
+)
+
+const (
+	samplePubkey       = "03864ef025fde8fb587d989186ce6a4a186895ee44a926bfc370e2c366597a3f8f"
+	sampleChannelPoint = "2f4b6f7a9e1c3d5b8a0e2c4d6f8a1b3c5d7e9f0a2b4c6d8e0f1a3b5c7d9e1f3a:1"
+	sampleTxHash       = "2f4b6f7a9e1c3d5b8a0e2c4d6f8a1b3c5d7e9f0a2b4c6d8e0f1a3b5c7d9e1f3a"
+	sampleChanId       = 871234567890123777
+	sampleBTCPrice     = 65000
+)
+
+// SampleTemplateData returns realistic template data of an event type, e.g. to validate
+// templates without a connection to LND. It returns nil for unknown event types.
+func SampleTemplateData(eventType EventType, lang language.Tag) interface{} {
+	sats := func(value float64) format.Number {
+		return format.Basic(value, lang)
+	}
+	detailed := func(value float64) format.Number {
+		return format.Detailed(value, lang)
+	}
+	fiat := func(sats float64) string {
+		return message.NewPrinter(lang).Sprintf("%.2f EUR", sats/100_000_000*sampleBTCPrice)
+	}
+
+	node := NodeTemplate{NodeName: "alice", NodeAlias: "Alice's Node"}
+	peerShort := format.FormatPubKey(samplePubkey)
+
+	switch eventType {
+	case Event_BACKUP_MULTI:
+		return &BackupMultiTemplate{
+			NodeTemplate:  node,
+			ChanPoints:    []string{sampleChannelPoint, sampleTxHash + ":0"},
+			NumChanPoints: 2,
+			Filename:      "channel-backup-20260426-123000.backup",
+			Sha256Sum:     "9f86d081884c7d659a2feaa0c55ad015a3bf4f1b2b0b822cd15d6c15b0f00a08",
+		}
+	case Event_CHAIN_SYNC_LOST:
+		return &ChainSyncLostTemplate{NodeTemplate: node, Duration: 12 * time.Minute}
+	case Event_CHAIN_SYNC_RESTORED:
+		return &ChainSyncRestoredTemplate{NodeTemplate: node, Duration: 27 * time.Minute}
+	case Event_CHANNEL_CLOSE:
+		return &ChannelCloseTemplate{
+			NodeTemplate:       node,
+			PeerAlias:          "ACINQ",
+			PeerPubKey:         samplePubkey,
+			PeerPubkeyShort:    peerShort,
+			SettledBalance:     sats(1234567),
+			ChanId:             sampleChanId,
+			ChannelPoint:       sampleChannelPoint,
+			RemotePubkey:       samplePubkey,
+			Capacity:           sats(5000000),
+			CapacityFiat:       fiat(5000000),
+			SettledBalanceFiat: fiat(1234567),
+			CloseInitiator:     true,
+			CloseType:          0,
+		}
+	case Event_CHANNEL_CLOSING:
+		return &ChannelClosingTemplate{
+			NodeTemplate:    node,
+			PeerAlias:       "ACINQ",
+			PeerPubKey:      samplePubkey,
+			PeerPubkeyShort: peerShort,
+			ChannelPoint:    sampleChannelPoint,
+			Capacity:        sats(5000000),
+			CapacityFiat:    fiat(5000000),
+			LimboBalance:    sats(1234567),
+			ClosingTxid:     sampleTxHash,
+			ClosingTxHex:    "0200000001...",
+		}
+	case Event_CHANNEL_FEE_CHANGE:
+		return &ChannelFeeChangeTemplate{
+			NodeTemplate:                node,
+			PeerAlias:                   "ACINQ",
+			PeerPubKey:                  samplePubkey,
+			PeerPubkeyShort:             peerShort,
+			ChannelPoint:                sampleChannelPoint,
+			Capacity:                    sats(5000000),
+			ChanId:                      sampleChanId,
+			OldFeeRate:                  sats(500),
+			NewFeeRate:                  sats(750),
+			FeeRateChange:               format.CalculateAbsoluteChange(500, 750),
+			FeeRateChangePercent:        format.CalculatePercentageChange(500, 750),
+			OldBaseFee:                  sats(1),
+			NewBaseFee:                  sats(1),
+			BaseFeeChange:               format.CalculateAbsoluteChange(1, 1),
+			BaseFeeChangePercent:        format.CalculatePercentageChange(1, 1),
+			OldInboundFeeRate:           sats(0),
+			NewInboundFeeRate:           sats(-100),
+			InboundFeeRateChange:        format.CalculateAbsoluteChange(0, -100),
+			InboundFeeRateChangePercent: format.CalculatePercentageChange(0, -100),
+			OldInboundBaseFee:           sats(0),
+			NewInboundBaseFee:           sats(0),
+			InboundBaseFeeChange:        format.CalculateAbsoluteChange(0, 0),
+			InboundBaseFeeChangePercent: format.CalculatePercentageChange(0, 0),
+		}
+	case Event_CHANNEL_OPEN:
+		return &ChannelOpenTemplate{
+			NodeTemplate:    node,
+			PeerAlias:       "ACINQ",
+			PeerPubKey:      samplePubkey,
+			PeerPubkeyShort: peerShort,
+			SettledBalance:  sats(0),
+			ChanId:          sampleChanId,
+			ChannelPoint:    sampleChannelPoint,
+			RemotePubkey:    samplePubkey,
+			Capacity:        sats(5000000),
+			CapacityFiat:    fiat(5000000),
+		}
+	case Event_CHANNEL_OPENING:
+		return &ChannelOpeningTemplate{
+			NodeTemplate:    node,
+			PeerAlias:       "ACINQ",
+			PeerPubKey:      samplePubkey,
+			PeerPubkeyShort: peerShort,
+			ChannelPoint:    sampleChannelPoint,
+			Capacity:        sats(5000000),
+			CapacityFiat:    fiat(5000000),
+			Initiator:       true,
+		}
+	case Event_CHANNEL_STATUS_UP:
+		return &ChannelStatusUpTemplate{
+			NodeTemplate:    node,
+			PeerAlias:       "ACINQ",
+			PeerPubKey:      samplePubkey,
+			PeerPubkeyShort: peerShort,
+			ChannelPoint:    sampleChannelPoint,
+			Capacity:        sats(5000000),
+			Duration:        42 * time.Minute,
+		}
+	case Event_CHANNEL_STATUS_DOWN:
+		return &ChannelStatusDownEventTemplate{
+			NodeTemplate:    node,
+			PeerAlias:       "ACINQ",
+			PeerPubKey:      samplePubkey,
+			PeerPubkeyShort: peerShort,
+			ChannelPoint:    sampleChannelPoint,
+			Capacity:        sats(5000000),
+			Duration:        15 * time.Minute,
+		}
+	case Event_FAILED_HTLC:
+		return &FailedHtlcLinkTemplate{
+			NodeTemplate:            node,
+			InChanId:                sampleChanId,
+			OutChanId:               sampleChanId + 1,
+			InChanAlias:             "ACINQ",
+			OutChanAlias:            "Kraken",
+			OutChanLiquidity:        sats(150000),
+			MissingOutChanLiquidity: detailed(100000),
+			IsLocalLiquidityFailure: true,
+			Amount:                  sats(250000),
+			WireFailure:             "TEMPORARY_CHANNEL_FAILURE",
+			FailureDetail:           "INSUFFICIENT_BALANCE",
+			MissedFee:               detailed(125.5),
+		}
+	case Event_FAILED_HTLC_DIGEST:
+		return &FailedHtlcDigestTemplate{
+			NodeTemplate:           node,
+			Start:                  "2026-04-26 11:00",
+			End:                    "2026-04-26 12:00",
+			Count:                  17,
+			Amount:                 sats(4250000),
+			AmountFiat:             fiat(4250000),
+			MissedFee:              detailed(1840.5),
+			MissedFeeFiat:          fiat(1840.5),
+			LocalLiquidityFailures: 12,
+			Groups: []FailedHtlcGroupTemplate{
+				{OutChanId: sampleChanId + 1, OutChanAlias: "Kraken", FailureDetail: "INSUFFICIENT_BALANCE", Count: 12, Amount: sats(3500000), MissedFee: detailed(1600), LocalLiquidityFailures: 12},
+				{OutChanId: sampleChanId + 2, OutChanAlias: "Boltz", FailureDetail: "NO_DETAIL", Count: 5, Amount: sats(750000), MissedFee: detailed(240.5)},
+			},
+		}
+	case Event_FORWARD:
+		return &ForwardTemplate{
+			NodeTemplate: node,
+			PeerAliasIn:  "ACINQ",
+			PeerAliasOut: "Kraken",
+			Amount:       sats(1000125),
+			AmountOut:    sats(1000000),
+			Fee:          detailed(125),
+			FeeRate:      format.RatePPM(125, 1000000, lang),
+			AmountFiat:   fiat(1000125),
+			FeeFiat:      fiat(125),
+		}
+	case Event_FORWARD_DIGEST:
+		return &ForwardDigestTemplate{
+			NodeTemplate: node,
+			Start:        "2026-04-26 11:00",
+			End:          "2026-04-26 12:00",
+			Count:        18,
+			Volume:       sats(9500000),
+			VolumeFiat:   fiat(9500000),
+			Fees:         detailed(1725.5),
+			FeesFiat:     fiat(1725.5),
+			FeeRate:      format.RatePPM(1725.5, 9500000, lang),
+			ChannelPairs: []ChannelPairTemplate{
+				{InChanId: sampleChanId, OutChanId: sampleChanId + 1, InAlias: "ACINQ", OutAlias: "Kraken", Count: 12, Volume: sats(6000000), Fees: detailed(1200), FeeRate: sats(200)},
+				{InChanId: sampleChanId + 2, OutChanId: sampleChanId, InAlias: "Boltz", OutAlias: "ACINQ", Count: 6, Volume: sats(3500000), Fees: detailed(525.5), FeeRate: sats(150)},
+			},
+		}
+	case Event_HEALTHY:
+		return &HealthyTemplate{NodeTemplate: node}
+	case Event_UNHEALTHY:
+		return &UnhealthyTemplate{NodeTemplate: node, Err: "rpc error: code = Unavailable desc = connection refused"}
+	case Event_INVOICE_SETTLED:
+		return &InvoiceSettledTemplate{
+			NodeTemplate:   node,
+			Memo:           "Coffee",
+			Value:          sats(21000),
+			ValueFiat:      fiat(21000),
+			PaymentRequest: "lnbc210u1p...",
+		}
+	case Event_KEYSEND:
+		return &KeysendTemplate{
+			NodeTemplate: node,
+			Msg:          "Thanks for the great routing!",
+			InChanAlias:  "ACINQ",
+			InChanId:     sampleChanId,
+			Amount:       sats(1000),
+		}
+	case Event_ONCHAIN_MEMPOOL, Event_ONCHAIN_CONFIRMED:
+		return &OnChainTransactionTemplate{
+			NodeTemplate: node,
+			TxHash:       sampleTxHash,
+			RawTxHex:     "0200000001...",
+			Amount:       sats(-500000),
+			TotalFees:    sats(1410),
+			AmountFiat:   fiat(-500000),
+			FeeFiat:      fiat(1410),
+			Confirmed:    eventType == Event_ONCHAIN_CONFIRMED,
+			Outputs: []OnChainOutput{
+				{Amount: sats(500000), Address: "bc1qar0srrr7xfkvy5l643lydnw9re59gtzzwf5mdq", OutputType: "SCRIPT_TYPE_WITNESS_V0_PUBKEY_HASH"},
+				{Amount: sats(748590), Address: "bc1q9h5yjfnvh8qgx2gnq0g7mzdsxfhkz3l6l7u4rz", OutputType: "SCRIPT_TYPE_WITNESS_V0_PUBKEY_HASH", IsOurAddress: true},
+			},
+			TransactionURL: "https://mempool.space/tx/" + sampleTxHash,
+		}
+	case Event_PAYMENT_SUCCEEDED, Event_REBALANCING_SUCCEEDED:
+		return &PaymentSucceededTemplate{
+			NodeTemplate: node,
+			PaymentHash:  sampleTxHash,
+			Amount:       sats(100000),
+			Fee:          detailed(12.5),
+			FeeRate:      format.RatePPM(12.5, 100000, lang),
+			AmountFiat:   fiat(100000),
+			FeeFiat:      fiat(12.5),
+			HtlcInfo: []PaymentHtlcInfo{{
+				FirstHop:  "ACINQ",
+				PenultHop: "Kraken",
+				Amount:    sats(100000),
+				Fee:       detailed(12.5),
+				FeeRate:   format.RatePPM(12.5, 100000, lang),
+				HopInfo: []PaymentHopInfo{
+					{Pubkey: samplePubkey, Alias: "ACINQ", Amount: sats(100012), Fee: detailed(12.5), FeeRate: sats(125)},
+					{Pubkey: samplePubkey, Alias: "Kraken", Amount: sats(100000), Fee: detailed(0), FeeRate: sats(0)},
+				},
+			}},
+			Receiver: "Kraken",
+			Memo:     "Invoice #42",
+		}
+	case Event_PEER_OFFLINE:
+		return &PeerOfflineTemplate{NodeTemplate: node, PeerAlias: "ACINQ", PeerPubKey: samplePubkey, PeerPubkeyShort: peerShort}
+	case Event_PEER_ONLINE:
+		return &PeerOnlineTemplate{NodeTemplate: node, PeerAlias: "ACINQ", PeerPubKey: samplePubkey, PeerPubkeyShort: peerShort}
+	case Event_TLS_CERT_EXPIRY:
+		return &TLSEventTemplate{
+			NodeTemplate:    node,
+			ExpiryDate:      time.Date(2026, 5, 10, 12, 0, 0, 0, time.UTC),
+			TimeUntilExpiry: 14 * 24 * time.Hour,
+		}
+	case Event_WALLET_STATE:
+		return &WalletStateTemplate{NodeTemplate: node, OldState: "LOCKED", NewState: "RPC_ACTIVE"}
+	case Event_LND_UPDATE_AVAILABLE:
+		return &LndUpdateAvailableTemplate{
+			NodeTemplate:   node,
+			LatestVersion:  &lndversion.LndVersion{Major: 0, Minor: 20, Patch: 1, PreRelease: "beta", Raw: "v0.20.1-beta"},
+			CurrentVersion: &lndversion.LndVersion{Major: 0, Minor: 19, Patch: 3, PreRelease: "beta", Raw: "v0.19.3-beta"},
+		}
+	case Event_HTLC_EXPIRATION:
+		return &HTLCExpirationTemplate{
+			NodeTemplate:    node,
+			PeerAlias:       "ACINQ",
+			PeerPubKey:      samplePubkey,
+			PeerPubkeyShort: peerShort,
+			ChannelPoint:    sampleChannelPoint,
+			HTLCAmount:      sats(250000),
+			RemainingBlocks: 72,
+			RemainingTime:   12 * time.Hour,
+		}
+	case Event_ALIAS_CHANGED:
+		return &AliasChangedTemplate{NodeTemplate: node, OldAlias: "ACINQ", NewAlias: "ACINQ 2"}
+	case Event_NODE_SUMMARY:
+		return &NodeSummaryTemplate{
+			NodeTemplate:   node,
+			Period:         "Daily",
+			Start:          "2026-04-25 08:00",
+			End:            "2026-04-26 08:00",
+			ForwardCount:   42,
+			ForwardVolume:  sats(12500000),
+			ForwardFees:    detailed(2150.25),
+			ForwardFeeRate: format.RatePPM(2150.25, 12500000, lang),
+			RebalanceCount: 2,
+			RebalanceCost:  detailed(310),
+			NetProfit:      detailed(1840.25),
+			InvoiceCount:   3,
+			InvoiceAmount:  sats(63000),
+			PaymentCount:   1,
+			PaymentAmount:  sats(100000),
+			PaymentFees:    detailed(12.5),
+			ChannelsOpened: 1,
+			TopChannelPairs: []ChannelPairTemplate{
+				{InChanId: sampleChanId, OutChanId: sampleChanId + 1, InAlias: "ACINQ", OutAlias: "Kraken", Count: 12, Volume: sats(6000000), Fees: detailed(1200), FeeRate: sats(200)},
+			},
+			OnChainBalance:            sats(2500000),
+			OnChainUnconfirmedBalance: sats(0),
+		}
+	case Event_LIQUIDITY_IMBALANCE:
+		return &LiquidityImbalanceTemplate{
+			NodeTemplate:    node,
+			PeerAlias:       "ACINQ",
+			PeerPubKey:      samplePubkey,
+			PeerPubkeyShort: peerShort,
+			ChannelPoint:    sampleChannelPoint,
+			ChanId:          sampleChanId,
+			Capacity:        sats(5000000),
+			LocalBalance:    sats(250000),
+			RemoteBalance:   sats(4746000),
+			LocalPercent:    detailed(5),
+			MinLocalPercent: detailed(10),
+			MaxLocalPercent: detailed(90),
+			LowLocal:        true,
+		}
+	case Event_LIQUIDITY_RECOVERED:
+		return &LiquidityRecoveredTemplate{
+			NodeTemplate:    node,
+			PeerAlias:       "ACINQ",
+			PeerPubKey:      samplePubkey,
+			PeerPubkeyShort: peerShort,
+			ChannelPoint:    sampleChannelPoint,
+			ChanId:          sampleChanId,
+			Capacity:        sats(5000000),
+			LocalBalance:    sats(1500000),
+			RemoteBalance:   sats(3496000),
+			LocalPercent:    detailed(30),
+		}
+	default:
+		return nil
+	}
+}
@@ -0,0 +1,39 @@
+package events
+
+import "fmt"
+
+// Severity is the importance of an event. It is mapped to the priority of the notification
+// providers and can be used to route notifications.
+type Severity string
+
+const (
+	SeverityInfo     Severity = "info"
+	SeverityWarning  Severity = "warning"
+	SeverityCritical Severity = "critical"
+)
+
+// severityLevels orders the severities, notifications without a severity count as info
+var severityLevels = map[Severity]int{
+	"":               0,
+	SeverityInfo:     0,
+	SeverityWarning:  1,
+	SeverityCritical: 2,
+}
+
+func (s Severity) String() string {
+	return string(s)
+}
+
+// AtLeast reports whether the severity is equal to or higher than min
+func (s Severity) AtLeast(min Severity) bool {
+	return severityLevels[s] >= severityLevels[min]
+}
+
+// ParseSeverity converts a string to a known Severity
+func ParseSeverity(s string) (Severity, error) {
+	switch severity := Severity(s); severity {
+	case SeverityInfo, SeverityWarning, SeverityCritical:
+		return severity, nil
+	}
+	return "", fmt.Errorf("unknown severity: %s", s)
+}
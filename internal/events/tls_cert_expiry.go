@@ -8,11 +8,13 @@ import (
 )
 
 type TLSCertExpiryEvent struct {
+	eventNode
 	ExpiryDate time.Time
 	timestamp  time.Time
 }
 
 type TLSEventTemplate struct {
+	NodeTemplate
 	ExpiryDate      time.Time
 	TimeUntilExpiry time.Duration
 }
@@ -32,8 +34,9 @@ func (e *TLSCertExpiryEvent) Timestamp() time.Time {
 	return e.timestamp
 }
 
-func (e *TLSCertExpiryEvent) GetTemplateData(lang language.Tag) interface{} {
+func (e *TLSCertExpiryEvent) GetTemplateData(cfg *config.Config, lang language.Tag, fiat FiatConverter) interface{} {
 	return &TLSEventTemplate{
+		NodeTemplate:    e.nodeTemplate(),
 		ExpiryDate:      e.ExpiryDate,
 		TimeUntilExpiry: time.Until(e.ExpiryDate),
 	}
@@ -42,3 +45,7 @@ func (e *TLSCertExpiryEvent) GetTemplateData(lang language.Tag) interface{} {
 func (e *TLSCertExpiryEvent) ShouldProcess(cfg *config.Config) bool {
 	return cfg.Events.TLSCertExpiryEvents
 }
+
+func (e *TLSCertExpiryEvent) Severity() Severity {
+	return SeverityWarning
+}
@@ -1,6 +1,7 @@
 package events
 
 import (
+	"fmt"
 	"time"
 
 	"github.com/Primexz/lndnotify/internal/config"
@@ -12,13 +13,21 @@ import (
 type Event interface {
 	Type() EventType
 	Timestamp() time.Time
-	GetTemplateData(lang language.Tag) interface{}
+	// GetTemplateData returns the template data with the config of the node at dispatch time
+	GetTemplateData(cfg *config.Config, lang language.Tag, fiat FiatConverter) interface{}
 	ShouldProcess(cfg *config.Config) bool
+
+	// Severity returns the importance of the event
+	Severity() Severity
+
+	// SetSource and Source hold the node the event was received from
+	SetSource(node NodeInfo)
+	Source() NodeInfo
 }
 
 // FileSource is an interface for types that can provide a file
 type FileSource interface {
-	GetFile() *uploader.File
+	GetFile(cfg *config.Config) *uploader.File
 }
 
 type EventType string
@@ -35,7 +44,9 @@ const (
 	Event_CHANNEL_STATUS_UP     EventType = "channel_status_up_event"
 	Event_CHANNEL_STATUS_DOWN   EventType = "channel_status_down_event"
 	Event_FAILED_HTLC           EventType = "failed_htlc_event"
+	Event_FAILED_HTLC_DIGEST    EventType = "failed_htlc_digest_event"
 	Event_FORWARD               EventType = "forward_event"
+	Event_FORWARD_DIGEST        EventType = "forward_digest_event"
 	Event_HEALTHY               EventType = "healthy_event"
 	Event_UNHEALTHY             EventType = "unhealthy_event"
 	Event_INVOICE_SETTLED       EventType = "invoice_settled_event"
@@ -51,8 +62,57 @@ const (
 	Event_LND_UPDATE_AVAILABLE  EventType = "lnd_update_available_event"
 	Event_HTLC_EXPIRATION       EventType = "htlc_expiration_event"
 	Event_ALIAS_CHANGED         EventType = "alias_changed_event"
+	Event_NODE_SUMMARY          EventType = "node_summary_event"
+	Event_LIQUIDITY_IMBALANCE   EventType = "liquidity_imbalance_event"
+	Event_LIQUIDITY_RECOVERED   EventType = "liquidity_recovered_event"
 )
 
+// EventTypes lists all known event types
+var EventTypes = []EventType{
+	Event_BACKUP_MULTI,
+	Event_CHAIN_SYNC_LOST,
+	Event_CHAIN_SYNC_RESTORED,
+	Event_CHANNEL_CLOSE,
+	Event_CHANNEL_CLOSING,
+	Event_CHANNEL_FEE_CHANGE,
+	Event_CHANNEL_OPEN,
+	Event_CHANNEL_OPENING,
+	Event_CHANNEL_STATUS_UP,
+	Event_CHANNEL_STATUS_DOWN,
+	Event_FAILED_HTLC,
+	Event_FAILED_HTLC_DIGEST,
+	Event_FORWARD,
+	Event_FORWARD_DIGEST,
+	Event_HEALTHY,
+	Event_UNHEALTHY,
+	Event_INVOICE_SETTLED,
+	Event_KEYSEND,
+	Event_ONCHAIN_MEMPOOL,
+	Event_ONCHAIN_CONFIRMED,
+	Event_PAYMENT_SUCCEEDED,
+	Event_PEER_OFFLINE,
+	Event_PEER_ONLINE,
+	Event_REBALANCING_SUCCEEDED,
+	Event_TLS_CERT_EXPIRY,
+	Event_WALLET_STATE,
+	Event_LND_UPDATE_AVAILABLE,
+	Event_HTLC_EXPIRATION,
+	Event_ALIAS_CHANGED,
+	Event_NODE_SUMMARY,
+	Event_LIQUIDITY_IMBALANCE,
+	Event_LIQUIDITY_RECOVERED,
+}
+
 func (et EventType) String() string {
 	return string(et)
 }
+
+// ParseEventType converts a string to a known EventType
+func ParseEventType(s string) (EventType, error) {
+	for _, et := range EventTypes {
+		if string(et) == s {
+			return et, nil
+		}
+	}
+	return "", fmt.Errorf("unknown event type: %s", s)
+}
@@ -8,11 +8,13 @@ import (
 )
 
 type UnhealthyEvent struct {
+	eventNode
 	Err       error
 	timestamp time.Time
 }
 
 type UnhealthyTemplate struct {
+	NodeTemplate
 	Err string
 }
 
@@ -31,12 +33,17 @@ func (e *UnhealthyEvent) Timestamp() time.Time {
 	return e.timestamp
 }
 
-func (e *UnhealthyEvent) GetTemplateData(lang language.Tag) interface{} {
+func (e *UnhealthyEvent) GetTemplateData(cfg *config.Config, lang language.Tag, fiat FiatConverter) interface{} {
 	return &UnhealthyTemplate{
-		Err: e.Err.Error(),
+		NodeTemplate: e.nodeTemplate(),
+		Err:          e.Err.Error(),
 	}
 }
 
 func (e *UnhealthyEvent) ShouldProcess(cfg *config.Config) bool {
 	return cfg.Events.HealthEvents
 }
+
+func (e *UnhealthyEvent) Severity() Severity {
+	return SeverityCritical
+}
@@ -9,12 +9,14 @@ import (
 )
 
 type WalletStateEvent struct {
+	eventNode
 	OldState  lnrpc.WalletState
 	NewState  lnrpc.WalletState
 	timestamp time.Time
 }
 
 type WalletStateTemplate struct {
+	NodeTemplate
 	OldState string
 	NewState string
 }
@@ -35,13 +37,23 @@ func (e *WalletStateEvent) Timestamp() time.Time {
 	return e.timestamp
 }
 
-func (e *WalletStateEvent) GetTemplateData(lang language.Tag) interface{} {
+func (e *WalletStateEvent) GetTemplateData(cfg *config.Config, lang language.Tag, fiat FiatConverter) interface{} {
 	return &WalletStateTemplate{
-		OldState: e.OldState.String(),
-		NewState: e.NewState.String(),
+		NodeTemplate: e.nodeTemplate(),
+		OldState:     e.OldState.String(),
+		NewState:     e.NewState.String(),
 	}
 }
 
 func (e *WalletStateEvent) ShouldProcess(cfg *config.Config) bool {
 	return cfg.Events.WalletStateEvents
 }
+
+// Severity is a warning if the wallet is no longer active, e.g. locked after a restart
+func (e *WalletStateEvent) Severity() Severity {
+	switch e.NewState {
+	case lnrpc.WalletState_RPC_ACTIVE, lnrpc.WalletState_SERVER_ACTIVE, lnrpc.WalletState_UNLOCKED:
+		return SeverityInfo
+	}
+	return SeverityWarning
+}
@@ -0,0 +1,108 @@
+package fiat
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"golang.org/x/text/language"
+	"golang.org/x/text/message"
+)
+
+const satsPerBTC = 100_000_000
+
+// Converter converts sat amounts to a fiat currency. The price is fetched in the background
+// and cached, so conversions never wait for the price source.
+type Converter struct {
+	source   PriceSource
+	currency string
+	interval time.Duration
+
+	mu    sync.RWMutex
+	price float64
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// NewConverter creates a converter that refreshes the price of the currency at the given interval
+func NewConverter(source PriceSource, currency string, interval time.Duration) *Converter {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &Converter{
+		source:   source,
+		currency: strings.ToUpper(currency),
+		interval: interval,
+		ctx:      ctx,
+		cancel:   cancel,
+	}
+}
+
+// Start fetches the price in the background and keeps refreshing it until Stop is called
+func (c *Converter) Start() {
+	c.wg.Add(1)
+	go func() {
+		defer c.wg.Done()
+
+		c.refresh()
+
+		ticker := time.NewTicker(c.interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-c.ctx.Done():
+				return
+			case <-ticker.C:
+				c.refresh()
+			}
+		}
+	}()
+}
+
+// Stop stops refreshing the price
+func (c *Converter) Stop() {
+	c.cancel()
+	c.wg.Wait()
+}
+
+func (c *Converter) refresh() {
+	ctx, cancel := context.WithTimeout(c.ctx, 30*time.Second)
+	defer cancel()
+
+	price, err := c.source.Price(ctx, c.currency)
+	if err != nil {
+		if c.ctx.Err() == nil {
+			log.WithField("currency", c.currency).WithError(err).Warn("error fetching bitcoin price, keeping the last price")
+		}
+		return
+	}
+
+	log.WithFields(log.Fields{"currency": c.currency, "price": price}).Debug("updated bitcoin price")
+	c.mu.Lock()
+	c.price = price
+	c.mu.Unlock()
+}
+
+// Convert returns the fiat value of a sat amount. It returns false if no price is known yet.
+func (c *Converter) Convert(sats float64) (float64, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	if c.price == 0 {
+		return 0, false
+	}
+	return sats / satsPerBTC * c.price, true
+}
+
+// Format returns the fiat value of a sat amount with two decimal places and the currency,
+// e.g. "812.34 EUR". It returns an empty string if no price is known yet.
+func (c *Converter) Format(sats float64, lang language.Tag) string {
+	value, ok := c.Convert(sats)
+	if !ok {
+		return ""
+	}
+	return message.NewPrinter(lang).Sprintf("%.2f %s", value, c.currency)
+}
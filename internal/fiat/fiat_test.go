@@ -0,0 +1,104 @@
+package fiat
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"golang.org/x/text/language"
+)
+
+func TestMempoolSource(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"time":1745660000,"USD":94512,"EUR":83124,"GBP":71200}`))
+	}))
+	defer server.Close()
+
+	source := NewMempoolSource(server.URL)
+
+	price, err := source.Price(context.Background(), "eur")
+	if err != nil {
+		t.Fatalf("Price() error = %v", err)
+	}
+	if price != 83124 {
+		t.Errorf("Price() = %v; want 83124", price)
+	}
+
+	if _, err := source.Price(context.Background(), "XYZ"); err == nil {
+		t.Error("Price() of unknown currency error = nil; want error")
+	}
+}
+
+func TestMempoolSourceError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	if _, err := NewMempoolSource(server.URL).Price(context.Background(), "USD"); err == nil {
+		t.Error("Price() error = nil; want error")
+	}
+}
+
+// failingSource returns a price once and fails afterwards
+type failingSource struct {
+	calls int
+}
+
+func (s *failingSource) Price(_ context.Context, _ string) (float64, error) {
+	s.calls++
+	if s.calls > 1 {
+		return 0, context.DeadlineExceeded
+	}
+	return 50000, nil
+}
+
+func TestConverter(t *testing.T) {
+	converter := NewConverter(FixedSource{Rate: 65000}, "eur", time.Hour)
+
+	if got := converter.Format(1250000, language.English); got != "" {
+		t.Errorf("Format() before the first price = %q; want empty", got)
+	}
+
+	converter.refresh()
+
+	value, ok := converter.Convert(1250000)
+	if !ok || value != 812.5 {
+		t.Errorf("Convert() = %v, %v; want 812.5, true", value, ok)
+	}
+	if got := converter.Format(1250000, language.English); got != "812.50 EUR" {
+		t.Errorf("Format() = %q; want %q", got, "812.50 EUR")
+	}
+	if got := converter.Format(250000000, language.German); got != "162.500,00 EUR" {
+		t.Errorf("Format() = %q; want %q", got, "162.500,00 EUR")
+	}
+}
+
+func TestConverterKeepsLastPrice(t *testing.T) {
+	converter := NewConverter(&failingSource{}, "USD", time.Hour)
+	converter.refresh()
+	converter.refresh()
+
+	if value, ok := converter.Convert(100000000); !ok || value != 50000 {
+		t.Errorf("Convert() = %v, %v; want 50000, true", value, ok)
+	}
+}
+
+func TestConverterStartStop(t *testing.T) {
+	converter := NewConverter(FixedSource{Rate: 100000}, "USD", time.Hour)
+	converter.Start()
+	defer converter.Stop()
+
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		if _, ok := converter.Convert(1); ok {
+			return
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("price not fetched after Start()")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
@@ -0,0 +1,68 @@
+package fiat
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// PriceSource provides the price of one bitcoin in a fiat currency
+type PriceSource interface {
+	Price(ctx context.Context, currency string) (float64, error)
+}
+
+// MempoolSource fetches prices from a mempool.space compatible /api/v1/prices endpoint
+type MempoolSource struct {
+	url    string
+	client *http.Client
+}
+
+// NewMempoolSource creates a price source for the given endpoint URL
+func NewMempoolSource(url string) *MempoolSource {
+	return &MempoolSource{
+		url:    url,
+		client: &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+// Price fetches the current price. The response contains the price per currency, e.g. {"time": 1745660000, "USD": 94512, "EUR": 83124}.
+func (s *MempoolSource) Price(ctx context.Context, currency string) (float64, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.url, nil)
+	if err != nil {
+		return 0, err
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close() // nolint:errcheck
+
+	if resp.StatusCode != http.StatusOK {
+		return 0, fmt.Errorf("unexpected status code %d", resp.StatusCode)
+	}
+
+	var prices map[string]float64
+	if err := json.NewDecoder(resp.Body).Decode(&prices); err != nil {
+		return 0, fmt.Errorf("decoding prices: %w", err)
+	}
+
+	price, ok := prices[strings.ToUpper(currency)]
+	if !ok || price <= 0 {
+		return 0, fmt.Errorf("no price for currency %s", currency)
+	}
+	return price, nil
+}
+
+// FixedSource always returns the same price, e.g. for testing templates
+type FixedSource struct {
+	Rate float64
+}
+
+// Price returns the fixed rate
+func (s FixedSource) Price(_ context.Context, _ string) (float64, error) {
+	return s.Rate, nil
+}
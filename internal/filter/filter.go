@@ -0,0 +1,165 @@
+package filter
+
+import (
+	"fmt"
+	"reflect"
+
+	"github.com/Primexz/lndnotify/internal/config"
+	"github.com/Primexz/lndnotify/internal/events"
+	"github.com/Primexz/lndnotify/pkg/format"
+	"github.com/expr-lang/expr"
+	"github.com/expr-lang/expr/vm"
+	log "github.com/sirupsen/logrus"
+)
+
+// Env holds the fields of the template data of an event for the evaluation of rules
+type Env map[string]any
+
+// NewEnv creates the environment of a template data struct. The fields of embedded
+// structs are promoted and formatted numbers are replaced by their value, so that rules
+// like `FeeRate > 500` work on fields that are rendered as "1,000".
+func NewEnv(data any) Env {
+	env := make(Env)
+	addFields(env, reflect.ValueOf(data))
+	return env
+}
+
+func addFields(env Env, v reflect.Value) {
+	for v.Kind() == reflect.Pointer || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return
+		}
+		v = v.Elem()
+	}
+	if v.Kind() != reflect.Struct {
+		return
+	}
+
+	for i := 0; i < v.NumField(); i++ {
+		field := v.Type().Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		if field.Anonymous {
+			addFields(env, v.Field(i))
+			continue
+		}
+		env[field.Name] = value(v.Field(i))
+	}
+}
+
+func value(v reflect.Value) any {
+	if n, ok := v.Interface().(format.Number); ok {
+		return n.Value
+	}
+	return v.Interface()
+}
+
+// Rule is a compiled filter expression
+type Rule struct {
+	expression string
+	program    *vm.Program
+}
+
+// Compile compiles an expression that must evaluate to a boolean
+func Compile(expression string) (*Rule, error) {
+	program, err := expr.Compile(expression, expr.AsBool())
+	if err != nil {
+		return nil, fmt.Errorf("compiling filter %q: %w", expression, err)
+	}
+	return &Rule{
+		expression: expression,
+		program:    program,
+	}, nil
+}
+
+// Match evaluates the rule against the environment
+func (r *Rule) Match(env Env) (bool, error) {
+	out, err := expr.Run(r.program, map[string]any(env))
+	if err != nil {
+		return false, fmt.Errorf("evaluating filter %q: %w", r.expression, err)
+	}
+	return out.(bool), nil
+}
+
+// Rules holds the filter rule of each event type
+type Rules map[events.EventType]*Rule
+
+// NewRules compiles the configured expressions, keyed by event type name. The defaults are
+// generated expressions per event type, e.g. of the min_amount settings, that must match as well.
+func NewRules(expressions map[string]string, defaults map[events.EventType]string) (Rules, error) {
+	combined := make(map[events.EventType]string, len(defaults)+len(expressions))
+	for eventType, expression := range defaults {
+		combined[eventType] = expression
+	}
+	for name, expression := range expressions {
+		eventType, err := events.ParseEventType(name)
+		if err != nil {
+			return nil, fmt.Errorf("invalid filter: %w", err)
+		}
+		// The expression is compiled on its own first, so errors don't show the defaults
+		if _, err := Compile(expression); err != nil {
+			return nil, err
+		}
+		if def, ok := combined[eventType]; ok {
+			expression = fmt.Sprintf("(%s) && (%s)", def, expression)
+		}
+		combined[eventType] = expression
+	}
+	if len(combined) == 0 {
+		return nil, nil
+	}
+
+	rules := make(Rules, len(combined))
+	for eventType, expression := range combined {
+		rule, err := Compile(expression)
+		if err != nil {
+			return nil, err
+		}
+		rules[eventType] = rule
+	}
+	return rules, nil
+}
+
+// MinAmountExpressions generates the expressions of the min_amount settings of the event config.
+// On-chain transactions are compared by their absolute amount, so outgoing transactions count too.
+func MinAmountExpressions(cfg config.EventConfig) map[events.EventType]string {
+	minAmounts := []struct {
+		eventType events.EventType
+		amount    string
+		min       uint64
+	}{
+		{events.Event_FORWARD, "AmountOut", cfg.ForwardEvent.MinAmount},
+		{events.Event_INVOICE_SETTLED, "Value", cfg.InvoiceEvent.MinAmount},
+		{events.Event_PAYMENT_SUCCEEDED, "Amount", cfg.PaymentEvent.MinAmount},
+		{events.Event_REBALANCING_SUCCEEDED, "Amount", cfg.RebalancingEvent.MinAmount},
+		{events.Event_ONCHAIN_MEMPOOL, "abs(Amount)", cfg.OnChainEvent.MinAmount},
+		{events.Event_ONCHAIN_CONFIRMED, "abs(Amount)", cfg.OnChainEvent.MinAmount},
+		{events.Event_FAILED_HTLC, "Amount", cfg.FailedHtlcEvent.MinAmount},
+	}
+
+	expressions := make(map[events.EventType]string)
+	for _, m := range minAmounts {
+		if m.min > 0 {
+			expressions[m.eventType] = fmt.Sprintf("%s >= %d", m.amount, m.min)
+		}
+	}
+	return expressions
+}
+
+// Match reports whether an event passes the rule of its event type. Events without a
+// rule always pass. If the rule can't be evaluated, e.g. because a field doesn't exist,
+// the error is logged and the event passes, so notifications are not lost to a broken rule.
+func (r Rules) Match(eventType events.EventType, env Env) bool {
+	rule, ok := r[eventType]
+	if !ok {
+		return true
+	}
+
+	match, err := rule.Match(env)
+	if err != nil {
+		log.WithField("event", eventType).WithError(err).Warn("error evaluating filter, sending notification")
+		return true
+	}
+	return match
+}
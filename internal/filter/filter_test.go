@@ -0,0 +1,159 @@
+package filter
+
+import (
+	"testing"
+
+	"github.com/Primexz/lndnotify/internal/config"
+	"github.com/Primexz/lndnotify/internal/events"
+	"github.com/Primexz/lndnotify/pkg/format"
+	"golang.org/x/text/language"
+)
+
+func TestNewEnv(t *testing.T) {
+	env := NewEnv(&events.ForwardTemplate{
+		NodeTemplate: events.NodeTemplate{NodeName: "alice"},
+		PeerAliasIn:  "ACINQ",
+		Amount:       format.Basic(1250000, language.German),
+		Fee:          format.Detailed(12.5, language.German),
+		FeeRate:      format.Basic(10, language.German),
+	})
+
+	tests := map[string]any{
+		"NodeName":    "alice",
+		"PeerAliasIn": "ACINQ",
+		"Amount":      float64(1250000),
+		"Fee":         12.5,
+		"FeeRate":     float64(10),
+	}
+	for name, want := range tests {
+		if got := env[name]; got != want {
+			t.Errorf("env[%q] = %#v; want %#v", name, got, want)
+		}
+	}
+}
+
+func TestNewEnvKeepsStrings(t *testing.T) {
+	env := NewEnv(struct {
+		Alias   string
+		Percent string
+		Exp     string
+		Memo    string
+		Msg     string
+	}{"1,23", "+5.0%", "1e5", "1,000", "42"})
+
+	for _, name := range []string{"Alias", "Percent", "Exp", "Memo", "Msg"} {
+		if _, ok := env[name].(string); !ok {
+			t.Errorf("env[%q] = %#v; want string", name, env[name])
+		}
+	}
+}
+
+func TestRulesMatch(t *testing.T) {
+	var eventCfg config.EventConfig
+	eventCfg.ForwardEvent.MinAmount = 1000
+	eventCfg.OnChainEvent.MinAmount = 5000
+
+	rules, err := NewRules(map[string]string{
+		"forward_event":       `PeerAliasOut == "ACINQ" && FeeRate > 500`,
+		"channel_close_event": `CloseType == 1`,
+	}, MinAmountExpressions(eventCfg))
+	if err != nil {
+		t.Fatalf("NewRules() error = %v", err)
+	}
+
+	amount := format.Basic(2000, language.English)
+	tests := []struct {
+		name      string
+		eventType events.EventType
+		data      any
+		want      bool
+	}{
+		{
+			name:      "matching forward",
+			eventType: events.Event_FORWARD,
+			data:      &events.ForwardTemplate{PeerAliasOut: "ACINQ", AmountOut: amount, FeeRate: format.Basic(1000, language.English)},
+			want:      true,
+		},
+		{
+			name:      "forward below fee rate",
+			eventType: events.Event_FORWARD,
+			data:      &events.ForwardTemplate{PeerAliasOut: "ACINQ", AmountOut: amount, FeeRate: format.Basic(100, language.English)},
+			want:      false,
+		},
+		{
+			name:      "forward to other peer",
+			eventType: events.Event_FORWARD,
+			data:      &events.ForwardTemplate{PeerAliasOut: "Kraken", AmountOut: amount, FeeRate: format.Basic(1000, language.English)},
+			want:      false,
+		},
+		{
+			name:      "forward below min amount",
+			eventType: events.Event_FORWARD,
+			data:      &events.ForwardTemplate{PeerAliasOut: "ACINQ", AmountOut: format.Basic(999, language.English), FeeRate: format.Basic(1000, language.English)},
+			want:      false,
+		},
+		{
+			name:      "outgoing on-chain transaction",
+			eventType: events.Event_ONCHAIN_CONFIRMED,
+			data:      &events.OnChainTransactionTemplate{Amount: format.Basic(-6000, language.English)},
+			want:      true,
+		},
+		{
+			name:      "on-chain transaction below min amount",
+			eventType: events.Event_ONCHAIN_MEMPOOL,
+			data:      &events.OnChainTransactionTemplate{Amount: format.Basic(4000, language.English)},
+			want:      false,
+		},
+		{
+			name:      "force close",
+			eventType: events.Event_CHANNEL_CLOSE,
+			data:      &events.ChannelCloseTemplate{CloseType: 1},
+			want:      true,
+		},
+		{
+			name:      "cooperative close",
+			eventType: events.Event_CHANNEL_CLOSE,
+			data:      &events.ChannelCloseTemplate{CloseType: 0},
+			want:      false,
+		},
+		{
+			name:      "event without rule",
+			eventType: events.Event_INVOICE_SETTLED,
+			data:      &events.InvoiceSettledTemplate{},
+			want:      true,
+		},
+		{
+			name:      "evaluation error passes",
+			eventType: events.Event_FORWARD,
+			data: struct {
+				PeerAliasOut, FeeRate string
+				AmountOut             float64
+			}{"ACINQ", "n/a", 2000},
+			want: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := rules.Match(tt.eventType, NewEnv(tt.data)); got != tt.want {
+				t.Errorf("Match() = %v; want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewRulesErrors(t *testing.T) {
+	tests := map[string]map[string]string{
+		"unknown event type": {"unknown_event": "true"},
+		"invalid syntax":     {"forward_event": "FeeRate >"},
+		"not a boolean":      {"forward_event": `"yes"`},
+	}
+
+	for name, expressions := range tests {
+		t.Run(name, func(t *testing.T) {
+			if _, err := NewRules(expressions, nil); err == nil {
+				t.Error("NewRules() error = nil; want error")
+			}
+		})
+	}
+}
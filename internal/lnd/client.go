@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"os"
 	"sync"
+	"sync/atomic"
 
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/credentials"
@@ -13,9 +14,11 @@ import (
 	channelmanager "github.com/Primexz/lndnotify/internal/channel_manager"
 	"github.com/Primexz/lndnotify/internal/config"
 	"github.com/Primexz/lndnotify/internal/events"
+	"github.com/Primexz/lndnotify/internal/state"
 	"github.com/lightningnetwork/lnd/lnrpc"
 	"github.com/lightningnetwork/lnd/lnrpc/chainrpc"
 	"github.com/lightningnetwork/lnd/lnrpc/routerrpc"
+	log "github.com/sirupsen/logrus"
 	"google.golang.org/protobuf/proto"
 )
 
@@ -29,12 +32,15 @@ type ClientConfig struct {
 
 // Client represents an LND node client
 type Client struct {
-	cfg             *config.Config
+	name            string
+	cfg             atomic.Pointer[config.Config]
+	store           *state.Store
 	conn            *grpc.ClientConn
 	client          lnrpc.LightningClient
 	state           lnrpc.StateClient
 	router          routerrpc.RouterClient
 	chain           chainrpc.ChainKitClient
+	logger          *log.Entry
 	channelManager  *channelmanager.ChannelManager
 	pendChanManager *channelmanager.PendingChannelManager
 	pendChanUpdates chan proto.Message
@@ -43,18 +49,60 @@ type Client struct {
 	ctx             context.Context
 	cancel          context.CancelFunc
 	wg              sync.WaitGroup
+
+	// channel open and close counters for the node summary
+	channelsOpened atomic.Int32
+	channelsClosed atomic.Int32
+
+	// forwards and link failures collected for the digests
+	forwards    forwardCollector
+	failedHtlcs failedHtlcCollector
+
+	// alias of the connected node, set once the main client is started
+	alias atomic.Value
+
+	// health and chain sync state for Status, changes are signaled on statusCh
+	healthy       atomic.Bool
+	syncedToChain atomic.Bool
+	statusCh      chan struct{}
+
+	// subscriptions holds the last error of every LND subscription, nil once established
+	subscriptions map[string]error
+	subMu         sync.Mutex
 }
 
-// NewClient creates a new LND client
-func NewClient(cfg *config.Config) *Client {
+// NewClient creates a new client for the named LND node. Handler checkpoints are kept in the given state store.
+func NewClient(name string, cfg *config.Config, store *state.Store) *Client {
+	// The node is only logged if a nodes list is configured
+	logger := log.NewEntry(log.StandardLogger())
+	if name != "" {
+		logger = logger.WithField("node", name)
+	}
+
 	ctx, cancel := context.WithCancel(context.Background())
-	return &Client{
-		cfg:             cfg,
+	c := &Client{
+		name:            name,
+		logger:          logger,
+		store:           store,
 		eventSub:        make(chan events.Event, 100),
 		pendChanUpdates: make(chan proto.Message, 100),
+		statusCh:        make(chan struct{}, 1),
+		subscriptions:   make(map[string]error),
 		ctx:             ctx,
 		cancel:          cancel,
 	}
+	c.cfg.Store(cfg)
+	return c
+}
+
+// UpdateConfig replaces the config used by the running handlers. Connection settings
+// are only applied on the next connect.
+func (c *Client) UpdateConfig(cfg *config.Config) {
+	c.cfg.Store(cfg)
+}
+
+func (c *Client) config() *config.Config {
+	return c.cfg.Load()
 }
 
 // Connect establishes a connection to the LND node
@@ -67,20 +115,20 @@ func (c *Client) Connect() error {
 	}
 
 	// Read TLS certificate
-	tlsCert, err := credentials.NewClientTLSFromFile(c.cfg.LND.TLSCertPath, "")
+	tlsCert, err := credentials.NewClientTLSFromFile(c.config().LND.TLSCertPath, "")
 	if err != nil {
 		return fmt.Errorf("reading TLS cert: %w", err)
 	}
 
 	// Read macaroon
-	macBytes, err := os.ReadFile(c.cfg.LND.MacaroonPath)
+	macBytes, err := os.ReadFile(c.config().LND.MacaroonPath)
 	if err != nil {
 		return fmt.Errorf("reading macaroon: %w", err)
 	}
 
 	// Create gRPC connection
 	conn, err := grpc.NewClient(
-		fmt.Sprintf("%s:%d", c.cfg.LND.Host, c.cfg.LND.Port),
+		fmt.Sprintf("%s:%d", c.config().LND.Host, c.config().LND.Port),
 		grpc.WithTransportCredentials(tlsCert),
 		grpc.WithPerRPCCredentials(&MacaroonCredential{
 			MacaroonHex: hex.EncodeToString(macBytes),
@@ -136,6 +184,15 @@ func (c *Client) IsConnected() bool {
 	return c.conn != nil
 }
 
+// Node returns the name and alias of the node. The alias is empty until the node info was fetched.
+func (c *Client) Node() events.NodeInfo {
+	alias, _ := c.alias.Load().(string)
+	return events.NodeInfo{
+		Name:  c.name,
+		Alias: alias,
+	}
+}
+
 // SubscribeEvents subscribes to LND events
 func (c *Client) SubscribeEvents() (<-chan events.Event, error) {
 	if !c.IsConnected() {
@@ -155,7 +212,15 @@ func (c *Client) SubscribeEvents() (<-chan events.Event, error) {
 	}
 
 	// nolint:errcheck
-	go retry(c.ctx, "main client", func() (string, error) {
+	go retry(c, "main client", func() (string, error) {
+		info, err := c.client.GetInfo(c.ctx, &lnrpc.GetInfoRequest{})
+		if err != nil {
+			return "", fmt.Errorf("fetching node info: %w", err)
+		}
+		c.alias.Store(info.Alias)
+		c.setHealthy(true)
+		c.setSyncedToChain(info.GetSyncedToChain())
+
 		if err := c.channelManager.Start(); err != nil {
 			return "", fmt.Errorf("starting channel manager: %w", err)
 		}
@@ -170,7 +235,9 @@ func (c *Client) SubscribeEvents() (<-chan events.Event, error) {
 			c.handleChannelEvents,
 			c.handleChannelFeeChanges,
 			c.handleFailedHtlcEvents,
+			c.handleFailedHtlcDigest,
 			c.handleForwards,
+			c.handleForwardDigest,
 			c.handleInvoiceEvents,
 			c.handleKeysendEvents,
 			c.handleOnChainEvents,
@@ -183,6 +250,9 @@ func (c *Client) SubscribeEvents() (<-chan events.Event, error) {
 			c.handeLndVersion,
 			c.handlePendingHTLCs,
 			c.handleAliasChanges,
+			c.handleNodeSummary,
+			c.handleLiquidity,
+			c.handleStatus,
 		}
 		c.wg.Add(len(handlers))
 		for _, h := range handlers {
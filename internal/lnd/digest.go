@@ -0,0 +1,55 @@
+package lnd
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+// digestWindow aggregates the groups of a digest between two flushes
+type digestWindow[K comparable, G any] struct {
+	mu     sync.Mutex
+	start  time.Time
+	groups map[K]*G
+}
+
+// add merges entry into the group with the given key of the current window
+func (w *digestWindow[K, G]) add(key K, entry G, merge func(group *G, entry G)) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.groups == nil {
+		w.groups = make(map[K]*G)
+	}
+	if w.start.IsZero() {
+		w.start = time.Now()
+	}
+
+	if group, ok := w.groups[key]; ok {
+		merge(group, entry)
+		return
+	}
+	w.groups[key] = &entry
+}
+
+// flush returns the start and the groups sorted by less of the window ending at end
+// and starts a new window. The groups are nil if nothing was recorded.
+func (w *digestWindow[K, G]) flush(end time.Time, less func(a, b G) bool) (time.Time, []G) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	start := w.start
+	w.start = end
+	if len(w.groups) == 0 {
+		return start, nil
+	}
+
+	groups := make([]G, 0, len(w.groups))
+	for _, group := range w.groups {
+		groups = append(groups, *group)
+	}
+	sort.Slice(groups, func(i, j int) bool { return less(groups[i], groups[j]) })
+	w.groups = nil
+
+	return start, groups
+}
@@ -0,0 +1,112 @@
+package lnd
+
+import (
+	"time"
+
+	"github.com/Primexz/lndnotify/internal/events"
+	"github.com/lightningnetwork/lnd/lnrpc/routerrpc"
+)
+
+// digestTopFailedHtlcGroups is the number of groups listed in the failed htlc digest
+const digestTopFailedHtlcGroups = 10
+
+type failedHtlcKey struct {
+	outChanId     uint64
+	failureDetail string
+}
+
+// failedHtlcCollector aggregates link failures between two failed htlc digests
+type failedHtlcCollector struct {
+	window digestWindow[failedHtlcKey, events.FailedHtlcGroup]
+}
+
+// add records a link failure in the digest of the current window
+func (f *failedHtlcCollector) add(group events.FailedHtlcGroup) {
+	f.window.add(failedHtlcKey{group.OutChanId, group.FailureDetail}, group, func(existing *events.FailedHtlcGroup, group events.FailedHtlcGroup) {
+		existing.Count += group.Count
+		existing.AmountMsat += group.AmountMsat
+		existing.MissedFeeMsat += group.MissedFeeMsat
+		existing.LocalLiquidityFailures += group.LocalLiquidityFailures
+	})
+}
+
+// flush returns the digest of the window ending at end and starts a new window.
+// It returns nil if no failures were recorded.
+func (f *failedHtlcCollector) flush(end time.Time) *events.FailedHtlcDigest {
+	start, groups := f.window.flush(end, func(a, b events.FailedHtlcGroup) bool {
+		if a.MissedFeeMsat != b.MissedFeeMsat {
+			return a.MissedFeeMsat > b.MissedFeeMsat
+		}
+		return a.Count > b.Count
+	})
+	if groups == nil {
+		return nil
+	}
+
+	digest := &events.FailedHtlcDigest{Start: start, End: end}
+	for _, group := range groups {
+		digest.Count += group.Count
+		digest.AmountMsat += group.AmountMsat
+		digest.MissedFeeMsat += group.MissedFeeMsat
+		digest.LocalLiquidityFailures += group.LocalLiquidityFailures
+	}
+	if len(groups) > digestTopFailedHtlcGroups {
+		groups = groups[:digestTopFailedHtlcGroups]
+	}
+	digest.Groups = groups
+
+	return digest
+}
+
+// addFailedHtlc records a link failure for the failed htlc digest
+func (c *Client) addFailedHtlc(htlcEvent *routerrpc.HtlcEvent, failEvent *routerrpc.LinkFailEvent) {
+	failInfo := failEvent.GetInfo()
+	outChanId := htlcEvent.GetOutgoingChannelId()
+	outChanAlias := "unknown"
+	outChanLiquidity := int64(0)
+
+	if outChan := c.channelManager.GetChannelById(outChanId); outChan != nil {
+		outChanAlias = outChan.PeerAlias
+		outChanLiquidity = outChan.GetLocalBalance() - outChan.GetLocalChanReserveSat() // nolint:staticcheck
+	} else {
+		c.logger.WithField("chan_id", outChanId).Warn("could not find outgoing channel")
+	}
+
+	group := events.FailedHtlcGroup{
+		OutChanId:     outChanId,
+		OutChanAlias:  outChanAlias,
+		FailureDetail: failEvent.GetFailureDetail().String(),
+		Count:         1,
+		AmountMsat:    failInfo.GetOutgoingAmtMsat(),
+	}
+	if failInfo.GetIncomingAmtMsat() > failInfo.GetOutgoingAmtMsat() {
+		group.MissedFeeMsat = failInfo.GetIncomingAmtMsat() - failInfo.GetOutgoingAmtMsat()
+	}
+	if int64(failInfo.GetOutgoingAmtMsat()/1000) > outChanLiquidity {
+		group.LocalLiquidityFailures = 1
+	}
+
+	c.failedHtlcs.add(group)
+}
+
+// handleFailedHtlcDigest sends the collected link failures as a digest on every digest interval
+func (c *Client) handleFailedHtlcDigest() {
+	c.logger.Debug("starting failed htlc digest handler")
+	defer c.wg.Done()
+
+	for {
+		// The interval is read on every iteration, so a reloaded config applies after the next digest
+		interval := c.config().EventConfig.FailedHtlcEvent.DigestInterval
+
+		select {
+		case <-c.ctx.Done():
+			return
+		case <-time.After(interval):
+		}
+
+		if digest := c.failedHtlcs.flush(time.Now()); digest != nil {
+			c.logger.WithField("failures", digest.Count).Debug("sending failed htlc digest")
+			c.eventSub <- events.NewFailedHtlcDigestEvent(digest)
+		}
+	}
+}
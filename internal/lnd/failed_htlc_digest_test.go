@@ -0,0 +1,51 @@
+package lnd
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Primexz/lndnotify/internal/events"
+)
+
+func TestFailedHtlcCollector(t *testing.T) {
+	var collector failedHtlcCollector
+
+	end := time.Now()
+	if digest := collector.flush(end); digest != nil {
+		t.Fatalf("flush() = %+v; want nil without failures", digest)
+	}
+
+	collector.add(events.FailedHtlcGroup{OutChanId: 1, FailureDetail: "INSUFFICIENT_BALANCE", Count: 1, AmountMsat: 100_000, MissedFeeMsat: 100, LocalLiquidityFailures: 1})
+	collector.add(events.FailedHtlcGroup{OutChanId: 1, FailureDetail: "INSUFFICIENT_BALANCE", Count: 1, AmountMsat: 200_000, MissedFeeMsat: 200, LocalLiquidityFailures: 1})
+	collector.add(events.FailedHtlcGroup{OutChanId: 1, FailureDetail: "NO_DETAIL", Count: 1, AmountMsat: 50_000, MissedFeeMsat: 50})
+	collector.add(events.FailedHtlcGroup{OutChanId: 2, FailureDetail: "INSUFFICIENT_BALANCE", Count: 1, AmountMsat: 500_000, MissedFeeMsat: 1000})
+
+	digest := collector.flush(end.Add(time.Hour))
+	if digest == nil {
+		t.Fatal("flush() = nil; want digest")
+	}
+	if !digest.Start.Equal(end) || !digest.End.Equal(end.Add(time.Hour)) {
+		t.Errorf("digest window = %v - %v; want %v - %v", digest.Start, digest.End, end, end.Add(time.Hour))
+	}
+	if digest.Count != 4 || digest.AmountMsat != 850_000 || digest.MissedFeeMsat != 1350 || digest.LocalLiquidityFailures != 2 {
+		t.Errorf("digest totals = %d, %d, %d, %d; want 4, 850000, 1350, 2", digest.Count, digest.AmountMsat, digest.MissedFeeMsat, digest.LocalLiquidityFailures)
+	}
+
+	want := []events.FailedHtlcGroup{
+		{OutChanId: 2, FailureDetail: "INSUFFICIENT_BALANCE", Count: 1, AmountMsat: 500_000, MissedFeeMsat: 1000},
+		{OutChanId: 1, FailureDetail: "INSUFFICIENT_BALANCE", Count: 2, AmountMsat: 300_000, MissedFeeMsat: 300, LocalLiquidityFailures: 2},
+		{OutChanId: 1, FailureDetail: "NO_DETAIL", Count: 1, AmountMsat: 50_000, MissedFeeMsat: 50},
+	}
+	if len(digest.Groups) != len(want) {
+		t.Fatalf("got %d groups; want %d", len(digest.Groups), len(want))
+	}
+	for i, group := range digest.Groups {
+		if group != want[i] {
+			t.Errorf("group %d = %+v; want %+v", i, group, want[i])
+		}
+	}
+
+	if digest := collector.flush(end.Add(2 * time.Hour)); digest != nil {
+		t.Errorf("flush() = %+v; want nil after the digest was sent", digest)
+	}
+}
@@ -0,0 +1,85 @@
+package lnd
+
+import (
+	"time"
+
+	"github.com/Primexz/lndnotify/internal/events"
+	"github.com/lightningnetwork/lnd/lnrpc"
+)
+
+// digestTopChannelPairs is the number of channel pairs listed in the forward digest
+const digestTopChannelPairs = 10
+
+type channelPairKey struct{ in, out uint64 }
+
+// forwardCollector aggregates forwards between two forward digests
+type forwardCollector struct {
+	window digestWindow[channelPairKey, events.ChannelPairSummary]
+}
+
+// add records a forward in the digest of the current window
+func (f *forwardCollector) add(fwd *lnrpc.ForwardingEvent) {
+	pair := events.ChannelPairSummary{
+		InChanId:   fwd.ChanIdIn,
+		OutChanId:  fwd.ChanIdOut,
+		InAlias:    fwd.PeerAliasIn,
+		OutAlias:   fwd.PeerAliasOut,
+		Count:      1,
+		VolumeMsat: fwd.AmtOutMsat,
+		FeesMsat:   fwd.FeeMsat,
+	}
+	f.window.add(channelPairKey{fwd.ChanIdIn, fwd.ChanIdOut}, pair, func(group *events.ChannelPairSummary, pair events.ChannelPairSummary) {
+		group.Count += pair.Count
+		group.VolumeMsat += pair.VolumeMsat
+		group.FeesMsat += pair.FeesMsat
+	})
+}
+
+// flush returns the digest of the window ending at end and starts a new window.
+// It returns nil if no forwards were recorded.
+func (f *forwardCollector) flush(end time.Time) *events.ForwardDigest {
+	start, pairs := f.window.flush(end, func(a, b events.ChannelPairSummary) bool {
+		if a.FeesMsat != b.FeesMsat {
+			return a.FeesMsat > b.FeesMsat
+		}
+		return a.VolumeMsat > b.VolumeMsat
+	})
+	if pairs == nil {
+		return nil
+	}
+
+	digest := &events.ForwardDigest{Start: start, End: end}
+	for _, pair := range pairs {
+		digest.Count += pair.Count
+		digest.VolumeMsat += pair.VolumeMsat
+		digest.FeesMsat += pair.FeesMsat
+	}
+	if len(pairs) > digestTopChannelPairs {
+		pairs = pairs[:digestTopChannelPairs]
+	}
+	digest.ChannelPairs = pairs
+
+	return digest
+}
+
+// handleForwardDigest sends the collected forwards as a digest on every digest interval
+func (c *Client) handleForwardDigest() {
+	c.logger.Debug("starting forward digest handler")
+	defer c.wg.Done()
+
+	for {
+		// The interval is read on every iteration, so a reloaded config applies after the next digest
+		interval := c.config().EventConfig.ForwardEvent.DigestInterval
+
+		select {
+		case <-c.ctx.Done():
+			return
+		case <-time.After(interval):
+		}
+
+		if digest := c.forwards.flush(time.Now()); digest != nil {
+			c.logger.WithField("forwards", digest.Count).Debug("sending forward digest")
+			c.eventSub <- events.NewForwardDigestEvent(digest)
+		}
+	}
+}
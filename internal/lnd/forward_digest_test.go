@@ -0,0 +1,50 @@
+package lnd
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Primexz/lndnotify/internal/events"
+	"github.com/lightningnetwork/lnd/lnrpc"
+)
+
+func TestForwardCollector(t *testing.T) {
+	var collector forwardCollector
+
+	end := time.Now()
+	if digest := collector.flush(end); digest != nil {
+		t.Fatalf("flush() = %+v; want nil without forwards", digest)
+	}
+
+	collector.add(&lnrpc.ForwardingEvent{ChanIdIn: 1, ChanIdOut: 2, PeerAliasIn: "ACINQ", PeerAliasOut: "Kraken", AmtOutMsat: 1_000_000, FeeMsat: 100})
+	collector.add(&lnrpc.ForwardingEvent{ChanIdIn: 1, ChanIdOut: 2, PeerAliasIn: "ACINQ", PeerAliasOut: "Kraken", AmtOutMsat: 2_000_000, FeeMsat: 200})
+	collector.add(&lnrpc.ForwardingEvent{ChanIdIn: 2, ChanIdOut: 1, PeerAliasIn: "Kraken", PeerAliasOut: "ACINQ", AmtOutMsat: 500_000, FeeMsat: 500})
+
+	digest := collector.flush(end.Add(time.Hour))
+	if digest == nil {
+		t.Fatal("flush() = nil; want digest")
+	}
+	if !digest.Start.Equal(end) || !digest.End.Equal(end.Add(time.Hour)) {
+		t.Errorf("digest window = %v - %v; want %v - %v", digest.Start, digest.End, end, end.Add(time.Hour))
+	}
+	if digest.Count != 3 || digest.VolumeMsat != 3_500_000 || digest.FeesMsat != 800 {
+		t.Errorf("digest totals = %d, %d, %d; want 3, 3500000, 800", digest.Count, digest.VolumeMsat, digest.FeesMsat)
+	}
+
+	want := []events.ChannelPairSummary{
+		{InChanId: 2, OutChanId: 1, InAlias: "Kraken", OutAlias: "ACINQ", Count: 1, VolumeMsat: 500_000, FeesMsat: 500},
+		{InChanId: 1, OutChanId: 2, InAlias: "ACINQ", OutAlias: "Kraken", Count: 2, VolumeMsat: 3_000_000, FeesMsat: 300},
+	}
+	if len(digest.ChannelPairs) != len(want) {
+		t.Fatalf("got %d channel pairs; want %d", len(digest.ChannelPairs), len(want))
+	}
+	for i, pair := range digest.ChannelPairs {
+		if pair != want[i] {
+			t.Errorf("channel pair %d = %+v; want %+v", i, pair, want[i])
+		}
+	}
+
+	if digest := collector.flush(end.Add(2 * time.Hour)); digest != nil {
+		t.Errorf("flush() = %+v; want nil after the digest was sent", digest)
+	}
+}
@@ -5,9 +5,11 @@ import (
 	"crypto/x509"
 	"encoding/pem"
 	"os"
+	"slices"
 	"time"
 
 	"github.com/Primexz/lndnotify/internal/events"
+	"github.com/Primexz/lndnotify/internal/metrics"
 	"github.com/Primexz/lndnotify/pkg/format"
 	"github.com/Primexz/lndnotify/pkg/lndversion"
 	"github.com/cenkalti/backoff/v5"
@@ -17,13 +19,17 @@ import (
 	log "github.com/sirupsen/logrus"
 )
 
-// handleForwards polls for forwarding events
+// handleForwards polls for forwarding events. The poll position is checkpointed, so
+// forwards that happened while lndnotify was not running are sent after a restart.
 func (c *Client) handleForwards() {
-	log.Debug("starting forward event handler")
+	c.logger.Debug("starting forward event handler")
 	defer c.wg.Done()
 
-	var lastOffset uint32
-	start := time.Now()
+	var st forwardState
+	if !c.loadState(stateKeyForwards, &st) {
+		st.StartTime = time.Now().Unix()
+	}
+
 	ticker := time.NewTicker(time.Minute)
 	defer ticker.Stop()
 
@@ -32,47 +38,63 @@ func (c *Client) handleForwards() {
 		case <-c.ctx.Done():
 			return
 		case <-ticker.C:
-			log.WithFields(log.Fields{
-				"since":       start,
-				"last_offset": lastOffset,
-			}).Debug("polling for forwarding events")
-
-			resp, err := c.client.ForwardingHistory(c.ctx, &lnrpc.ForwardingHistoryRequest{
-				StartTime:       uint64(start.Unix()),
-				PeerAliasLookup: true,
-				IndexOffset:     lastOffset,
-			})
-			if err != nil {
-				log.WithError(err).Error("error fetching forwarding history")
-				continue
+			if err := c.pollForwards(&st); err != nil {
+				c.logger.WithError(err).Error("error fetching forwarding history")
 			}
+		}
+	}
+}
 
-			forwards := resp.GetForwardingEvents()
-			for _, fwd := range forwards {
-				c.eventSub <- events.NewForwardEvent(fwd)
-			}
+// pollForwards sends the forwards after the checkpoint and advances it
+func (c *Client) pollForwards(st *forwardState) error {
+	c.logger.WithFields(log.Fields{
+		"since":       time.Unix(st.StartTime, 0),
+		"last_offset": st.LastOffset,
+	}).Debug("polling for forwarding events")
+
+	resp, err := c.client.ForwardingHistory(c.ctx, &lnrpc.ForwardingHistoryRequest{
+		StartTime:       uint64(st.StartTime),
+		PeerAliasLookup: true,
+		IndexOffset:     st.LastOffset,
+	})
+	if err != nil {
+		return err
+	}
 
-			// push last offset for next request. lnd will return the current offset
-			// if no new events are available.
-			lastOffset = resp.LastOffsetIndex
+	forwardCfg := c.config().EventConfig.ForwardEvent
+	forwards := resp.GetForwardingEvents()
+	for _, fwd := range forwards {
+		if forwardCfg.Digest() && fwd.AmtOut >= forwardCfg.MinAmount {
+			c.forwards.add(fwd)
+		}
+		if forwardCfg.Individual() {
+			c.eventSub <- events.NewForwardEvent(fwd)
 		}
 	}
+
+	// push last offset for next request. lnd will return the current offset
+	// if no new events are available.
+	if resp.LastOffsetIndex != st.LastOffset {
+		st.LastOffset = resp.LastOffsetIndex
+		c.saveState(stateKeyForwards, *st)
+	}
+	return nil
 }
 
 // handlePeerEvents handles peer connection and disconnection events
 // Deprecated: Replace with channel_status
 func (c *Client) handlePeerEvents() {
-	log.Debug("starting peer event handler")
+	c.logger.Debug("starting peer event handler")
 	defer c.wg.Done()
 
-	// nolint:errcheck
-	retry(c.ctx, "peer event subscription", func() (string, error) {
+	c.subscribe("peer event subscription", func() (string, error) {
 		ev, err := c.client.SubscribePeerEvents(c.ctx, &lnrpc.PeerEventSubscription{})
 		if err != nil {
 			return "", err
 		}
 
-		log.Debug("peer event subscription established")
+		c.logger.Debug("peer event subscription established")
+		c.subscriptionEstablished("peer event subscription")
 
 		for {
 			select {
@@ -90,7 +112,7 @@ func (c *Client) handlePeerEvents() {
 				PubKey: peerEvent.GetPubKey(),
 			})
 			if err != nil {
-				log.WithField("pubkey", peerEvent.GetPubKey()).WithError(err).Warn("error fetching node info")
+				c.logger.WithField("pubkey", peerEvent.GetPubKey()).WithError(err).Warn("error fetching node info")
 			}
 
 			switch peerEvent.GetType() {
@@ -107,17 +129,17 @@ func (c *Client) handlePeerEvents() {
 
 // handleChannelEvents handles channel open and close events
 func (c *Client) handleChannelEvents() {
-	log.Debug("starting channel event handler")
+	c.logger.Debug("starting channel event handler")
 	defer c.wg.Done()
 
-	// nolint:errcheck
-	retry(c.ctx, "channel event subscription", func() (string, error) {
+	c.subscribe("channel event subscription", func() (string, error) {
 		ev, err := c.client.SubscribeChannelEvents(c.ctx, &lnrpc.ChannelEventSubscription{})
 		if err != nil {
 			return "", err
 		}
 
-		log.Debug("channel event subscription established")
+		c.logger.Debug("channel event subscription established")
+		c.subscriptionEstablished("channel event subscription")
 
 		for {
 			select {
@@ -131,29 +153,31 @@ func (c *Client) handleChannelEvents() {
 				return "", err // Return error to trigger retry
 			}
 
-			log.WithField("channel_event", chanEvent).Trace("received channel event")
+			c.logger.WithField("channel_event", chanEvent).Trace("received channel event")
 
 			switch chanEvent.GetType() {
 			case lnrpc.ChannelEventUpdate_PENDING_OPEN_CHANNEL:
 				c.pendChanManager.RefreshDelayed()
 			case lnrpc.ChannelEventUpdate_OPEN_CHANNEL:
 				channel := chanEvent.GetOpenChannel()
+				c.channelsOpened.Add(1)
 				nodeInfo, err := c.client.GetNodeInfo(c.ctx, &lnrpc.NodeInfoRequest{
 					PubKey: channel.RemotePubkey,
 				})
 				if err != nil {
-					log.WithError(err).Error("error fetching node info")
+					c.logger.WithError(err).Error("error fetching node info")
 					continue
 				}
 
 				c.eventSub <- events.NewChannelOpenEvent(nodeInfo.Node, channel)
 			case lnrpc.ChannelEventUpdate_CLOSED_CHANNEL:
 				channel := chanEvent.GetClosedChannel()
+				c.channelsClosed.Add(1)
 				nodeInfo, err := c.client.GetNodeInfo(c.ctx, &lnrpc.NodeInfoRequest{
 					PubKey: channel.RemotePubkey,
 				})
 				if err != nil {
-					log.WithError(err).Error("error fetching node info")
+					c.logger.WithError(err).Error("error fetching node info")
 					continue
 				}
 
@@ -164,7 +188,7 @@ func (c *Client) handleChannelEvents() {
 }
 
 func (c *Client) handleChannelFeeChanges() {
-	log.Debug("starting channel fee change handler")
+	c.logger.Debug("starting channel fee change handler")
 	defer c.wg.Done()
 
 	for {
@@ -177,18 +201,38 @@ func (c *Client) handleChannelFeeChanges() {
 	}
 }
 
+// handleInvoiceEvents handles settled invoices. The last seen settle index is passed on
+// resubscribe, so invoices settled during a disconnect or restart are sent as catch-up events.
 func (c *Client) handleInvoiceEvents() {
-	log.Debug("starting invoice event handler")
+	c.logger.Debug("starting invoice event handler")
 	defer c.wg.Done()
 
-	// nolint:errcheck
-	retry(c.ctx, "invoice event subscription", func() (string, error) {
-		ev, err := c.client.SubscribeInvoices(c.ctx, &lnrpc.InvoiceSubscription{})
+	var settleIndex uint64
+	hasSettleIndex := c.loadState(stateKeyInvoiceSettleIndex, &settleIndex)
+
+	c.subscribe("invoice event subscription", func() (string, error) {
+		// Without a known index, we start at the latest settled invoice instead of
+		// waiting for the next one, so invoices settled before a restart are not missed.
+		if !hasSettleIndex {
+			latest, err := c.latestSettleIndex()
+			if err != nil {
+				return "", err
+			}
+			settleIndex = latest
+			hasSettleIndex = true
+			c.saveState(stateKeyInvoiceSettleIndex, settleIndex)
+		}
+
+		subscribedAt := time.Now()
+		ev, err := c.client.SubscribeInvoices(c.ctx, &lnrpc.InvoiceSubscription{
+			SettleIndex: settleIndex,
+		})
 		if err != nil {
 			return "", err
 		}
 
-		log.Debug("invoice event subscription established")
+		c.logger.WithField("settle_index", settleIndex).Debug("invoice event subscription established")
+		c.subscriptionEstablished("invoice event subscription")
 
 		for {
 			select {
@@ -204,6 +248,11 @@ func (c *Client) handleInvoiceEvents() {
 
 			switch invoice.GetState() {
 			case lnrpc.Invoice_SETTLED:
+				isNew, catchUp := settledInvoice(invoice, settleIndex, subscribedAt)
+				if !isNew {
+					continue
+				}
+
 				// We check if there is a payment with this hash in our lnd instance.
 				// If yes, it is a rebalancing payment, so we do not send an invoice event.
 				ctx, cancel := context.WithCancel(c.ctx)
@@ -213,33 +262,60 @@ func (c *Client) handleInvoiceEvents() {
 
 				// If an error occurs here, we assume that there is no payment with this hash.
 				if err != nil {
-					c.eventSub <- events.NewInvoiceSettledEvent(invoice)
-					cancel()
-					continue
+					c.eventSub <- events.NewInvoiceSettledEvent(invoice, catchUp)
+				} else if _, err := stream.Recv(); err != nil {
+					// The rpc error "payment isn't initiated" is returned, when fetching the first
+					// element from the stream.
+					c.eventSub <- events.NewInvoiceSettledEvent(invoice, catchUp)
 				}
+				cancel()
 
-				// The rpc error "payment isn't initiated" is returned, when fetching the first
-				// element from the stream.
-				if _, err := stream.Recv(); err != nil {
-					c.eventSub <- events.NewInvoiceSettledEvent(invoice)
+				if invoice.SettleIndex > settleIndex {
+					settleIndex = invoice.SettleIndex
+					c.saveState(stateKeyInvoiceSettleIndex, settleIndex)
 				}
-				cancel()
 			}
 		}
 	})
 }
 
+// latestSettleIndex returns the highest settle index of all invoices. Invoices are listed
+// by add index and an older invoice can be settled last, so all invoices are checked.
+func (c *Client) latestSettleIndex() (uint64, error) {
+	var settleIndex, offset uint64
+	for {
+		resp, err := c.client.ListInvoices(c.ctx, &lnrpc.ListInvoiceRequest{
+			IndexOffset:    offset,
+			NumMaxInvoices: 1000,
+		})
+		if err != nil {
+			return 0, err
+		}
+
+		for _, invoice := range resp.Invoices {
+			settleIndex = max(settleIndex, invoice.SettleIndex)
+		}
+
+		if len(resp.Invoices) == 0 || resp.LastIndexOffset <= offset {
+			break
+		}
+		offset = resp.LastIndexOffset
+	}
+
+	return settleIndex, nil
+}
+
 func (c *Client) handleFailedHtlcEvents() {
-	log.Debug("starting failed htlc event handler")
+	c.logger.Debug("starting failed htlc event handler")
 	defer c.wg.Done()
 
-	// nolint:errcheck
-	retry(c.ctx, "htlc event subscription", func() (string, error) {
+	c.subscribe("htlc event subscription", func() (string, error) {
 		ev, err := c.router.SubscribeHtlcEvents(c.ctx, &routerrpc.SubscribeHtlcEventsRequest{})
 		if err != nil {
-			log.WithError(err).Error("error subscribing to failed htlc events")
+			c.logger.WithError(err).Error("error subscribing to failed htlc events")
 			return "", err
 		}
+		c.subscriptionEstablished("htlc event subscription")
 
 		for {
 			select {
@@ -254,32 +330,57 @@ func (c *Client) handleFailedHtlcEvents() {
 			}
 
 			if htlcEvent.GetEventType() != routerrpc.HtlcEvent_FORWARD {
-				log.WithField("htlc_event", htlcEvent).Trace("ignoring non-forward htlc event")
+				c.logger.WithField("htlc_event", htlcEvent).Trace("ignoring non-forward htlc event")
 				continue
 			}
 
 			linkFailEvent := htlcEvent.GetLinkFailEvent()
-			if linkFailEvent != nil {
+			if linkFailEvent == nil {
+				c.logger.WithField("htlc_event", htlcEvent).Trace("unhandled htlc event")
+				continue
+			}
+
+			failedHtlcCfg := c.config().EventConfig.FailedHtlcEvent
+			if failedHtlcCfg.Digest() && linkFailEvent.GetInfo().GetOutgoingAmtMsat()/1000 >= failedHtlcCfg.MinAmount {
+				c.addFailedHtlc(htlcEvent, linkFailEvent)
+			}
+			if failedHtlcCfg.Individual() {
 				c.eventSub <- events.NewFailedHtlcLinkEvent(htlcEvent, linkFailEvent, c.channelManager)
-			} else {
-				log.WithField("htlc_event", htlcEvent).Trace("unhandled htlc event")
 			}
 		}
 	})
 }
 
 func (c *Client) handleKeysendEvents() {
-	log.Debug("keysend event handler")
+	c.logger.Debug("keysend event handler")
 	defer c.wg.Done()
 
-	// nolint:errcheck
-	retry(c.ctx, "keysend event subscription", func() (string, error) {
-		ev, err := c.client.SubscribeInvoices(c.ctx, &lnrpc.InvoiceSubscription{})
+	var settleIndex uint64
+	hasSettleIndex := c.loadState(stateKeyKeysendSettleIndex, &settleIndex)
+
+	c.subscribe("keysend event subscription", func() (string, error) {
+		// Without a known index, we start at the latest settled invoice instead of
+		// waiting for the next one, so invoices settled before a restart are not missed.
+		if !hasSettleIndex {
+			latest, err := c.latestSettleIndex()
+			if err != nil {
+				return "", err
+			}
+			settleIndex = latest
+			hasSettleIndex = true
+			c.saveState(stateKeyKeysendSettleIndex, settleIndex)
+		}
+
+		subscribedAt := time.Now()
+		ev, err := c.client.SubscribeInvoices(c.ctx, &lnrpc.InvoiceSubscription{
+			SettleIndex: settleIndex,
+		})
 		if err != nil {
 			return "", err
 		}
 
-		log.Debug("keysend (invoice) event subscription established")
+		c.logger.Debug("keysend (invoice) event subscription established")
+		c.subscriptionEstablished("keysend event subscription")
 
 		for {
 			select {
@@ -296,6 +397,10 @@ func (c *Client) handleKeysendEvents() {
 			if invoice.GetState() != lnrpc.Invoice_SETTLED {
 				continue
 			}
+			isNew, catchUp := settledInvoice(invoice, settleIndex, subscribedAt)
+			if !isNew {
+				continue
+			}
 
 			htlcs := invoice.GetHtlcs()
 			for _, htlc := range htlcs {
@@ -306,20 +411,37 @@ func (c *Client) handleKeysendEvents() {
 					channel := c.channelManager.GetChannelById(htlc.ChanId)
 					msg := string(msgBuf)
 
-					c.eventSub <- events.NewKeysendEvent(msg, channel, htlc)
+					c.eventSub <- events.NewKeysendEvent(msg, channel, htlc, catchUp)
 					break
 				}
 			}
+
+			if invoice.SettleIndex > settleIndex {
+				settleIndex = invoice.SettleIndex
+				c.saveState(stateKeyKeysendSettleIndex, settleIndex)
+			}
 		}
 	})
 }
 
+// handlePaymentEvents handles succeeded payments. Payments that succeeded during a
+// disconnect or restart are fetched with ListPayments and sent as catch-up events. Payments
+// that were in flight at the checkpoint are kept and checked again on the next catch-up.
 func (c *Client) handlePaymentEvents() {
-	log.Debug("starting payment event handler")
+	c.logger.Debug("starting payment event handler")
 	defer c.wg.Done()
 
-	// nolint:errcheck
-	retry(c.ctx, "payment event subscription", func() (string, error) {
+	var paymentIndex uint64
+	hasPaymentIndex := c.loadState(stateKeyPaymentIndex, &paymentIndex)
+
+	var inFlightIndices []uint64
+	c.loadState(stateKeyPaymentsInFlight, &inFlightIndices)
+	inFlight := make(map[uint64]struct{}, len(inFlightIndices))
+	for _, index := range inFlightIndices {
+		inFlight[index] = struct{}{}
+	}
+
+	c.subscribe("payment event subscription", func() (string, error) {
 		// Pubkey of the local node to distinguish between rebalancing and external payment
 		var localPubkey string
 		if info, err := c.client.GetInfo(c.ctx, &lnrpc.GetInfoRequest{}); err == nil {
@@ -333,7 +455,28 @@ func (c *Client) handlePaymentEvents() {
 			return "", err
 		}
 
-		log.Debug("payment event subscription established")
+		c.logger.Debug("payment event subscription established")
+		c.subscriptionEstablished("payment event subscription")
+
+		// Without a known index, we start at the latest payment instead of
+		// sending the whole payment history.
+		if !hasPaymentIndex {
+			resp, err := c.client.ListPayments(c.ctx, &lnrpc.ListPaymentsRequest{
+				Reversed:    true,
+				MaxPayments: 1,
+			})
+			if err != nil {
+				return "", err
+			}
+			paymentIndex = resp.LastIndexOffset
+			hasPaymentIndex = true
+			c.saveState(stateKeyPaymentIndex, paymentIndex)
+		}
+
+		caughtUp, err := c.catchUpPayments(&paymentIndex, inFlight, localPubkey)
+		if err != nil {
+			return "", err
+		}
 
 		for {
 			select {
@@ -349,42 +492,153 @@ func (c *Client) handlePaymentEvents() {
 
 			switch payment.Status {
 			case lnrpc.Payment_SUCCEEDED:
-				var payReq *lnrpc.PayReq
-				if payment.PaymentRequest != "" {
-					if decoded, err := c.client.DecodePayReq(c.ctx, &lnrpc.PayReqString{
-						PayReq: payment.PaymentRequest,
-					}); err == nil {
-						payReq = decoded
-					}
+				if _, ok := inFlight[payment.PaymentIndex]; ok {
+					delete(inFlight, payment.PaymentIndex)
+					c.savePaymentsInFlight(inFlight)
 				}
-
-				var recPubkey string
-				// The pub_key of the last hop (receiver of the payment) has to be identical
-				// for all htlcs. Hence we use the first htlc.
-				if len(payment.Htlcs) > 0 && len(payment.Htlcs[0].Route.Hops) > 0 {
-					lastHop := payment.Htlcs[0].Route.Hops[len(payment.Htlcs[0].Route.Hops)-1]
-					recPubkey = lastHop.PubKey
+				if _, ok := caughtUp[payment.PaymentIndex]; ok {
+					continue
 				}
 
-				isRebalancing := recPubkey == localPubkey
-				c.eventSub <- events.NewPaymentSucceededEvent(payment, payReq, isRebalancing, c.getAlias)
+				c.eventSub <- c.newPaymentSucceededEvent(payment, localPubkey, false)
+
+				if payment.PaymentIndex > paymentIndex {
+					paymentIndex = payment.PaymentIndex
+					c.saveState(stateKeyPaymentIndex, paymentIndex)
+				}
+			case lnrpc.Payment_FAILED:
+				if _, ok := inFlight[payment.PaymentIndex]; ok {
+					delete(inFlight, payment.PaymentIndex)
+					c.savePaymentsInFlight(inFlight)
+				}
+			default:
+				// Remember the payment, so it is still sent if it succeeds after a restart
+				if _, ok := inFlight[payment.PaymentIndex]; !ok {
+					inFlight[payment.PaymentIndex] = struct{}{}
+					c.savePaymentsInFlight(inFlight)
+				}
 			}
 		}
 	})
 }
 
+// catchUpPayments sends all payments that succeeded after the given payment index, and the
+// in-flight payments that succeeded in the meantime. It advances the index and updates the
+// in-flight payments. It returns the indices of the sent payments.
+func (c *Client) catchUpPayments(paymentIndex *uint64, inFlight map[uint64]struct{}, localPubkey string) (map[uint64]struct{}, error) {
+	sent := make(map[uint64]struct{})
+
+	indices := make([]uint64, 0, len(inFlight))
+	for index := range inFlight {
+		indices = append(indices, index)
+	}
+	slices.Sort(indices)
+
+	for _, index := range indices {
+		resp, err := c.client.ListPayments(c.ctx, &lnrpc.ListPaymentsRequest{
+			IncludeIncomplete: true,
+			IndexOffset:       index - 1,
+			MaxPayments:       1,
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		// A payment that no longer exists is forgotten
+		if len(resp.Payments) == 0 || resp.Payments[0].PaymentIndex != index {
+			delete(inFlight, index)
+			continue
+		}
+
+		switch payment := resp.Payments[0]; payment.Status {
+		case lnrpc.Payment_SUCCEEDED:
+			c.logger.WithField("payment_index", index).Debug("sending missed payment")
+			c.eventSub <- c.newPaymentSucceededEvent(payment, localPubkey, true)
+			sent[index] = struct{}{}
+			delete(inFlight, index)
+		case lnrpc.Payment_FAILED:
+			delete(inFlight, index)
+		}
+	}
+	c.savePaymentsInFlight(inFlight)
+
+	for {
+		resp, err := c.client.ListPayments(c.ctx, &lnrpc.ListPaymentsRequest{
+			IncludeIncomplete: true,
+			IndexOffset:       *paymentIndex,
+			MaxPayments:       100,
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		for _, payment := range resp.Payments {
+			switch payment.Status {
+			case lnrpc.Payment_SUCCEEDED:
+				c.logger.WithField("payment_index", payment.PaymentIndex).Debug("sending missed payment")
+				c.eventSub <- c.newPaymentSucceededEvent(payment, localPubkey, true)
+				sent[payment.PaymentIndex] = struct{}{}
+			case lnrpc.Payment_FAILED:
+			default:
+				inFlight[payment.PaymentIndex] = struct{}{}
+			}
+		}
+
+		if len(resp.Payments) == 0 || resp.LastIndexOffset <= *paymentIndex {
+			return sent, nil
+		}
+
+		// The in-flight payments are saved first, so none is lost if the index is saved
+		c.savePaymentsInFlight(inFlight)
+		*paymentIndex = resp.LastIndexOffset
+		c.saveState(stateKeyPaymentIndex, *paymentIndex)
+	}
+}
+
+// savePaymentsInFlight persists the indices of the payments that were in flight
+func (c *Client) savePaymentsInFlight(inFlight map[uint64]struct{}) {
+	indices := make([]uint64, 0, len(inFlight))
+	for index := range inFlight {
+		indices = append(indices, index)
+	}
+	slices.Sort(indices)
+	c.saveState(stateKeyPaymentsInFlight, indices)
+}
+
+func (c *Client) newPaymentSucceededEvent(payment *lnrpc.Payment, localPubkey string, catchUp bool) *events.PaymentSucceededEvent {
+	var payReq *lnrpc.PayReq
+	if payment.PaymentRequest != "" {
+		if decoded, err := c.client.DecodePayReq(c.ctx, &lnrpc.PayReqString{
+			PayReq: payment.PaymentRequest,
+		}); err == nil {
+			payReq = decoded
+		}
+	}
+
+	var recPubkey string
+	// The pub_key of the last hop (receiver of the payment) has to be identical
+	// for all htlcs. Hence we use the first htlc.
+	if len(payment.Htlcs) > 0 && len(payment.Htlcs[0].Route.Hops) > 0 {
+		lastHop := payment.Htlcs[0].Route.Hops[len(payment.Htlcs[0].Route.Hops)-1]
+		recPubkey = lastHop.PubKey
+	}
+
+	isRebalancing := recPubkey == localPubkey
+	return events.NewPaymentSucceededEvent(payment, payReq, isRebalancing, catchUp, c.getAlias)
+}
+
 func (c *Client) handleOnChainEvents() {
-	log.Debug("starting on chain event handler")
+	c.logger.Debug("starting on chain event handler")
 	defer c.wg.Done()
 
-	// nolint:errcheck
-	retry(c.ctx, "on chain event subscription", func() (string, error) {
+	c.subscribe("on chain event subscription", func() (string, error) {
 		ev, err := c.client.SubscribeTransactions(c.ctx, &lnrpc.GetTransactionsRequest{})
 		if err != nil {
 			return "", err
 		}
 
-		log.Debug("on chain event subscription established")
+		c.logger.Debug("on chain event subscription established")
+		c.subscriptionEstablished("on chain event subscription")
 
 		for {
 			select {
@@ -400,7 +654,7 @@ func (c *Client) handleOnChainEvents() {
 
 			confirmCnt := event.GetNumConfirmations()
 			if confirmCnt == 0 || confirmCnt == 1 {
-				c.eventSub <- events.NewOnChainTransactionEvent(event, c.cfg)
+				c.eventSub <- events.NewOnChainTransactionEvent(event, c.config())
 				c.pendChanManager.RefreshDelayed()
 			}
 		}
@@ -408,7 +662,7 @@ func (c *Client) handleOnChainEvents() {
 }
 
 func (c *Client) handlePendingChannels() {
-	log.Debug("starting pending channel event handler")
+	c.logger.Debug("starting pending channel event handler")
 	defer c.wg.Done()
 
 	for {
@@ -422,22 +676,19 @@ func (c *Client) handlePendingChannels() {
 			case *lnrpc.PendingChannelsResponse_WaitingCloseChannel:
 				c.eventSub <- events.NewChannelClosingEvent(ev, c.getAlias)
 			default:
-				log.WithField("update", update).Warn("unknown pending channel update type")
+				c.logger.WithField("update", update).Warn("unknown pending channel update type")
 			}
 		}
 	}
 }
 
 func (c *Client) handleChainSyncState() {
-	log.Debug("starting sync state event handler")
+	c.logger.Debug("starting sync state event handler")
 	defer c.wg.Done()
 
 	ticker := time.NewTicker(time.Minute)
 	defer ticker.Stop()
 
-	unsyncedThreshold := c.cfg.EventConfig.ChainLostEvent.Threshold
-	warningInterval := c.cfg.EventConfig.ChainLostEvent.WarningInterval
-
 	var lastUnsyncedTime *time.Time
 	var lastWarningTime *time.Time
 
@@ -446,35 +697,39 @@ func (c *Client) handleChainSyncState() {
 		case <-c.ctx.Done():
 			return
 		case <-ticker.C:
-			log.Debug("polling for sync state")
+			c.logger.Debug("polling for sync state")
 
 			info, err := c.client.GetInfo(c.ctx, &lnrpc.GetInfoRequest{})
 			if err != nil {
-				log.WithError(err).Error("error fetching node info")
+				c.logger.WithError(err).Error("error fetching node info")
 				continue
 			}
 
+			c.setSyncedToChain(info.GetSyncedToChain())
 			if info.GetSyncedToChain() {
+				metrics.ChainSynced.WithLabelValues(c.name).Set(1)
 				if lastWarningTime != nil {
-					log.Debug("chain sync restored")
+					c.logger.Debug("chain sync restored")
 					c.eventSub <- events.NewChainSyncRestoredEvent(time.Since(*lastUnsyncedTime))
 					lastWarningTime = nil
 				}
 				lastUnsyncedTime = nil
 			} else {
+				metrics.ChainSynced.WithLabelValues(c.name).Set(0)
 				now := time.Now()
 				if lastUnsyncedTime == nil {
 					// first time we detect chain is not synced
 					lastUnsyncedTime = &now
-					log.Debug("chain sync lost, starting timer")
+					c.logger.Debug("chain sync lost, starting timer")
 				} else {
+					chainLostCfg := c.config().EventConfig.ChainLostEvent
 					unsyncedDuration := now.Sub(*lastUnsyncedTime)
-					if unsyncedDuration >= unsyncedThreshold {
+					if unsyncedDuration >= chainLostCfg.Threshold {
 						shouldWarn := false
 						if lastWarningTime == nil {
 							// initial warning after threshold
 							shouldWarn = true
-						} else if now.Sub(*lastWarningTime) >= warningInterval {
+						} else if now.Sub(*lastWarningTime) >= chainLostCfg.WarningInterval {
 							// oh no, it's time for another warning
 							shouldWarn = true
 						}
@@ -492,17 +747,17 @@ func (c *Client) handleChainSyncState() {
 }
 
 func (c *Client) handleBackupEvents() {
-	log.Debug("starting backup event handler")
+	c.logger.Debug("starting backup event handler")
 	defer c.wg.Done()
 
-	// nolint:errcheck
-	retry(c.ctx, "channel backup subscription", func() (string, error) {
+	c.subscribe("channel backup subscription", func() (string, error) {
 		ev, err := c.client.SubscribeChannelBackups(c.ctx, &lnrpc.ChannelBackupSubscription{})
 		if err != nil {
 			return "", err
 		}
 
-		log.Debug("channel backup subscription established")
+		c.logger.Debug("channel backup subscription established")
+		c.subscriptionEstablished("channel backup subscription")
 
 		for {
 			select {
@@ -530,7 +785,7 @@ func (c *Client) handleChannelStatusEvents() {
 	ticker := time.NewTicker(time.Minute)
 	defer ticker.Stop()
 
-	log.Debug("starting channel status event handler")
+	c.logger.Debug("starting channel status event handler")
 	defer c.wg.Done()
 
 	for {
@@ -538,14 +793,14 @@ func (c *Client) handleChannelStatusEvents() {
 		case <-c.ctx.Done():
 			return
 		case <-ticker.C:
-			log.Debug("checking channel status")
+			c.logger.Debug("checking channel status")
 
 			channels := c.channelManager.GetAllChannels()
 			now := time.Now()
 
 			for _, channel := range channels {
 				chanId := channel.ChanId
-				logger := log.WithField("channel_id", chanId)
+				logger := c.logger.WithField("channel_id", chanId)
 
 				if channel.GetActive() {
 					// Channel is active
@@ -565,7 +820,7 @@ func (c *Client) handleChannelStatusEvents() {
 
 						logger.Debug("channel is still down, checking duration")
 
-						if downDuration >= c.cfg.EventConfig.ChannelStatusEvent.MinDowntime && !notifiedChannels[chanId] {
+						if downDuration >= c.config().EventConfig.ChannelStatusEvent.MinDowntime && !notifiedChannels[chanId] {
 							logger.Debug("channel has been down long enough, sending event")
 							c.eventSub <- events.NewChannelStatusDownEvent(channel, downDuration, c.getAlias)
 							notifiedChannels[chanId] = true
@@ -584,7 +839,7 @@ func (c *Client) handleChannelStatusEvents() {
 
 // TODO: https://github.com/Primexz/lndnotify/issues/35
 func (c *Client) handleTLSCertExpiry() {
-	log.Debug("starting tls cert expiry handler")
+	c.logger.Debug("starting tls cert expiry handler")
 	defer c.wg.Done()
 
 	ticker := time.NewTicker(1 * time.Hour)
@@ -595,10 +850,10 @@ func (c *Client) handleTLSCertExpiry() {
 		case <-c.ctx.Done():
 			return
 		case <-ticker.C:
-			certPath := c.cfg.LND.TLSCertPath
-			logger := log.WithField("cert_path", certPath)
+			certPath := c.config().LND.TLSCertPath
+			logger := c.logger.WithField("cert_path", certPath)
 
-			log.Debug("checking tls cert expiry")
+			c.logger.Debug("checking tls cert expiry")
 
 			// #nosec G304
 			certData, err := os.ReadFile(certPath)
@@ -620,7 +875,7 @@ func (c *Client) handleTLSCertExpiry() {
 			}
 
 			timeUntilExpiry := time.Until(cert.NotAfter)
-			if timeUntilExpiry <= c.cfg.EventConfig.TLSCertExpiryEvent.Threshold {
+			if timeUntilExpiry <= c.config().EventConfig.TLSCertExpiryEvent.Threshold {
 				logger.WithField("expires_at", cert.NotAfter).Info("tls certificate is expiring soon")
 				c.eventSub <- events.NewTLSCertExpiryEvent(cert.NotAfter)
 			} else {
@@ -632,20 +887,20 @@ func (c *Client) handleTLSCertExpiry() {
 
 // handleLndWalletState handles wallet state change events
 func (c *Client) handleLndWalletState() {
-	log.Debug("starting wallet state event handler")
+	c.logger.Debug("starting wallet state event handler")
 	defer c.wg.Done()
 
 	var lastState lnrpc.WalletState
 	var initialEvent = true
 
-	// nolint:errcheck
-	retry(c.ctx, "wallet state subscription", func() (string, error) {
+	c.subscribe("wallet state subscription", func() (string, error) {
 		ev, err := c.state.SubscribeState(c.ctx, &lnrpc.SubscribeStateRequest{})
 		if err != nil {
 			return "", err
 		}
 
-		log.Debug("wallet state subscription established")
+		c.logger.Debug("wallet state subscription established")
+		c.subscriptionEstablished("wallet state subscription")
 
 		for {
 			select {
@@ -659,7 +914,7 @@ func (c *Client) handleLndWalletState() {
 				return "", err
 			}
 
-			log.WithField("wallet_state", peerEvent).Trace("received wallet state event")
+			c.logger.WithField("wallet_state", peerEvent).Trace("received wallet state event")
 
 			if initialEvent {
 				initialEvent = false
@@ -674,7 +929,7 @@ func (c *Client) handleLndWalletState() {
 }
 
 func (c *Client) handleLndHealth() {
-	log.Debug("starting lnd health event handler")
+	c.logger.Debug("starting lnd health event handler")
 	defer c.wg.Done()
 
 	ticker := time.NewTicker(time.Minute)
@@ -687,17 +942,18 @@ func (c *Client) handleLndHealth() {
 		case <-c.ctx.Done():
 			return
 		case <-ticker.C:
-			log.Debug("checking lnd health")
+			c.logger.Debug("checking lnd health")
 
 			_, err := c.client.GetInfo(c.ctx, &lnrpc.GetInfoRequest{})
 			healthy := err == nil
+			c.setHealthy(healthy)
 
 			if healthy != lastHealthyState {
 				if healthy {
-					log.Info("lnd is healthy again")
+					c.logger.Info("lnd is healthy again")
 					c.eventSub <- events.NewLndHealthyEvent()
 				} else {
-					log.WithError(err).Warn("lnd is unhealthy")
+					c.logger.WithError(err).Warn("lnd is unhealthy")
 					c.eventSub <- events.NewLndUnhealthyEvent(err)
 				}
 				lastHealthyState = healthy
@@ -707,143 +963,189 @@ func (c *Client) handleLndHealth() {
 }
 
 func (c *Client) handeLndVersion() {
-	log.Debug("starting lnd version event handler")
+	c.logger.Debug("starting lnd version event handler")
 	defer c.wg.Done()
 
 	ticker := time.NewTicker(24 * time.Hour)
 	defer ticker.Stop()
 
-	lastInformedVersion := ""
+	var lastInformedVersion string
+	c.loadState(stateKeyLndVersion, &lastInformedVersion)
 
 	for {
 		select {
 		case <-c.ctx.Done():
 			return
 		case <-ticker.C:
-			log.Debug("checking lnd version")
+			c.logger.Debug("checking lnd version")
 
 			info, err := c.client.GetInfo(c.ctx, &lnrpc.GetInfoRequest{})
 			if err != nil {
-				log.WithError(err).Error("error fetching lnd info")
+				c.logger.WithError(err).Error("error fetching lnd info")
 				continue
 			}
 
 			outdated, localVersion, latestVersion, err := lndversion.CheckVersion(info.Version)
 			if err != nil {
-				log.WithError(err).Error("error checking lnd version")
+				c.logger.WithError(err).Error("error checking lnd version")
 				continue
 			}
-
-			logger := log.WithFields(log.Fields{
-				"local_version":  localVersion,
-				"latest_version": latestVersion,
-			})
-
 			if !outdated {
-				logger.Debug("lnd is up to date")
-				continue
-			}
-
-			if lastInformedVersion == latestVersion.String() {
-				logger.Debug("already informed about this lnd version")
+				c.logger.WithField("local_version", localVersion).Debug("lnd is up to date")
 				continue
 			}
-			lastInformedVersion = latestVersion.String()
 
-			c.eventSub <- events.NewLndUpdateAvailableEvent(latestVersion, localVersion)
+			c.informLndUpdate(&lastInformedVersion, localVersion, latestVersion)
 		}
 	}
 }
 
+// informLndUpdate sends an update event for an outdated lnd, once per latest version
+func (c *Client) informLndUpdate(lastInformedVersion *string, localVersion, latestVersion *lndversion.LndVersion) {
+	logger := c.logger.WithFields(log.Fields{
+		"local_version":  localVersion,
+		"latest_version": latestVersion,
+	})
+
+	if *lastInformedVersion == latestVersion.String() {
+		logger.Debug("already informed about this lnd version")
+		return
+	}
+	*lastInformedVersion = latestVersion.String()
+	c.saveState(stateKeyLndVersion, *lastInformedVersion)
+
+	c.eventSub <- events.NewLndUpdateAvailableEvent(latestVersion, localVersion)
+}
+
 func (c *Client) handlePendingHTLCs() {
-	log.Debug("starting pending htlc event handler")
+	c.logger.Debug("starting pending htlc event handler")
 	defer c.wg.Done()
 
 	notifiedHtlcs := make(map[uint64]map[uint64]bool) // channelID -> htlcIndex -> notified
+	c.loadState(stateKeyPendingHTLCs, &notifiedHtlcs)
+
+	refreshCh := c.channelManager.SubscribeRefresh()
 
 	for {
 		select {
 		case <-c.ctx.Done():
 			return
-		case <-c.channelManager.GetRefreshChannel():
-			log.Debug("checking for pending htlcs")
-
-			blockResp, err := c.chain.GetBestBlock(c.ctx, &chainrpc.GetBestBlockRequest{})
+		case <-refreshCh:
+			stillPending, err := c.checkPendingHTLCs(notifiedHtlcs)
 			if err != nil {
-				log.WithError(err).Error("error fetching best block")
+				c.logger.WithError(err).Error("error fetching best block")
 				continue
 			}
-			currentHeight := blockResp.GetBlockHeight()
+			notifiedHtlcs = stillPending
+		}
+	}
+}
 
-			pendingHtlcs := c.channelManager.GetPendingHTLCs()
-			for ch, htlcs := range pendingHtlcs {
-				for _, htlc := range htlcs {
-					if _, exists := notifiedHtlcs[ch.ChanId]; !exists {
-						notifiedHtlcs[ch.ChanId] = make(map[uint64]bool)
-					}
+// checkPendingHTLCs sends an expiration event for every pending HTLC that was not notified
+// before. It returns the HTLCs that are still pending, resolved ones can't show up again.
+func (c *Client) checkPendingHTLCs(notifiedHtlcs map[uint64]map[uint64]bool) (map[uint64]map[uint64]bool, error) {
+	c.logger.Debug("checking for pending htlcs")
 
-					if _, notified := notifiedHtlcs[ch.ChanId][htlc.HtlcIndex]; notified {
-						continue
-					}
+	blockResp, err := c.chain.GetBestBlock(c.ctx, &chainrpc.GetBestBlockRequest{})
+	if err != nil {
+		return nil, err
+	}
+	currentHeight := blockResp.GetBlockHeight()
+
+	pendingHtlcs := c.channelManager.GetPendingHTLCs()
+	stillPending := make(map[uint64]map[uint64]bool)
+	for ch, htlcs := range pendingHtlcs {
+		for _, htlc := range htlcs {
+			if _, exists := stillPending[ch.ChanId]; !exists {
+				stillPending[ch.ChanId] = make(map[uint64]bool)
+			}
+			stillPending[ch.ChanId][htlc.HtlcIndex] = true
 
-					notifiedHtlcs[ch.ChanId][htlc.HtlcIndex] = true
+			if _, notified := notifiedHtlcs[ch.ChanId][htlc.HtlcIndex]; notified {
+				continue
+			}
 
-					log.WithFields(log.Fields{
-						"channel_id":        ch.ChanId,
-						"htlc_index":        htlc.HtlcIndex,
-						"expiration_height": htlc.ExpirationHeight,
-						"current_height":    currentHeight,
-					}).Info("pending htlc detected")
+			c.logger.WithFields(log.Fields{
+				"channel_id":        ch.ChanId,
+				"htlc_index":        htlc.HtlcIndex,
+				"expiration_height": htlc.ExpirationHeight,
+				"current_height":    currentHeight,
+			}).Info("pending htlc detected")
 
-					remainingBlocks := int32(htlc.ExpirationHeight) - currentHeight // #nosec G115
-					c.eventSub <- events.NewHTLCExpirationEvent(htlc, ch, remainingBlocks)
-				}
-			}
+			remainingBlocks := int32(htlc.ExpirationHeight) - currentHeight // #nosec G115
+			c.eventSub <- events.NewHTLCExpirationEvent(htlc, ch, remainingBlocks)
 		}
 	}
+
+	c.saveState(stateKeyPendingHTLCs, stillPending)
+	return stillPending, nil
 }
 
 func (c *Client) handleAliasChanges() {
-	log.Debug("starting alias change event handler")
+	c.logger.Debug("starting alias change event handler")
 	defer c.wg.Done()
 
 	ticker := time.NewTicker(10 * time.Minute)
 	defer ticker.Stop()
 
 	aliasMap := make(map[string]string) // pubkey -> alias
+	c.loadState(stateKeyAliases, &aliasMap)
 
 	for {
 		select {
 		case <-c.ctx.Done():
 			return
 		case <-ticker.C:
-			log.Debug("checking for alias changes")
-
-			peersResp, err := c.client.ListPeers(c.ctx, &lnrpc.ListPeersRequest{})
-			if err != nil {
-				log.WithError(err).Error("error fetching peer list")
-				continue
+			if err := c.checkAliasChanges(aliasMap); err != nil {
+				c.logger.WithError(err).Error("error fetching peer list")
 			}
+		}
+	}
+}
 
-			for _, peer := range peersResp.Peers {
-				pubkey := peer.PubKey
-				currentAlias := c.getAlias(pubkey)
+// checkAliasChanges sends an event for every peer whose alias differs from the known one
+// and updates the known aliases
+func (c *Client) checkAliasChanges(aliasMap map[string]string) error {
+	c.logger.Debug("checking for alias changes")
 
-				if oldAlias, exists := aliasMap[pubkey]; exists {
-					if oldAlias != currentAlias {
-						log.WithFields(log.Fields{
-							"pubkey":    pubkey,
-							"old_alias": oldAlias,
-							"new_alias": currentAlias,
-						}).Info("alias change detected")
-						c.eventSub <- events.NewAliasChangedEvent(pubkey, oldAlias, currentAlias)
-					}
-				}
+	peersResp, err := c.client.ListPeers(c.ctx, &lnrpc.ListPeersRequest{})
+	if err != nil {
+		return err
+	}
 
-				aliasMap[pubkey] = currentAlias
+	for _, peer := range peersResp.Peers {
+		pubkey := peer.PubKey
+		currentAlias := c.getAlias(pubkey)
+
+		if oldAlias, exists := aliasMap[pubkey]; exists {
+			if oldAlias != currentAlias {
+				c.logger.WithFields(log.Fields{
+					"pubkey":    pubkey,
+					"old_alias": oldAlias,
+					"new_alias": currentAlias,
+				}).Info("alias change detected")
+				c.eventSub <- events.NewAliasChangedEvent(pubkey, oldAlias, currentAlias)
 			}
 		}
+
+		aliasMap[pubkey] = currentAlias
+	}
+
+	c.saveState(stateKeyAliases, aliasMap)
+	return nil
+}
+
+// settledInvoice reports whether a settled invoice of the subscription is new, i.e. it was
+// not handled before the given settle index, and whether it was settled before the
+// subscription was established, i.e. it was missed while lndnotify was disconnected.
+// Delivery only depends on the settle index. The settle date of LND only has a precision of
+// seconds, so an invoice settled in the same second the subscription was established is not
+// marked as catch-up, while an invoice settled after the subscription never is.
+func settledInvoice(invoice *lnrpc.Invoice, settleIndex uint64, subscribedAt time.Time) (isNew bool, catchUp bool) {
+	if invoice.SettleIndex != 0 && invoice.SettleIndex <= settleIndex {
+		return false, false
 	}
+	return true, invoice.SettleDate < subscribedAt.Unix()
 }
 
 // getAlias returns the alias for a given pubkey. If an error occurs, it returns the first
@@ -857,15 +1159,16 @@ func (c *Client) getAlias(pubkey string) string {
 	return format.FormatPubKey(pubkey)
 }
 
-func retry[T any](ctx context.Context, name string, operation backoff.Operation[T]) (T, error) {
-	logger := log.WithField("name", name)
+func retry[T any](c *Client, name string, operation backoff.Operation[T]) (T, error) {
+	logger := c.logger.WithField("name", name)
 	notify := func(err error, duration time.Duration) {
 		logger.WithError(err).WithField("next_retry_in", duration).Error("operation failed, retrying")
+		metrics.SubscriptionReconnects.WithLabelValues(name, c.name).Inc()
 	}
 
-	ret, err := backoff.Retry(ctx, operation, backoff.WithNotify(notify), backoff.WithMaxElapsedTime(0))
+	ret, err := backoff.Retry(c.ctx, operation, backoff.WithNotify(notify), backoff.WithMaxElapsedTime(0))
 	if err != nil {
-		if ctx.Err() != nil {
+		if c.ctx.Err() != nil {
 			logger.Debug("context cancelled, stopping retry")
 		} else {
 			logger.WithError(err).Error("operation failed permanently")
@@ -0,0 +1,256 @@
+package lnd
+
+import (
+	"context"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/Primexz/lndnotify/internal/events"
+	"github.com/Primexz/lndnotify/internal/state"
+	"github.com/lightningnetwork/lnd/lnrpc"
+	log "github.com/sirupsen/logrus"
+	"google.golang.org/grpc"
+)
+
+// fakeLightning serves ListPayments, ListInvoices, ListChannels, ForwardingHistory and ListPeers
+// from static lists, payments and invoices are ordered by index. Peers are keyed by pubkey and hold the alias.
+type fakeLightning struct {
+	lnrpc.LightningClient
+	payments []*lnrpc.Payment
+	channels []*lnrpc.Channel
+	forwards []*lnrpc.ForwardingEvent
+	invoices []*lnrpc.Invoice
+	peers    map[string]string
+}
+
+func (f *fakeLightning) ListInvoices(ctx context.Context, in *lnrpc.ListInvoiceRequest, opts ...grpc.CallOption) (*lnrpc.ListInvoiceResponse, error) {
+	resp := &lnrpc.ListInvoiceResponse{LastIndexOffset: in.IndexOffset}
+	for _, invoice := range f.invoices {
+		if invoice.AddIndex <= in.IndexOffset {
+			continue
+		}
+		if uint64(len(resp.Invoices)) == in.NumMaxInvoices {
+			break
+		}
+		resp.Invoices = append(resp.Invoices, invoice)
+		resp.LastIndexOffset = invoice.AddIndex
+	}
+	return resp, nil
+}
+
+func (f *fakeLightning) ForwardingHistory(ctx context.Context, in *lnrpc.ForwardingHistoryRequest, opts ...grpc.CallOption) (*lnrpc.ForwardingHistoryResponse, error) {
+	offset := min(int(in.IndexOffset), len(f.forwards))
+	return &lnrpc.ForwardingHistoryResponse{
+		ForwardingEvents: f.forwards[offset:],
+		LastOffsetIndex:  uint32(len(f.forwards)), // #nosec G115
+	}, nil
+}
+
+func (f *fakeLightning) ListPeers(ctx context.Context, in *lnrpc.ListPeersRequest, opts ...grpc.CallOption) (*lnrpc.ListPeersResponse, error) {
+	resp := &lnrpc.ListPeersResponse{}
+	for pubkey := range f.peers {
+		resp.Peers = append(resp.Peers, &lnrpc.Peer{PubKey: pubkey})
+	}
+	return resp, nil
+}
+
+func (f *fakeLightning) GetNodeInfo(ctx context.Context, in *lnrpc.NodeInfoRequest, opts ...grpc.CallOption) (*lnrpc.NodeInfo, error) {
+	return &lnrpc.NodeInfo{Node: &lnrpc.LightningNode{PubKey: in.PubKey, Alias: f.peers[in.PubKey]}}, nil
+}
+
+func (f *fakeLightning) ListChannels(ctx context.Context, in *lnrpc.ListChannelsRequest, opts ...grpc.CallOption) (*lnrpc.ListChannelsResponse, error) {
+	return &lnrpc.ListChannelsResponse{Channels: f.channels}, nil
+}
+
+func (f *fakeLightning) GetChanInfo(ctx context.Context, in *lnrpc.ChanInfoRequest, opts ...grpc.CallOption) (*lnrpc.ChannelEdge, error) {
+	return &lnrpc.ChannelEdge{ChannelId: in.ChanId}, nil
+}
+
+func (f *fakeLightning) ListPayments(ctx context.Context, in *lnrpc.ListPaymentsRequest, opts ...grpc.CallOption) (*lnrpc.ListPaymentsResponse, error) {
+	resp := &lnrpc.ListPaymentsResponse{LastIndexOffset: in.IndexOffset}
+	for _, payment := range f.payments {
+		if payment.PaymentIndex <= in.IndexOffset {
+			continue
+		}
+		if !in.IncludeIncomplete && payment.Status != lnrpc.Payment_SUCCEEDED {
+			continue
+		}
+		if in.MaxPayments != 0 && uint64(len(resp.Payments)) == in.MaxPayments {
+			break
+		}
+		resp.Payments = append(resp.Payments, payment)
+		resp.LastIndexOffset = payment.PaymentIndex
+	}
+	return resp, nil
+}
+
+func newTestPaymentClient(t *testing.T, payments ...*lnrpc.Payment) *Client {
+	t.Helper()
+
+	store, err := state.NewStore("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &Client{
+		store:    store,
+		logger:   log.NewEntry(log.StandardLogger()),
+		client:   &fakeLightning{payments: payments},
+		eventSub: make(chan events.Event, 100),
+		ctx:      context.Background(),
+	}
+}
+
+func sentPayments(c *Client) []uint64 {
+	var sent []uint64
+	for {
+		select {
+		case event := <-c.eventSub:
+			sent = append(sent, event.(*events.PaymentSucceededEvent).Payment.PaymentIndex)
+		default:
+			return sent
+		}
+	}
+}
+
+func TestCatchUpPayments(t *testing.T) {
+	payment := func(index uint64, status lnrpc.Payment_PaymentStatus) *lnrpc.Payment {
+		return &lnrpc.Payment{PaymentIndex: index, Status: status}
+	}
+
+	tests := []struct {
+		name         string
+		payments     []*lnrpc.Payment
+		paymentIndex uint64
+		inFlight     []uint64
+		wantSent     []uint64
+		wantIndex    uint64
+		wantInFlight []uint64
+	}{
+		{
+			name:         "succeeded after index",
+			payments:     []*lnrpc.Payment{payment(1, lnrpc.Payment_SUCCEEDED), payment(2, lnrpc.Payment_SUCCEEDED), payment(3, lnrpc.Payment_FAILED)},
+			paymentIndex: 1,
+			wantSent:     []uint64{2},
+			wantIndex:    3,
+		},
+		{
+			name:         "in flight is kept",
+			payments:     []*lnrpc.Payment{payment(1, lnrpc.Payment_IN_FLIGHT), payment(2, lnrpc.Payment_SUCCEEDED), payment(3, lnrpc.Payment_INITIATED)},
+			wantSent:     []uint64{2},
+			wantIndex:    3,
+			wantInFlight: []uint64{1, 3},
+		},
+		{
+			name:         "in flight succeeded while down",
+			payments:     []*lnrpc.Payment{payment(1, lnrpc.Payment_SUCCEEDED), payment(2, lnrpc.Payment_SUCCEEDED), payment(3, lnrpc.Payment_SUCCEEDED)},
+			paymentIndex: 2,
+			inFlight:     []uint64{1},
+			wantSent:     []uint64{1, 3},
+			wantIndex:    3,
+		},
+		{
+			name:         "in flight failed or still in flight",
+			payments:     []*lnrpc.Payment{payment(1, lnrpc.Payment_FAILED), payment(2, lnrpc.Payment_IN_FLIGHT), payment(3, lnrpc.Payment_SUCCEEDED)},
+			paymentIndex: 3,
+			inFlight:     []uint64{1, 2},
+			wantIndex:    3,
+			wantInFlight: []uint64{2},
+		},
+		{
+			name:         "deleted in flight payment",
+			payments:     []*lnrpc.Payment{payment(2, lnrpc.Payment_SUCCEEDED)},
+			paymentIndex: 2,
+			inFlight:     []uint64{1},
+			wantIndex:    2,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := newTestPaymentClient(t, tt.payments...)
+
+			paymentIndex := tt.paymentIndex
+			inFlight := make(map[uint64]struct{})
+			for _, index := range tt.inFlight {
+				inFlight[index] = struct{}{}
+			}
+
+			caughtUp, err := c.catchUpPayments(&paymentIndex, inFlight, "")
+			if err != nil {
+				t.Fatalf("catchUpPayments() error = %v", err)
+			}
+
+			if sent := sentPayments(c); !slices.Equal(sent, tt.wantSent) {
+				t.Errorf("sent payments = %v; want %v", sent, tt.wantSent)
+			}
+			if len(caughtUp) != len(tt.wantSent) {
+				t.Errorf("caught up payments = %v; want %v", caughtUp, tt.wantSent)
+			}
+			if paymentIndex != tt.wantIndex {
+				t.Errorf("payment index = %d; want %d", paymentIndex, tt.wantIndex)
+			}
+
+			var saved []uint64
+			c.loadState(stateKeyPaymentsInFlight, &saved)
+			if !slices.Equal(saved, tt.wantInFlight) {
+				t.Errorf("saved in-flight payments = %v; want %v", saved, tt.wantInFlight)
+			}
+			if len(inFlight) != len(tt.wantInFlight) {
+				t.Errorf("in-flight payments = %v; want %v", inFlight, tt.wantInFlight)
+			}
+		})
+	}
+}
+
+func TestSettledInvoice(t *testing.T) {
+	subscribedAt := time.Unix(1_000, 500_000_000)
+
+	tests := []struct {
+		name        string
+		settleIndex uint64
+		settleDate  int64
+		lastIndex   uint64
+		wantNew     bool
+		wantCatchUp bool
+	}{
+		{"missed", 5, 990, 4, true, true},
+		{"settled the second before", 5, 999, 4, true, true},
+		{"settled in the same second", 5, 1_000, 4, true, false},
+		{"live", 5, 1_001, 4, true, false},
+		{"already handled", 4, 990, 4, false, false},
+		{"first run", 1, 990, 0, true, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			invoice := &lnrpc.Invoice{SettleIndex: tt.settleIndex, SettleDate: tt.settleDate}
+			isNew, catchUp := settledInvoice(invoice, tt.lastIndex, subscribedAt)
+			if isNew != tt.wantNew || catchUp != tt.wantCatchUp {
+				t.Errorf("settledInvoice() = %v, %v; want %v, %v", isNew, catchUp, tt.wantNew, tt.wantCatchUp)
+			}
+		})
+	}
+}
+
+func TestLatestSettleIndex(t *testing.T) {
+	// An early invoice is settled last and the invoices span several pages
+	var invoices []*lnrpc.Invoice
+	for i := uint64(1); i <= 2500; i++ {
+		invoice := &lnrpc.Invoice{AddIndex: i}
+		if i%2 == 0 {
+			invoice.SettleIndex = i / 2
+		}
+		invoices = append(invoices, invoice)
+	}
+	invoices[2].SettleIndex = 1251
+
+	c := newTestPaymentClient(t)
+	c.client = &fakeLightning{invoices: invoices}
+
+	settleIndex, err := c.latestSettleIndex()
+	if err != nil {
+		t.Fatalf("latestSettleIndex() error = %v", err)
+	}
+	if settleIndex != 1251 {
+		t.Errorf("latestSettleIndex() = %d; want 1251", settleIndex)
+	}
+}
@@ -0,0 +1,92 @@
+package lnd
+
+import (
+	"github.com/Primexz/lndnotify/internal/events"
+	log "github.com/sirupsen/logrus"
+)
+
+// liquidityState is the imbalance state of a channel
+type liquidityState string
+
+const (
+	liquidityLow  liquidityState = "low"
+	liquidityHigh liquidityState = "high"
+)
+
+// handleLiquidity checks the local balance of every channel after each channel refresh
+// and sends an alert when it leaves the configured band and again when it recovers.
+func (c *Client) handleLiquidity() {
+	c.logger.Debug("starting liquidity event handler")
+	defer c.wg.Done()
+
+	imbalanced := make(map[uint64]liquidityState) // channelID -> state
+	c.loadState(stateKeyLiquidity, &imbalanced)
+
+	refreshCh := c.channelManager.SubscribeRefresh()
+
+	for {
+		select {
+		case <-c.ctx.Done():
+			return
+		case <-refreshCh:
+			c.logger.Debug("checking channel liquidity")
+
+			current := make(map[uint64]liquidityState)
+			for _, ch := range c.channelManager.GetAllChannels() {
+				total := ch.LocalBalance + ch.RemoteBalance
+				if total <= 0 {
+					continue
+				}
+				localPercent := float64(ch.LocalBalance) * 100 / float64(total)
+
+				cfg := c.config().EventConfig.LiquidityEvent
+				minLocal, maxLocal := cfg.Thresholds(ch.ChanId, ch.RemotePubkey)
+
+				old := imbalanced[ch.ChanId]
+				state := nextLiquidityState(old, localPercent, minLocal, maxLocal, *cfg.HysteresisPercent)
+				if state != "" {
+					current[ch.ChanId] = state
+				}
+				if state == old {
+					continue
+				}
+
+				logger := c.logger.WithFields(log.Fields{
+					"channel_id":    ch.ChanId,
+					"peer":          ch.RemotePubkey,
+					"local_percent": localPercent,
+				})
+
+				if state == "" {
+					logger.Info("channel liquidity recovered")
+					c.eventSub <- events.NewLiquidityRecoveredEvent(ch, localPercent)
+					continue
+				}
+
+				logger.WithField("state", state).Info("channel liquidity imbalance detected")
+				c.eventSub <- events.NewLiquidityImbalanceEvent(ch, localPercent, minLocal, maxLocal)
+			}
+
+			// Closed channels are dropped, as they can't recover anymore.
+			imbalanced = current
+			c.saveState(stateKeyLiquidity, imbalanced)
+		}
+	}
+}
+
+// nextLiquidityState returns the imbalance state of a channel with the given local balance
+// percent. An imbalanced channel only recovers once it is inside the band by the hysteresis,
+// so channels around a threshold don't flap.
+func nextLiquidityState(old liquidityState, localPercent, minLocal, maxLocal, hysteresis float64) liquidityState {
+	switch {
+	case localPercent < minLocal:
+		return liquidityLow
+	case localPercent > maxLocal:
+		return liquidityHigh
+	case old == liquidityLow && localPercent < minLocal+hysteresis:
+		return liquidityLow
+	case old == liquidityHigh && localPercent > maxLocal-hysteresis:
+		return liquidityHigh
+	}
+	return ""
+}
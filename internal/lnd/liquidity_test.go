@@ -0,0 +1,35 @@
+package lnd
+
+import "testing"
+
+func TestNextLiquidityState(t *testing.T) {
+	tests := []struct {
+		name         string
+		old          liquidityState
+		localPercent float64
+		hysteresis   float64
+		want         liquidityState
+	}{
+		{"inside band", "", 50, 5, ""},
+		{"on min threshold", "", 10, 5, ""},
+		{"on max threshold", "", 90, 5, ""},
+		{"crosses min", "", 9, 5, liquidityLow},
+		{"crosses max", "", 91, 5, liquidityHigh},
+		{"low stays low", liquidityLow, 5, 5, liquidityLow},
+		{"high stays high", liquidityHigh, 95, 5, liquidityHigh},
+		{"low within hysteresis", liquidityLow, 12, 5, liquidityLow},
+		{"high within hysteresis", liquidityHigh, 88, 5, liquidityHigh},
+		{"low recovers", liquidityLow, 15, 5, ""},
+		{"high recovers", liquidityHigh, 85, 5, ""},
+		{"low recovers without hysteresis", liquidityLow, 10, 0, ""},
+		{"low swings to high", liquidityLow, 91, 5, liquidityHigh},
+		{"high swings to low", liquidityHigh, 9, 5, liquidityLow},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := nextLiquidityState(tt.old, tt.localPercent, 10, 90, tt.hysteresis); got != tt.want {
+				t.Errorf("nextLiquidityState() = %q; want %q", got, tt.want)
+			}
+		})
+	}
+}
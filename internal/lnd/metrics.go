@@ -0,0 +1,66 @@
+package lnd
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var (
+	channelsDesc = prometheus.NewDesc(
+		"lndnotify_channels",
+		"Number of open channels by status.",
+		[]string{"status"}, nil,
+	)
+	localBalanceDesc = prometheus.NewDesc(
+		"lndnotify_channels_local_balance_sats",
+		"Sum of the local balance of all open channels in sats.",
+		nil, nil,
+	)
+	remoteBalanceDesc = prometheus.NewDesc(
+		"lndnotify_channels_remote_balance_sats",
+		"Sum of the remote balance of all open channels in sats.",
+		nil, nil,
+	)
+)
+
+// channelCollector exports the channel state cached by the channel manager
+type channelCollector struct {
+	client *Client
+}
+
+// Collector returns a Prometheus collector for the channels of the node
+func (c *Client) Collector() prometheus.Collector {
+	return &channelCollector{client: c}
+}
+
+func (cc *channelCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- channelsDesc
+	ch <- localBalanceDesc
+	ch <- remoteBalanceDesc
+}
+
+func (cc *channelCollector) Collect(ch chan<- prometheus.Metric) {
+	cc.client.mu.Lock()
+	cm := cc.client.channelManager
+	cc.client.mu.Unlock()
+
+	if cm == nil {
+		return
+	}
+
+	var active, inactive float64
+	var localBalance, remoteBalance int64
+	for _, channel := range cm.GetAllChannels() {
+		if channel.GetActive() {
+			active++
+		} else {
+			inactive++
+		}
+		localBalance += channel.GetLocalBalance()
+		remoteBalance += channel.GetRemoteBalance()
+	}
+
+	ch <- prometheus.MustNewConstMetric(channelsDesc, prometheus.GaugeValue, active, "active")
+	ch <- prometheus.MustNewConstMetric(channelsDesc, prometheus.GaugeValue, inactive, "inactive")
+	ch <- prometheus.MustNewConstMetric(localBalanceDesc, prometheus.GaugeValue, float64(localBalance))
+	ch <- prometheus.MustNewConstMetric(remoteBalanceDesc, prometheus.GaugeValue, float64(remoteBalance))
+}
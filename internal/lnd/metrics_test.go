@@ -0,0 +1,45 @@
+package lnd
+
+import (
+	"strings"
+	"testing"
+
+	channelmanager "github.com/Primexz/lndnotify/internal/channel_manager"
+	"github.com/lightningnetwork/lnd/lnrpc"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func TestChannelCollector(t *testing.T) {
+	c := &Client{}
+	collector := c.Collector()
+
+	// Nothing is exported before the channels are loaded
+	if count := testutil.CollectAndCount(collector); count != 0 {
+		t.Fatalf("collected %d metrics before connect; want 0", count)
+	}
+
+	c.channelManager = channelmanager.NewChannelManager(&fakeLightning{channels: []*lnrpc.Channel{
+		{ChanId: 1, Active: true, LocalBalance: 1_000_000, RemoteBalance: 500_000},
+		{ChanId: 2, Active: true, LocalBalance: 200_000, RemoteBalance: 300_000},
+		{ChanId: 3, Active: false, LocalBalance: 50_000, RemoteBalance: 0},
+	}})
+	if err := c.channelManager.RefreshNow(); err != nil {
+		t.Fatalf("RefreshNow() error = %v", err)
+	}
+
+	want := `
+# HELP lndnotify_channels Number of open channels by status.
+# TYPE lndnotify_channels gauge
+lndnotify_channels{status="active"} 2
+lndnotify_channels{status="inactive"} 1
+# HELP lndnotify_channels_local_balance_sats Sum of the local balance of all open channels in sats.
+# TYPE lndnotify_channels_local_balance_sats gauge
+lndnotify_channels_local_balance_sats 1.25e+06
+# HELP lndnotify_channels_remote_balance_sats Sum of the remote balance of all open channels in sats.
+# TYPE lndnotify_channels_remote_balance_sats gauge
+lndnotify_channels_remote_balance_sats 800000
+`
+	if err := testutil.CollectAndCompare(collector, strings.NewReader(want)); err != nil {
+		t.Error(err)
+	}
+}
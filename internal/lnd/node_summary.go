@@ -0,0 +1,268 @@
+package lnd
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/Primexz/lndnotify/internal/config"
+	"github.com/Primexz/lndnotify/internal/events"
+	"github.com/lightningnetwork/lnd/lnrpc"
+)
+
+const (
+	// summaryTopChannelPairs is the number of channel pairs listed in the node summary
+	summaryTopChannelPairs = 5
+
+	// summaryInvoiceLookback is added to the period when listing invoices, as invoices
+	// settled during the period may have been created before (lnd default expiry is 24h)
+	summaryInvoiceLookback = 24 * time.Hour
+)
+
+// handleNodeSummary sends a node summary on the configured daily or weekly schedule
+func (c *Client) handleNodeSummary() {
+	c.logger.Debug("starting node summary handler")
+	defer c.wg.Done()
+
+	for {
+		// The schedule is read on every iteration, so a reloaded config applies after the next summary
+		summaryCfg := c.config().EventConfig.NodeSummaryEvent
+		loc, err := summaryCfg.Location()
+		if err != nil {
+			c.logger.WithError(err).Error("invalid node summary timezone, using local time")
+			loc = time.Local
+		}
+
+		start, next := nextSummaryTime(time.Now().In(loc), summaryCfg)
+		c.logger.WithField("next_summary", next).Debug("scheduled node summary")
+
+		timer := time.NewTimer(time.Until(next))
+		select {
+		case <-c.ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+
+		// Channel events are counted continuously, so the counters are reset
+		// even if no summary is sent.
+		opened := int(c.channelsOpened.Swap(0))
+		closed := int(c.channelsClosed.Swap(0))
+
+		if !c.config().Events.NodeSummaryEvents {
+			continue
+		}
+
+		summary, err := c.collectNodeSummary(start, next)
+		if err != nil {
+			c.logger.WithError(err).Error("error collecting node summary")
+			continue
+		}
+		summary.ChannelsOpened = opened
+		summary.ChannelsClosed = closed
+		if summaryCfg.Schedule == "weekly" {
+			summary.Period = "Weekly"
+		} else {
+			summary.Period = "Daily"
+		}
+
+		c.eventSub <- events.NewNodeSummaryEvent(summary)
+	}
+}
+
+// nextSummaryTime returns the start of the reporting period and the next time a summary
+// is due after now. Both are computed on the calendar of now's location, so a period
+// spanning a daylight saving time change is an hour shorter or longer.
+func nextSummaryTime(now time.Time, cfg config.NodeSummaryEventConfig) (time.Time, time.Time) {
+	clock, err := time.Parse("15:04", cfg.Time)
+	if err != nil {
+		clock = time.Date(0, 1, 1, 8, 0, 0, 0, time.UTC)
+	}
+
+	next := time.Date(now.Year(), now.Month(), now.Day(), clock.Hour(), clock.Minute(), 0, 0, now.Location())
+
+	if cfg.Schedule == "weekly" {
+		weekday, err := config.ParseWeekday(cfg.Weekday)
+		if err != nil {
+			weekday = time.Monday
+		}
+
+		next = next.AddDate(0, 0, (int(weekday)-int(next.Weekday())+7)%7)
+		if !next.After(now) {
+			next = next.AddDate(0, 0, 7)
+		}
+		return next.AddDate(0, 0, -7), next
+	}
+
+	if !next.After(now) {
+		next = next.AddDate(0, 0, 1)
+	}
+	return next.AddDate(0, 0, -1), next
+}
+
+// collectNodeSummary gathers forwarding, payment, invoice and wallet statistics of the given period
+func (c *Client) collectNodeSummary(start, end time.Time) (*events.NodeSummary, error) {
+	summary := &events.NodeSummary{
+		Start: start,
+		End:   end,
+	}
+
+	if err := c.summarizeForwards(summary); err != nil {
+		return nil, fmt.Errorf("fetching forwarding history: %w", err)
+	}
+
+	rebalanceHashes, err := c.summarizePayments(summary)
+	if err != nil {
+		return nil, fmt.Errorf("fetching payments: %w", err)
+	}
+
+	if err := c.summarizeInvoices(summary, rebalanceHashes); err != nil {
+		return nil, fmt.Errorf("fetching invoices: %w", err)
+	}
+
+	balance, err := c.client.WalletBalance(c.ctx, &lnrpc.WalletBalanceRequest{})
+	if err != nil {
+		return nil, fmt.Errorf("fetching wallet balance: %w", err)
+	}
+	summary.OnChainBalance = balance.ConfirmedBalance
+	summary.OnChainUnconfirmedBalance = balance.UnconfirmedBalance
+
+	return summary, nil
+}
+
+func (c *Client) summarizeForwards(summary *events.NodeSummary) error {
+	type pairKey struct{ in, out uint64 }
+	pairs := make(map[pairKey]*events.ChannelPairSummary)
+
+	forwards, err := c.ForwardingHistory(c.ctx, summary.Start, summary.End)
+	if err != nil {
+		return err
+	}
+
+	for _, fwd := range forwards {
+		summary.ForwardCount++
+		summary.ForwardVolumeMsat += fwd.AmtOutMsat
+		summary.ForwardFeesMsat += fwd.FeeMsat
+
+		key := pairKey{fwd.ChanIdIn, fwd.ChanIdOut}
+		pair, ok := pairs[key]
+		if !ok {
+			pair = &events.ChannelPairSummary{
+				InChanId:  fwd.ChanIdIn,
+				OutChanId: fwd.ChanIdOut,
+				InAlias:   fwd.PeerAliasIn,
+				OutAlias:  fwd.PeerAliasOut,
+			}
+			pairs[key] = pair
+		}
+		pair.Count++
+		pair.VolumeMsat += fwd.AmtOutMsat
+		pair.FeesMsat += fwd.FeeMsat
+	}
+
+	top := make([]events.ChannelPairSummary, 0, len(pairs))
+	for _, pair := range pairs {
+		top = append(top, *pair)
+	}
+	sort.Slice(top, func(i, j int) bool {
+		return top[i].FeesMsat > top[j].FeesMsat
+	})
+	if len(top) > summaryTopChannelPairs {
+		top = top[:summaryTopChannelPairs]
+	}
+	summary.TopChannelPairs = top
+
+	return nil
+}
+
+// summarizePayments adds the succeeded payments and rebalancings of the period to the summary.
+// It returns the payment hashes of the rebalancings, as their invoices must not be counted.
+func (c *Client) summarizePayments(summary *events.NodeSummary) (map[string]struct{}, error) {
+	info, err := c.client.GetInfo(c.ctx, &lnrpc.GetInfoRequest{})
+	if err != nil {
+		return nil, err
+	}
+
+	rebalanceHashes := make(map[string]struct{})
+
+	var offset uint64
+	for {
+		resp, err := c.client.ListPayments(c.ctx, &lnrpc.ListPaymentsRequest{
+			IndexOffset:       offset,
+			MaxPayments:       1000,
+			CreationDateStart: uint64(summary.Start.Unix()),
+			CreationDateEnd:   uint64(summary.End.Unix()),
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		for _, payment := range resp.Payments {
+			if payment.Status != lnrpc.Payment_SUCCEEDED {
+				continue
+			}
+
+			var recPubkey string
+			if len(payment.Htlcs) > 0 && len(payment.Htlcs[0].Route.Hops) > 0 {
+				hops := payment.Htlcs[0].Route.Hops
+				recPubkey = hops[len(hops)-1].PubKey
+			}
+
+			if recPubkey == info.IdentityPubkey {
+				summary.RebalanceCount++
+				summary.RebalanceCostMsat += payment.FeeMsat
+				rebalanceHashes[payment.PaymentHash] = struct{}{}
+				continue
+			}
+
+			summary.PaymentCount++
+			summary.PaymentAmountMsat += payment.ValueMsat
+			summary.PaymentFeesMsat += payment.FeeMsat
+		}
+
+		if len(resp.Payments) == 0 || resp.LastIndexOffset <= offset {
+			break
+		}
+		offset = resp.LastIndexOffset
+	}
+
+	return rebalanceHashes, nil
+}
+
+// summarizeInvoices adds the invoices settled during the period to the summary
+func (c *Client) summarizeInvoices(summary *events.NodeSummary, rebalanceHashes map[string]struct{}) error {
+	var offset uint64
+	for {
+		resp, err := c.client.ListInvoices(c.ctx, &lnrpc.ListInvoiceRequest{
+			IndexOffset:       offset,
+			NumMaxInvoices:    1000,
+			CreationDateStart: uint64(summary.Start.Add(-summaryInvoiceLookback).Unix()),
+			CreationDateEnd:   uint64(summary.End.Unix()),
+		})
+		if err != nil {
+			return err
+		}
+
+		for _, invoice := range resp.Invoices {
+			if invoice.State != lnrpc.Invoice_SETTLED {
+				continue
+			}
+			if invoice.SettleDate < summary.Start.Unix() || invoice.SettleDate >= summary.End.Unix() {
+				continue
+			}
+			if _, ok := rebalanceHashes[fmt.Sprintf("%x", invoice.RHash)]; ok {
+				continue
+			}
+
+			summary.InvoiceCount++
+			summary.InvoiceAmountMsat += invoice.AmtPaidMsat
+		}
+
+		if len(resp.Invoices) == 0 || resp.LastIndexOffset <= offset {
+			break
+		}
+		offset = resp.LastIndexOffset
+	}
+
+	return nil
+}
@@ -0,0 +1,53 @@
+package lnd
+
+import (
+	"testing"
+	"time"
+	_ "time/tzdata"
+
+	"github.com/Primexz/lndnotify/internal/config"
+)
+
+func TestNextSummaryTime(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	if err != nil {
+		t.Fatal(err)
+	}
+	at := func(month time.Month, day, hour, minute int) time.Time {
+		return time.Date(2026, month, day, hour, minute, 0, 0, berlin)
+	}
+
+	daily := config.NodeSummaryEventConfig{Schedule: "daily", Time: "08:00"}
+	weekly := config.NodeSummaryEventConfig{Schedule: "weekly", Time: "08:00", Weekday: "monday"}
+
+	tests := []struct {
+		name       string
+		now        time.Time
+		cfg        config.NodeSummaryEventConfig
+		wantStart  time.Time
+		wantNext   time.Time
+		wantLength time.Duration
+	}{
+		{"daily before time", at(time.June, 10, 7, 0), daily, at(time.June, 9, 8, 0), at(time.June, 10, 8, 0), 24 * time.Hour},
+		{"daily after time", at(time.June, 10, 9, 0), daily, at(time.June, 10, 8, 0), at(time.June, 11, 8, 0), 24 * time.Hour},
+		{"daily at time", at(time.June, 10, 8, 0), daily, at(time.June, 10, 8, 0), at(time.June, 11, 8, 0), 24 * time.Hour},
+		{"daily into summer time", at(time.March, 29, 7, 0), daily, at(time.March, 28, 8, 0), at(time.March, 29, 8, 0), 23 * time.Hour},
+		{"daily into winter time", at(time.October, 25, 7, 0), daily, at(time.October, 24, 8, 0), at(time.October, 25, 8, 0), 25 * time.Hour},
+		{"daily after summer time change", at(time.March, 29, 9, 0), daily, at(time.March, 29, 8, 0), at(time.March, 30, 8, 0), 24 * time.Hour},
+		{"weekly later in week", at(time.June, 10, 9, 0), weekly, at(time.June, 8, 8, 0), at(time.June, 15, 8, 0), 7 * 24 * time.Hour},
+		{"weekly on weekday before time", at(time.June, 15, 7, 0), weekly, at(time.June, 8, 8, 0), at(time.June, 15, 8, 0), 7 * 24 * time.Hour},
+		{"weekly into winter time", at(time.October, 21, 9, 0), weekly, at(time.October, 19, 8, 0), at(time.October, 26, 8, 0), 7*24*time.Hour + time.Hour},
+		{"invalid time", at(time.June, 10, 7, 0), config.NodeSummaryEventConfig{Time: "25:00"}, at(time.June, 9, 8, 0), at(time.June, 10, 8, 0), 24 * time.Hour},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			start, next := nextSummaryTime(tt.now, tt.cfg)
+			if !start.Equal(tt.wantStart) || !next.Equal(tt.wantNext) {
+				t.Errorf("nextSummaryTime() = %v, %v; want %v, %v", start, next, tt.wantStart, tt.wantNext)
+			}
+			if length := next.Sub(start); length != tt.wantLength {
+				t.Errorf("period length = %v; want %v", length, tt.wantLength)
+			}
+		})
+	}
+}
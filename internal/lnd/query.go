@@ -0,0 +1,91 @@
+package lnd
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/lightningnetwork/lnd/lnrpc"
+)
+
+var errNotConnected = errors.New("not connected to LND")
+
+// lightning returns the lightning client, or an error if the client is disconnected
+func (c *Client) lightning() (lnrpc.LightningClient, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.client == nil {
+		return nil, errNotConnected
+	}
+	return c.client, nil
+}
+
+// Channels returns the open channels cached by the channel manager
+func (c *Client) Channels() []*lnrpc.Channel {
+	c.mu.Lock()
+	cm := c.channelManager
+	c.mu.Unlock()
+
+	if cm == nil {
+		return nil
+	}
+	return cm.GetAllChannels()
+}
+
+// Info returns the node info
+func (c *Client) Info(ctx context.Context) (*lnrpc.GetInfoResponse, error) {
+	client, err := c.lightning()
+	if err != nil {
+		return nil, err
+	}
+	return client.GetInfo(ctx, &lnrpc.GetInfoRequest{})
+}
+
+// PendingChannels returns the channels that are being opened or closed
+func (c *Client) PendingChannels(ctx context.Context) (*lnrpc.PendingChannelsResponse, error) {
+	client, err := c.lightning()
+	if err != nil {
+		return nil, err
+	}
+	return client.PendingChannels(ctx, &lnrpc.PendingChannelsRequest{})
+}
+
+// WalletBalance returns the on-chain balance of the wallet
+func (c *Client) WalletBalance(ctx context.Context) (*lnrpc.WalletBalanceResponse, error) {
+	client, err := c.lightning()
+	if err != nil {
+		return nil, err
+	}
+	return client.WalletBalance(ctx, &lnrpc.WalletBalanceRequest{})
+}
+
+// ForwardingHistory returns all forwards between start and end
+func (c *Client) ForwardingHistory(ctx context.Context, start, end time.Time) ([]*lnrpc.ForwardingEvent, error) {
+	client, err := c.lightning()
+	if err != nil {
+		return nil, err
+	}
+
+	var forwards []*lnrpc.ForwardingEvent
+	var offset uint32
+	for {
+		resp, err := client.ForwardingHistory(ctx, &lnrpc.ForwardingHistoryRequest{
+			StartTime:       uint64(start.Unix()),
+			EndTime:         uint64(end.Unix()),
+			IndexOffset:     offset,
+			NumMaxEvents:    10000,
+			PeerAliasLookup: true,
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		forwards = append(forwards, resp.ForwardingEvents...)
+
+		if len(resp.ForwardingEvents) == 0 || resp.LastOffsetIndex <= offset {
+			return forwards, nil
+		}
+		offset = resp.LastOffsetIndex
+	}
+}
@@ -0,0 +1,38 @@
+package lnd
+
+// Keys of the handler checkpoints in the state store
+const (
+	stateKeyForwards     = "forwards"
+	stateKeyPendingHTLCs = "pending_htlcs"
+	stateKeyAliases      = "aliases"
+	stateKeyLndVersion   = "lnd_version"
+	stateKeyLiquidity    = "liquidity"
+
+	stateKeyInvoiceSettleIndex = "invoice_settle_index"
+	stateKeyKeysendSettleIndex = "keysend_settle_index"
+	stateKeyPaymentIndex       = "payment_index"
+	stateKeyPaymentsInFlight   = "payments_in_flight"
+)
+
+// forwardState is the checkpoint of the forwarding history poller
+type forwardState struct {
+	StartTime  int64  `json:"start_time"`
+	LastOffset uint32 `json:"last_offset"`
+}
+
+// loadState restores a handler checkpoint. It returns false if no checkpoint exists.
+func (c *Client) loadState(key string, v any) bool {
+	ok, err := c.store.Load(key, v)
+	if err != nil {
+		c.logger.WithField("key", key).WithError(err).Warn("error loading handler state, starting fresh")
+		return false
+	}
+	return ok
+}
+
+// saveState persists a handler checkpoint
+func (c *Client) saveState(key string, v any) {
+	if err := c.store.Save(key, v); err != nil {
+		c.logger.WithField("key", key).WithError(err).Error("error saving handler state")
+	}
+}
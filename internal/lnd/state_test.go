@@ -0,0 +1,159 @@
+package lnd
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	channelmanager "github.com/Primexz/lndnotify/internal/channel_manager"
+	"github.com/Primexz/lndnotify/internal/config"
+	"github.com/Primexz/lndnotify/internal/events"
+	"github.com/Primexz/lndnotify/internal/state"
+	"github.com/Primexz/lndnotify/pkg/lndversion"
+	"github.com/lightningnetwork/lnd/lnrpc"
+	"github.com/lightningnetwork/lnd/lnrpc/chainrpc"
+	log "github.com/sirupsen/logrus"
+	"google.golang.org/grpc"
+)
+
+type fakeChainKit struct {
+	chainrpc.ChainKitClient
+	height int32
+}
+
+func (f *fakeChainKit) GetBestBlock(ctx context.Context, in *chainrpc.GetBestBlockRequest, opts ...grpc.CallOption) (*chainrpc.GetBestBlockResponse, error) {
+	return &chainrpc.GetBestBlockResponse{BlockHeight: f.height}, nil
+}
+
+// handlerState holds the checkpoints of the handlers, restored like the handlers do on start
+type handlerState struct {
+	forwards            forwardState
+	notifiedHtlcs       map[uint64]map[uint64]bool
+	aliases             map[string]string
+	lastInformedVersion string
+}
+
+// startTestClient creates a client on the state store in dir and restores the handler state
+func startTestClient(t *testing.T, dir string, lightning *fakeLightning) (*Client, *handlerState) {
+	t.Helper()
+
+	store, err := state.NewStore(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cm := channelmanager.NewChannelManager(lightning)
+	if err := cm.RefreshNow(); err != nil {
+		t.Fatal(err)
+	}
+
+	c := &Client{
+		store:          store,
+		logger:         log.NewEntry(log.StandardLogger()),
+		client:         lightning,
+		chain:          &fakeChainKit{height: 800},
+		channelManager: cm,
+		eventSub:       make(chan events.Event, 100),
+		ctx:            context.Background(),
+	}
+	c.cfg.Store(&config.Config{})
+
+	st := &handlerState{
+		notifiedHtlcs: make(map[uint64]map[uint64]bool),
+		aliases:       make(map[string]string),
+	}
+	if !c.loadState(stateKeyForwards, &st.forwards) {
+		st.forwards.StartTime = time.Now().Unix()
+	}
+	c.loadState(stateKeyPendingHTLCs, &st.notifiedHtlcs)
+	c.loadState(stateKeyAliases, &st.aliases)
+	c.loadState(stateKeyLndVersion, &st.lastInformedVersion)
+	return c, st
+}
+
+// runHandlers runs one iteration of the checkpointed handlers
+func runHandlers(t *testing.T, c *Client, st *handlerState, local, latest *lndversion.LndVersion) {
+	t.Helper()
+
+	if err := c.pollForwards(&st.forwards); err != nil {
+		t.Fatalf("pollForwards() error = %v", err)
+	}
+	notified, err := c.checkPendingHTLCs(st.notifiedHtlcs)
+	if err != nil {
+		t.Fatalf("checkPendingHTLCs() error = %v", err)
+	}
+	st.notifiedHtlcs = notified
+	if err := c.checkAliasChanges(st.aliases); err != nil {
+		t.Fatalf("checkAliasChanges() error = %v", err)
+	}
+	c.informLndUpdate(&st.lastInformedVersion, local, latest)
+}
+
+func sentEventTypes(c *Client) map[events.EventType]int {
+	sent := make(map[events.EventType]int)
+	for {
+		select {
+		case event := <-c.eventSub:
+			sent[event.Type()]++
+		default:
+			return sent
+		}
+	}
+}
+
+func TestHandlerStateSurvivesRestart(t *testing.T) {
+	dir := t.TempDir()
+	lightning := &fakeLightning{
+		channels: []*lnrpc.Channel{{
+			ChanId:       1,
+			PendingHtlcs: []*lnrpc.HTLC{{HtlcIndex: 7, ExpirationHeight: 900}},
+		}},
+		forwards: []*lnrpc.ForwardingEvent{{AmtOut: 1000}, {AmtOut: 2000}},
+		peers:    map[string]string{"peer": "old alias"},
+	}
+	local, err := lndversion.ParseLndVersion("0.18.5-beta")
+	if err != nil {
+		t.Fatal(err)
+	}
+	latest, err := lndversion.ParseLndVersion("0.19.0-beta")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The first check only learns the alias, the second one notifies the change
+	c, st := startTestClient(t, dir, lightning)
+	runHandlers(t, c, st, local, latest)
+	lightning.peers["peer"] = "new alias"
+	if err := c.checkAliasChanges(st.aliases); err != nil {
+		t.Fatalf("checkAliasChanges() error = %v", err)
+	}
+
+	want := map[events.EventType]int{
+		events.Event_FORWARD:              2,
+		events.Event_HTLC_EXPIRATION:      1,
+		events.Event_ALIAS_CHANGED:        1,
+		events.Event_LND_UPDATE_AVAILABLE: 1,
+	}
+	assertSentEvents(t, "first start", sentEventTypes(c), want)
+
+	// After a restart on the same state only the new forward is sent
+	lightning.forwards = append(lightning.forwards, &lnrpc.ForwardingEvent{AmtOut: 3000})
+	c, st = startTestClient(t, dir, lightning)
+	runHandlers(t, c, st, local, latest)
+
+	assertSentEvents(t, "restart", sentEventTypes(c), map[events.EventType]int{events.Event_FORWARD: 1})
+}
+
+func assertSentEvents(t *testing.T, name string, got, want map[events.EventType]int) {
+	t.Helper()
+
+	if len(got) != len(want) {
+		t.Errorf("%s: sent events = %v; want %v", name, got, want)
+		return
+	}
+	for eventType, count := range want {
+		if got[eventType] != count {
+			t.Errorf("%s: sent events = %v; want %v", name, got, want)
+			return
+		}
+	}
+}
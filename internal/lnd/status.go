@@ -0,0 +1,117 @@
+package lnd
+
+import (
+	"errors"
+
+	"github.com/cenkalti/backoff/v5"
+)
+
+var errSubscriptionPending = errors.New("subscription not established yet")
+
+// NodeStatus is the health, chain sync and channel state of a node
+type NodeStatus struct {
+	Healthy          bool
+	SyncedToChain    bool
+	ActiveChannels   int
+	InactiveChannels int
+}
+
+// Status returns the current status of the node. Health and chain sync are updated by the
+// health and chain sync handlers, the channels are cached by the channel manager.
+func (c *Client) Status() NodeStatus {
+	status := NodeStatus{
+		Healthy:       c.healthy.Load(),
+		SyncedToChain: c.syncedToChain.Load(),
+	}
+	for _, channel := range c.Channels() {
+		if channel.GetActive() {
+			status.ActiveChannels++
+		} else {
+			status.InactiveChannels++
+		}
+	}
+	return status
+}
+
+// StatusChanges returns a channel that signals possible changes of the status, i.e. health or
+// chain sync changes and channel refreshes
+func (c *Client) StatusChanges() <-chan struct{} {
+	return c.statusCh
+}
+
+// setHealthy updates the health state and signals a change
+func (c *Client) setHealthy(healthy bool) {
+	if c.healthy.Swap(healthy) != healthy {
+		c.notifyStatus()
+	}
+}
+
+// setSyncedToChain updates the chain sync state and signals a change
+func (c *Client) setSyncedToChain(synced bool) {
+	if c.syncedToChain.Swap(synced) != synced {
+		c.notifyStatus()
+	}
+}
+
+// notifyStatus signals a status change without blocking if a signal is already pending
+func (c *Client) notifyStatus() {
+	select {
+	case c.statusCh <- struct{}{}:
+	default:
+	}
+}
+
+// handleStatus signals a status change after every channel refresh
+func (c *Client) handleStatus() {
+	c.logger.Debug("starting status handler")
+	defer c.wg.Done()
+
+	refreshCh := c.channelManager.SubscribeRefresh()
+	for {
+		select {
+		case <-c.ctx.Done():
+			return
+		case <-refreshCh:
+			c.notifyStatus()
+		}
+	}
+}
+
+// Subscriptions returns the state of every LND subscription of the node, nil for established
+// subscriptions and the last error otherwise
+func (c *Client) Subscriptions() map[string]error {
+	c.subMu.Lock()
+	defer c.subMu.Unlock()
+
+	subscriptions := make(map[string]error, len(c.subscriptions))
+	for name, err := range c.subscriptions {
+		subscriptions[name] = err
+	}
+	return subscriptions
+}
+
+// subscribe runs an LND subscription with retries. The subscription counts as down from a
+// failure until the operation calls subscriptionEstablished again.
+func (c *Client) subscribe(name string, operation backoff.Operation[string]) {
+	c.setSubscription(name, errSubscriptionPending)
+
+	// nolint:errcheck
+	retry(c, name, func() (string, error) {
+		ret, err := operation()
+		if err != nil {
+			c.setSubscription(name, err)
+		}
+		return ret, err
+	})
+}
+
+// subscriptionEstablished marks a subscription as alive
+func (c *Client) subscriptionEstablished(name string) {
+	c.setSubscription(name, nil)
+}
+
+func (c *Client) setSubscription(name string, err error) {
+	c.subMu.Lock()
+	defer c.subMu.Unlock()
+	c.subscriptions[name] = err
+}
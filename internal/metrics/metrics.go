@@ -0,0 +1,133 @@
+package metrics
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	log "github.com/sirupsen/logrus"
+)
+
+const namespace = "lndnotify"
+
+var (
+	// EventsReceived counts all events received from LND
+	EventsReceived = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "events_received_total",
+		Help:      "Number of events received from LND.",
+	}, []string{"event_type", "node"})
+
+	// EventsFiltered counts events skipped by the event filters
+	EventsFiltered = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "events_filtered_total",
+		Help:      "Number of events skipped by the event configuration.",
+	}, []string{"event_type", "node"})
+
+	// EventsSuppressed counts events suppressed as duplicates or by the rate limits
+	EventsSuppressed = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "events_suppressed_total",
+		Help:      "Number of events suppressed as duplicates or by the rate limits.",
+	}, []string{"event_type", "node"})
+
+	// EventsRendered counts events rendered into a notification
+	EventsRendered = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "events_rendered_total",
+		Help:      "Number of events rendered into a notification.",
+	}, []string{"event_type", "node"})
+
+	// EventsRenderFailed counts events that could not be rendered
+	EventsRenderFailed = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "events_render_failed_total",
+		Help:      "Number of events that could not be rendered.",
+	}, []string{"event_type", "node"})
+
+	// NotificationsSent counts notifications delivered to a provider
+	NotificationsSent = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "notifications_sent_total",
+		Help:      "Number of notifications delivered to a provider.",
+	}, []string{"provider", "node"})
+
+	// NotificationsFailed counts failed notification deliveries
+	NotificationsFailed = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "notifications_failed_total",
+		Help:      "Number of failed notification deliveries to a provider.",
+	}, []string{"provider", "node"})
+
+	// BatchQueueLength is the number of notifications waiting in the batch queue
+	BatchQueueLength = promauto.NewGauge(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "batch_queue_length",
+		Help:      "Number of notifications waiting in the batch queue.",
+	})
+
+	// OutboxPending is the number of notifications waiting in the outbox
+	OutboxPending = promauto.NewGauge(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "outbox_pending",
+		Help:      "Number of notifications waiting in the outbox for delivery.",
+	})
+
+	// SubscriptionReconnects counts retries of LND subscriptions
+	SubscriptionReconnects = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "subscription_reconnects_total",
+		Help:      "Number of reconnects of LND subscriptions.",
+	}, []string{"subscription", "node"})
+
+	// ChainSynced reports whether LND is synced to the chain
+	ChainSynced = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "chain_synced",
+		Help:      "Whether LND is synced to the chain (1) or not (0).",
+	}, []string{"node"})
+)
+
+// Server serves the Prometheus metrics over HTTP
+type Server struct {
+	srv *http.Server
+}
+
+// NewServer creates a metrics server listening on the given address
+func NewServer(listenAddress string) *Server {
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", promhttp.Handler())
+
+	return &Server{
+		srv: &http.Server{
+			Addr:              listenAddress,
+			Handler:           mux,
+			ReadHeaderTimeout: 10 * time.Second,
+		},
+	}
+}
+
+// Start starts serving metrics in the background
+func (s *Server) Start() {
+	go func() {
+		log.WithField("address", s.srv.Addr).Info("starting metrics server")
+		if err := s.srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			log.WithError(err).Error("metrics server failed")
+		}
+	}()
+}
+
+// Stop gracefully shuts down the metrics server
+func (s *Server) Stop() {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if err := s.srv.Shutdown(ctx); err != nil {
+		log.WithError(err).Error("error shutting down metrics server")
+	}
+}
@@ -5,23 +5,23 @@ import (
 	"strings"
 	"time"
 
-	"github.com/Primexz/lndnotify/pkg/uploader"
+	"github.com/Primexz/lndnotify/internal/events"
+	"github.com/Primexz/lndnotify/internal/metrics"
 	log "github.com/sirupsen/logrus"
 )
 
 // addToBatch adds a notification to the batch queue
-func (m *Manager) addToBatch(message string, file *uploader.File) {
+func (m *Manager) addToBatch(notification Notification) {
 	m.batchMu.Lock()
 	defer m.batchMu.Unlock()
 
 	log.WithFields(log.Fields{
-		"message":    message,
+		"message":    notification.Message,
 		"batch_size": len(m.batchQueue) + 1,
 	}).Debug("adding notification to batch")
-	m.batchQueue = append(m.batchQueue, QueuedNotification{
-		Message: message,
-		File:    file,
-	})
+	m.batchQueue = append(m.batchQueue, notification)
+
+	metrics.BatchQueueLength.Set(float64(len(m.batchQueue)))
 
 	// Start or reset the flush timer
 	if m.flushTimer != nil {
@@ -51,37 +51,59 @@ func (m *Manager) flushBatch() {
 
 	log.WithField("batch_size", len(m.batchQueue)).Debug("flushing notification batch")
 
-	var messages []string
-	var fileUploads []QueuedNotification
-
-	for _, notification := range m.batchQueue {
-		if notification.File != nil {
-			fileUploads = append(fileUploads, notification)
-		} else {
-			messages = append(messages, notification.Message)
+	// Send batched messages, grouped per provider as each provider
+	// may only accept a subset of the event types
+	for name, p := range m.getProviders() {
+		var notifications []Notification
+		for _, notification := range m.batchQueue {
+			if !p.accepts(notification.EventType, notification.Severity, notification.Env) {
+				continue
+			}
+			if m.hold(name, p, notification) {
+				continue
+			}
+			notifications = append(notifications, notification)
 		}
-	}
 
-	// Send batched regular messages
-	if len(messages) > 0 {
-		m.sendBatch(messages)
-	}
-
-	// Send file uploads individually (they can't be batched)
-	for _, upload := range fileUploads {
-		m.uploadFile(upload.Message, upload.File)
+		m.sendBatch(name, p, notifications)
 	}
 
 	m.batchQueue = m.batchQueue[:0]
+	metrics.BatchQueueLength.Set(0)
 }
 
-// sendBatch sends multiple notifications as a batch with improved formatting
-func (m *Manager) sendBatch(messages []string) {
-	if len(messages) == 0 {
-		return
+// sendBatch sends multiple notifications as a batch with improved formatting. The batch is
+// sent with the highest severity of its notifications. Webhooks receive every notification
+// individually and files are uploaded individually as they can't be batched.
+func (m *Manager) sendBatch(name string, p Provider, notifications []Notification) {
+	var (
+		messages []string
+		ids      []uint64
+		severity events.Severity
+		node     string
+	)
+	for _, notification := range notifications {
+		if p.webhook != nil || notification.File != nil {
+			m.dispatch(name, p, notification)
+			continue
+		}
+		if len(messages) == 0 {
+			severity = notification.Severity
+			node = notification.Node.Name
+		} else if node != notification.Node.Name {
+			node = ""
+		}
+		messages = append(messages, notification.Message)
+		ids = append(ids, notification.ID)
+		if !severity.AtLeast(notification.Severity) {
+			severity = notification.Severity
+		}
 	}
 
 	count := len(messages)
+	if count == 0 {
+		return
+	}
 
 	var batchMessage string
 	if count > 1 {
@@ -105,5 +127,72 @@ func (m *Manager) sendBatch(messages []string) {
 		}
 	}
 
-	m.send(batchMessage)
+	m.sendTo(name, p, severity, node, batchMessage, ids)
+}
+
+// hold adds a notification to the held notifications of the provider if it is in quiet hours.
+// It reports whether the notification was held.
+func (m *Manager) hold(name string, p Provider, notification Notification) bool {
+	if !p.quietHours.holds(notification, time.Now()) {
+		return false
+	}
+
+	m.heldMu.Lock()
+	defer m.heldMu.Unlock()
+
+	log.WithFields(log.Fields{
+		"provider":  name,
+		"message":   notification.Message,
+		"held_size": len(m.held[name]) + 1,
+	}).Debug("holding notification during quiet hours")
+	m.held[name] = append(m.held[name], notification)
+	m.report([]uint64{notification.ID}, name, DeliveryHeld, nil)
+	return true
+}
+
+// releaseLoop sends the held notifications of providers whose quiet hours have ended
+func (m *Manager) releaseLoop() {
+	defer m.wg.Done()
+
+	ticker := time.NewTicker(time.Minute)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-m.stopCh:
+			return
+		case <-ticker.C:
+			m.releaseHeld(false)
+		}
+	}
+}
+
+// releaseHeld sends the held notifications of each provider as one batch once its quiet hours
+// have ended, or regardless of the quiet hours if all is set.
+func (m *Manager) releaseHeld(all bool) {
+	now := time.Now()
+	providers := m.getProviders()
+
+	release := make(map[string][]Notification)
+	m.heldMu.Lock()
+	for name, notifications := range m.held {
+		p, ok := providers[name]
+		if !ok {
+			log.WithField("provider", name).WithField("count", len(notifications)).Warn("dropping held notifications of removed provider")
+			delete(m.held, name)
+			continue
+		}
+		if !all && p.quietHours.active(now) {
+			continue
+		}
+		release[name] = notifications
+		delete(m.held, name)
+	}
+	m.heldMu.Unlock()
+
+	for name, notifications := range release {
+		p := providers[name]
+		log.WithField("provider", name).WithField("count", len(notifications)).Info("sending notifications held during quiet hours")
+		m.sendBatch(name, p, notifications)
+	}
 }
@@ -0,0 +1,77 @@
+package notify
+
+import (
+	"time"
+
+	"github.com/Primexz/lndnotify/internal/events"
+)
+
+// EnvelopeVersion is the version of the JSON envelope of webhooks and MQTT messages. It is
+// increased on incompatible changes of the envelope or of the data of any event type.
+const EnvelopeVersion = 1
+
+// Envelope is the JSON representation of a notification posted to webhooks and published via
+// MQTT, e.g.
+//
+//	{
+//	  "version": 1,
+//	  "event_type": "forward_event",
+//	  "timestamp": "2026-04-26T12:00:00Z",
+//	  "node": {"name": "alice", "alias": "Alice's Node"},
+//	  "severity": "info",
+//	  "data": {"NodeName": "alice", "PeerAliasIn": "ACINQ", "Amount": "1,000", ...},
+//	  "message": "💰 Forwarded 1,000 sats ..."
+//	}
+//
+// Data is the template data of the event type, i.e. the template variables of TEMPLATES.md
+// keyed by their Go field names, with English number formatting. Within an envelope version,
+// fields of an event type are only added, never renamed or removed. The schema of every event
+// type is pinned in testdata/envelope_schema.golden.json.
+//
+// Status messages and summaries without template data have no data; status messages also
+// have no event type and severity. File attachments, e.g. channel backups, are included as
+// base64 encoded data in webhooks.
+type Envelope struct {
+	Version   int              `json:"version"`
+	EventType events.EventType `json:"event_type,omitempty"`
+	Timestamp time.Time        `json:"timestamp"`
+	Node      *EnvelopeNode    `json:"node,omitempty"`
+	Severity  events.Severity  `json:"severity,omitempty"`
+	Data      interface{}      `json:"data,omitempty"`
+	Message   string           `json:"message"`
+	File      *EnvelopeFile    `json:"file,omitempty"`
+}
+
+// EnvelopeNode is the LND node an event was received from
+type EnvelopeNode struct {
+	Name  string `json:"name"`
+	Alias string `json:"alias"`
+}
+
+// EnvelopeFile is a file attached to a notification
+type EnvelopeFile struct {
+	Name string `json:"name"`
+	Data []byte `json:"data"`
+}
+
+// newEnvelope creates the envelope of a notification, including its file if withFile is set
+func newEnvelope(n Notification, withFile bool) Envelope {
+	envelope := Envelope{
+		Version:   EnvelopeVersion,
+		EventType: n.EventType,
+		Timestamp: n.Timestamp,
+		Severity:  n.Severity,
+		Data:      n.Data,
+		Message:   n.Message,
+	}
+	if envelope.Timestamp.IsZero() {
+		envelope.Timestamp = time.Now()
+	}
+	if n.Node != (events.NodeInfo{}) {
+		envelope.Node = &EnvelopeNode{Name: n.Node.Name, Alias: n.Node.Alias}
+	}
+	if withFile && n.File != nil {
+		envelope.File = &EnvelopeFile{Name: n.File.Filename, Data: n.File.Data}
+	}
+	return envelope
+}
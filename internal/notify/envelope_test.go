@@ -0,0 +1,73 @@
+package notify
+
+import (
+	"encoding/json"
+	"flag"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/Primexz/lndnotify/internal/events"
+	"golang.org/x/text/language"
+)
+
+var update = flag.Bool("update", false, "update golden files")
+
+// TestEnvelopeSchema pins the JSON fields of the envelope data of every event type. Run with
+// -update after adding fields; renaming or removing fields requires a new envelope version.
+func TestEnvelopeSchema(t *testing.T) {
+	schema := make(map[string][]string)
+	for _, eventType := range events.EventTypes {
+		data, err := json.Marshal(events.SampleTemplateData(eventType, language.English))
+		if err != nil {
+			t.Fatalf("encoding data of %s: %v", eventType, err)
+		}
+		var value interface{}
+		if err := json.Unmarshal(data, &value); err != nil {
+			t.Fatalf("decoding data of %s: %v", eventType, err)
+		}
+
+		var fields []string
+		schemaFields(value, "", &fields)
+		sort.Strings(fields)
+		schema[eventType.String()] = fields
+	}
+
+	got, err := json.MarshalIndent(schema, "", "  ")
+	if err != nil {
+		t.Fatal(err)
+	}
+	got = append(got, '\n')
+
+	golden := filepath.Join("testdata", "envelope_schema.golden.json")
+	if *update {
+		if err := os.WriteFile(golden, got, 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	want, err := os.ReadFile(golden)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(got) != string(want) {
+		t.Errorf("envelope schema changed, run go test ./internal/notify -run TestEnvelopeSchema -update if only fields were added\ngot:\n%s", got)
+	}
+}
+
+// schemaFields collects the field paths of a decoded JSON value. Array elements are described
+// by their first element.
+func schemaFields(value interface{}, prefix string, fields *[]string) {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, field := range v {
+			*fields = append(*fields, prefix+key)
+			schemaFields(field, prefix+key+".", fields)
+		}
+	case []interface{}:
+		if len(v) > 0 {
+			schemaFields(v[0], strings.TrimSuffix(prefix, ".")+"[].", fields)
+		}
+	}
+}
@@ -1,12 +1,14 @@
 package notify
 
 import (
+	"errors"
 	"fmt"
-	"text/template"
 	"time"
 
+	"github.com/Primexz/lndnotify/internal/events"
+	"github.com/Primexz/lndnotify/internal/metrics"
 	"github.com/Primexz/lndnotify/pkg/uploader"
-	"github.com/nicholas-fedor/shoutrrr/pkg/types"
+	"github.com/cenkalti/backoff/v5"
 	log "github.com/sirupsen/logrus"
 )
 
@@ -14,108 +16,230 @@ import (
 func NewManager(cfg *ManagerConfig) *Manager {
 	m := &Manager{
 		cfg:       cfg,
-		providers: make(map[string]Provider),
-		templates: make(map[string]*template.Template),
 		lastReset: time.Now(),
+		held:      make(map[string][]Notification),
+		stopCh:    make(chan struct{}),
 	}
 
-	// Initialize providers and templates
-	m.initializeProviders()
-	m.parseTemplates()
+	// Initialize providers and templates, invalid ones are skipped
+	var err error
+	if m.providers, err = buildProviders(cfg.Providers); err != nil {
+		log.WithError(err).Error("error initializing providers")
+	}
+	if m.templates, err = parseTemplates(cfg.Templates, cfg.Locale); err != nil {
+		log.WithError(err).Error("error parsing templates")
+	}
+
+	m.wg.Add(1)
+	go m.releaseLoop()
+
+	if cfg.Outbox.Enabled {
+		o, err := newOutbox(cfg.Outbox, m.deliverEntry)
+		if err != nil {
+			log.WithError(err).Error("error initializing outbox, sending notifications without persistence")
+		} else {
+			m.outbox = o
+			m.outbox.start()
+		}
+	}
 
 	return m
 }
 
-// Send sends a notification to all configured providers
-func (m *Manager) send(message string) {
-	if message == "" {
-		return
+// Reload replaces the providers and templates. If any provider or template of the new
+// config is invalid, nothing is replaced and the error is returned. Batching and outbox
+// settings are only applied on restart.
+func (m *Manager) Reload(cfg *ManagerConfig) error {
+	providers, err := buildProviders(cfg.Providers)
+	if err != nil {
+		return err
+	}
+	templates, err := parseTemplates(cfg.Templates, cfg.Locale)
+	if err != nil {
+		return err
 	}
 
-	for name, p := range m.providers {
-		logger := log.WithField("provider", name).WithField("message", message)
+	m.mu.Lock()
+	m.providers = providers
+	m.templates = templates
+	m.mu.Unlock()
+	return nil
+}
 
-		logger.Info("sending notification")
-		errs := p.Sender.Send(message, &types.Params{})
-		for _, err := range errs {
-			if err == nil {
-				continue
-			}
-			logger.WithError(err).Error("error sending notification")
-		}
-	}
+// getProviders returns the current providers. The returned map must not be modified.
+func (m *Manager) getProviders() map[string]Provider {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.providers
 }
 
-func (m *Manager) uploadFile(message string, file *uploader.File) {
-	if file == nil {
-		m.send(message)
+// send sends a notification to all providers accepting the event
+func (m *Manager) send(notification Notification) {
+	if notification.Message == "" {
 		return
 	}
 
-	for name, p := range m.providers {
-		logger := log.WithFields(log.Fields{
-			"provider": name,
-			"filename": file.Filename,
-			"message":  message,
-			"size":     len(file.Data),
-		})
-
-		// fallback sends the message without attachment via shoutrrr
-		fallback := func(err error) {
-			msg := message
-			msg += "\n\n⚠️ Attachment removed"
-			if err != nil {
-				msg += fmt.Sprintf("\n🚨 Upload error: %v", err)
-			} else {
-				msg += " (file upload not supported for this provider)"
-			}
-			logger.Info("sending notification")
-
-			errs := p.Sender.Send(msg, &types.Params{})
-			for _, err := range errs {
-				if err == nil {
-					continue
-				}
-				logger.WithError(err).Error("error sending notification")
-			}
+	for name, p := range m.getProviders() {
+		if !p.accepts(notification.EventType, notification.Severity, notification.Env) {
+			continue
 		}
-
-		if p.Uploader == nil {
-			fallback(nil)
+		if m.hold(name, p, notification) {
 			continue
 		}
-		logger.Info("uploading file")
+		m.dispatch(name, p, notification)
+	}
+}
 
-		err := p.Uploader.Upload(message, file)
+// dispatch sends a single notification to a provider. Webhooks receive the JSON envelope
+// including attachments, files are uploaded to other providers if supported.
+func (m *Manager) dispatch(name string, p Provider, notification Notification) {
+	switch {
+	case p.webhook != nil:
+		body, err := p.webhook.body(notification)
 		if err != nil {
-			logger.WithError(err).Error("error uploading file, trying fallback")
-			fallback(err)
+			log.WithField("provider", name).WithError(err).Error("error creating webhook body")
+			return
 		}
+		m.sendTo(name, p, notification.Severity, notification.Node.Name, body, []uint64{notification.ID})
+	case notification.File != nil:
+		m.uploadTo(name, p, notification.Severity, notification.Node.Name, notification.Message, notification.File, []uint64{notification.ID})
+	default:
+		m.sendTo(name, p, notification.Severity, notification.Node.Name, notification.Message, []uint64{notification.ID})
 	}
 }
 
-// SendNotification sends a notification, either immediately or adds to batch
-func (m *Manager) SendNotification(message string, instant bool) {
-	if m.cfg.Batching.Enabled && !instant {
-		m.addToBatch(message, nil)
+// sendTo sends a message of the given notifications to a single provider. If the outbox is
+// enabled, the message is persisted and delivered with retries. The node labels the delivery
+// metrics, it is empty for messages of several nodes.
+func (m *Manager) sendTo(name string, p Provider, severity events.Severity, node string, message string, ids []uint64) {
+	logger := log.WithField("provider", name).WithField("message", message)
+
+	if m.outbox != nil {
+		err := m.outbox.enqueue(name, severity, node, message, ids)
+		if err == nil {
+			m.report(ids, name, DeliveryQueued, nil)
+			return
+		}
+		logger.WithError(err).Warn("cannot add notification to outbox, sending directly")
+	}
+
+	if err := m.deliver(name, p, severity, node, message, ids); err != nil {
+		logger.WithError(err).Error("error sending notification")
+	}
+}
+
+// deliver sends a notification to a single provider with the priority params of the severity.
+// For webhooks, the message is the JSON envelope. The result is reported for the given notifications.
+func (m *Manager) deliver(name string, p Provider, severity events.Severity, node string, message string, ids []uint64) error {
+	log.WithField("provider", name).WithField("message", message).Info("sending notification")
+
+	var err error
+	if p.webhook != nil {
+		err = p.webhook.post(message)
 	} else {
-		m.send(message)
+		err = errors.Join(p.Sender.Send(message, p.sendParams(severity))...)
 	}
+	if err != nil {
+		metrics.NotificationsFailed.WithLabelValues(name, node).Inc()
+		m.report(ids, name, DeliveryFailed, err)
+		return err
+	}
+
+	metrics.NotificationsSent.WithLabelValues(name, node).Inc()
+	m.report(ids, name, DeliverySent, nil)
+	return nil
 }
 
-// SendNotificationWithFile sends a notification with file, either immediately or adds to batch
-func (m *Manager) SendNotificationWithFile(message string, file *uploader.File) {
-	if m.cfg.Batching.Enabled {
-		m.addToBatch(message, file)
+// report passes the delivery result of notifications to the OnDelivery callback. Notifications
+// without an ID are not reported.
+func (m *Manager) report(ids []uint64, provider string, status DeliveryStatus, err error) {
+	if m.cfg.OnDelivery == nil {
+		return
+	}
+
+	reported := make([]uint64, 0, len(ids))
+	for _, id := range ids {
+		if id != 0 {
+			reported = append(reported, id)
+		}
+	}
+	if len(reported) > 0 {
+		m.cfg.OnDelivery(reported, provider, status, err)
+	}
+}
+
+// deliverEntry delivers a notification from the outbox
+func (m *Manager) deliverEntry(entry *OutboxEntry) error {
+	p, ok := m.getProviders()[entry.Provider]
+	if !ok {
+		return backoff.Permanent(fmt.Errorf("provider not configured: %s", entry.Provider))
+	}
+	return m.deliver(entry.Provider, p, events.Severity(entry.Severity), entry.Node, entry.Message, entry.Notifications)
+}
+
+// uploadTo uploads a file to a single provider. If the provider doesn't support uploads or
+// the upload fails, the message is sent without the attachment.
+func (m *Manager) uploadTo(name string, p Provider, severity events.Severity, node string, message string, file *uploader.File, ids []uint64) {
+	logger := log.WithFields(log.Fields{
+		"provider": name,
+		"filename": file.Filename,
+		"message":  message,
+		"size":     len(file.Data),
+	})
+
+	// fallback sends the message without attachment via shoutrrr
+	fallback := func(err error) {
+		msg := message
+		msg += "\n\n⚠️ Attachment removed"
+		if err != nil {
+			msg += fmt.Sprintf("\n🚨 Upload error: %v", err)
+		} else {
+			msg += " (file upload not supported for this provider)"
+		}
+		m.sendTo(name, p, severity, node, msg, ids)
+	}
+
+	if p.Uploader == nil {
+		fallback(nil)
+		return
+	}
+	logger.Info("uploading file")
+
+	err := p.Uploader.Upload(message, file)
+	if err != nil {
+		metrics.NotificationsFailed.WithLabelValues(name, node).Inc()
+		logger.WithError(err).Error("error uploading file, trying fallback")
+		fallback(err)
+		return
+	}
+	metrics.NotificationsSent.WithLabelValues(name, node).Inc()
+	m.report(ids, name, DeliverySent, nil)
+}
+
+// SendNotification sends a notification, either immediately or adds to batch. The event type,
+// severity and filter environment of the notification are used to route it to providers;
+// an empty event type reaches all providers. The severity sets the priority of the notification.
+func (m *Manager) SendNotification(notification Notification, instant bool) {
+	if m.cfg.Batching.Enabled && !instant {
+		m.addToBatch(notification)
 	} else {
-		m.uploadFile(message, file)
+		m.send(notification)
 	}
 }
 
-// Stop gracefully stops the notification manager and flushes any pending batches
+// Stop gracefully stops the notification manager and flushes any pending batches and
+// notifications held during quiet hours
 func (m *Manager) Stop() {
 	if m.cfg.Batching.Enabled {
 		log.Info("flushing pending notification batch before shutdown")
 		m.flushBatch()
 	}
+
+	close(m.stopCh)
+	m.wg.Wait()
+	m.releaseHeld(true)
+
+	if m.outbox != nil {
+		m.outbox.stop()
+	}
 }
@@ -0,0 +1,273 @@
+package notify
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Primexz/lndnotify/internal/config"
+	"github.com/eclipse/paho.golang/autopaho"
+	"github.com/eclipse/paho.golang/paho"
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	mqttStatusOnline  = "online"
+	mqttStatusOffline = "offline"
+
+	mqttPublishTimeout = 10 * time.Second
+	mqttQueueSize      = 100
+)
+
+// mqttMessage is a message waiting to be published
+type mqttMessage struct {
+	topic   string
+	payload []byte
+	retain  bool
+}
+
+// MQTTPublisher publishes events and the state of the nodes to an MQTT broker. Events are
+// published as JSON envelopes to <prefix>/<node>/<event_type>, the state of a node to the
+// retained topics <prefix>/<node>/state/<name> and the availability of lndnotify to the
+// retained topic <prefix>/status.
+type MQTTPublisher struct {
+	cfg    config.MQTTConfig
+	conn   *autopaho.ConnectionManager
+	cancel context.CancelFunc
+
+	// state holds the last payload of every retained state topic, republished on reconnect
+	state   map[string][]byte
+	stateMu sync.Mutex
+
+	queue   chan mqttMessage
+	stopped bool
+	queueMu sync.Mutex
+	wg      sync.WaitGroup
+}
+
+// NewMQTTPublisher creates a publisher that connects to the broker in the background and
+// reconnects if the connection is lost
+func NewMQTTPublisher(cfg config.MQTTConfig) (*MQTTPublisher, error) {
+	broker, err := url.Parse(cfg.Broker)
+	if err != nil {
+		return nil, fmt.Errorf("invalid broker URL: %w", err)
+	}
+	tlsCfg, err := mqttTLSConfig(cfg.TLS)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &MQTTPublisher{
+		cfg:   cfg,
+		state: make(map[string][]byte),
+		queue: make(chan mqttMessage, mqttQueueSize),
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	p.cancel = cancel
+	p.conn, err = autopaho.NewConnection(ctx, autopaho.ClientConfig{
+		ServerUrls:                    []*url.URL{broker},
+		TlsCfg:                        tlsCfg,
+		KeepAlive:                     30,
+		CleanStartOnInitialConnection: true,
+		ConnectUsername:               cfg.Username,
+		ConnectPassword:               []byte(cfg.Password),
+		WillMessage: &paho.WillMessage{
+			Topic:   p.topic("status"),
+			Payload: []byte(mqttStatusOffline),
+			QoS:     cfg.QoS,
+			Retain:  true,
+		},
+		OnConnectionUp: func(conn *autopaho.ConnectionManager, _ *paho.Connack) {
+			log.WithField("broker", broker.Host).Info("connected to mqtt broker")
+			go p.publishRetained(conn)
+		},
+		OnConnectError: func(err error) {
+			log.WithField("broker", broker.Host).WithError(err).Warn("error connecting to mqtt broker")
+		},
+		ClientConfig: paho.ClientConfig{
+			ClientID: cfg.ClientID,
+		},
+	})
+	if err != nil {
+		cancel()
+		return nil, fmt.Errorf("creating mqtt connection: %w", err)
+	}
+
+	p.wg.Add(1)
+	go p.publishLoop()
+	return p, nil
+}
+
+// PublishEvent publishes the envelope of a notification to the topic of its node and event
+// type. Notifications without an event type, e.g. status messages, and attachments are not
+// published.
+func (p *MQTTPublisher) PublishEvent(notification Notification) {
+	if notification.EventType == "" {
+		return
+	}
+
+	payload, err := json.Marshal(newEnvelope(notification, false))
+	if err != nil {
+		log.WithError(err).Error("error encoding mqtt event")
+		return
+	}
+
+	p.enqueue(mqttMessage{
+		topic:   p.topic(notification.Node.Name, notification.EventType.String()),
+		payload: payload,
+	})
+}
+
+// PublishState publishes a state value of a node as JSON to its retained state topic. Unchanged
+// values are not published again.
+func (p *MQTTPublisher) PublishState(node, name string, value interface{}) {
+	payload, err := json.Marshal(value)
+	if err != nil {
+		log.WithError(err).Error("error encoding mqtt state")
+		return
+	}
+
+	topic := p.topic(node, "state", name)
+	p.stateMu.Lock()
+	if last, ok := p.state[topic]; ok && string(last) == string(payload) {
+		p.stateMu.Unlock()
+		return
+	}
+	p.state[topic] = payload
+	p.stateMu.Unlock()
+
+	p.enqueue(mqttMessage{topic: topic, payload: payload, retain: true})
+}
+
+// Stop publishes the pending messages and the offline status and disconnects from the broker
+func (p *MQTTPublisher) Stop() {
+	p.queueMu.Lock()
+	p.stopped = true
+	close(p.queue)
+	p.queueMu.Unlock()
+	p.wg.Wait()
+
+	if err := p.publish(p.conn, mqttMessage{topic: p.topic("status"), payload: []byte(mqttStatusOffline), retain: true}); err != nil {
+		log.WithError(err).Warn("error publishing mqtt offline status")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), mqttPublishTimeout)
+	defer cancel()
+	if err := p.conn.Disconnect(ctx); err != nil {
+		log.WithError(err).Warn("error disconnecting from mqtt broker")
+	}
+	p.cancel()
+}
+
+// enqueue adds a message to the publish queue without blocking. Messages are dropped if the
+// queue is full or the publisher is stopped.
+func (p *MQTTPublisher) enqueue(msg mqttMessage) {
+	p.queueMu.Lock()
+	defer p.queueMu.Unlock()
+
+	if p.stopped {
+		return
+	}
+	select {
+	case p.queue <- msg:
+	default:
+		log.WithField("topic", msg.topic).Warn("mqtt queue is full, dropping message")
+	}
+}
+
+// publishLoop publishes the queued messages in order
+func (p *MQTTPublisher) publishLoop() {
+	defer p.wg.Done()
+
+	for msg := range p.queue {
+		if err := p.publish(p.conn, msg); err != nil {
+			log.WithField("topic", msg.topic).WithError(err).Warn("error publishing mqtt message")
+		}
+	}
+}
+
+// publishRetained publishes the online status and the last state of every node after a
+// (re)connect, as the broker may have lost the retained messages
+func (p *MQTTPublisher) publishRetained(conn *autopaho.ConnectionManager) {
+	messages := []mqttMessage{{topic: p.topic("status"), payload: []byte(mqttStatusOnline), retain: true}}
+
+	p.stateMu.Lock()
+	for topic, payload := range p.state {
+		messages = append(messages, mqttMessage{topic: topic, payload: payload, retain: true})
+	}
+	p.stateMu.Unlock()
+
+	for _, msg := range messages {
+		if err := p.publish(conn, msg); err != nil {
+			log.WithField("topic", msg.topic).WithError(err).Warn("error publishing retained mqtt message")
+		}
+	}
+}
+
+// publish sends a single message, failing immediately if the broker is not connected
+func (p *MQTTPublisher) publish(conn *autopaho.ConnectionManager, msg mqttMessage) error {
+	ctx, cancel := context.WithTimeout(context.Background(), mqttPublishTimeout)
+	defer cancel()
+
+	log.WithField("topic", msg.topic).Debug("publishing mqtt message")
+	_, err := conn.Publish(ctx, &paho.Publish{
+		Topic:   msg.topic,
+		QoS:     p.cfg.QoS,
+		Retain:  msg.retain,
+		Payload: msg.payload,
+	})
+	return err
+}
+
+// topic joins the topic prefix with the given levels. MQTT wildcards and separators in the
+// levels are replaced, an empty level is named "default", e.g. the node of a config without
+// a nodes list.
+func (p *MQTTPublisher) topic(levels ...string) string {
+	replacer := strings.NewReplacer("/", "_", "+", "_", "#", "_")
+	parts := []string{p.cfg.TopicPrefix}
+	for _, level := range levels {
+		if level == "" {
+			level = "default"
+		}
+		parts = append(parts, replacer.Replace(level))
+	}
+	return strings.Join(parts, "/")
+}
+
+// mqttTLSConfig returns the TLS config of the MQTT connection, nil to use the system defaults
+func mqttTLSConfig(cfg config.MQTTTLSConfig) (*tls.Config, error) {
+	if cfg == (config.MQTTTLSConfig{}) {
+		return nil, nil
+	}
+
+	tlsCfg := &tls.Config{
+		MinVersion:         tls.VersionTLS12,
+		InsecureSkipVerify: cfg.InsecureSkipVerify, // nolint:gosec
+	}
+	if cfg.CAFile != "" {
+		ca, err := os.ReadFile(cfg.CAFile)
+		if err != nil {
+			return nil, fmt.Errorf("reading mqtt CA file: %w", err)
+		}
+		tlsCfg.RootCAs = x509.NewCertPool()
+		if !tlsCfg.RootCAs.AppendCertsFromPEM(ca) {
+			return nil, fmt.Errorf("no certificates found in mqtt CA file %s", cfg.CAFile)
+		}
+	}
+	if cfg.CertFile != "" {
+		cert, err := tls.LoadX509KeyPair(cfg.CertFile, cfg.KeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("loading mqtt client certificate: %w", err)
+		}
+		tlsCfg.Certificates = []tls.Certificate{cert}
+	}
+	return tlsCfg, nil
+}
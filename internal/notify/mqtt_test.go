@@ -0,0 +1,161 @@
+package notify
+
+import (
+	"encoding/json"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/Primexz/lndnotify/internal/config"
+	"github.com/Primexz/lndnotify/internal/events"
+	"github.com/eclipse/paho.golang/packets"
+)
+
+// fakeBroker is a minimal MQTT v5 server that accepts a single client and records its
+// CONNECT packet and publications
+type fakeBroker struct {
+	listener  net.Listener
+	connects  chan *packets.Connect
+	publishes chan *packets.Publish
+}
+
+func newFakeBroker(t *testing.T) *fakeBroker {
+	t.Helper()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	b := &fakeBroker{
+		listener:  listener,
+		connects:  make(chan *packets.Connect, 1),
+		publishes: make(chan *packets.Publish, 10),
+	}
+	t.Cleanup(func() { listener.Close() })
+	go b.serve()
+	return b
+}
+
+func (b *fakeBroker) serve() {
+	for {
+		conn, err := b.listener.Accept()
+		if err != nil {
+			return
+		}
+		go b.handle(conn)
+	}
+}
+
+func (b *fakeBroker) handle(conn net.Conn) {
+	defer conn.Close()
+
+	for {
+		cp, err := packets.ReadPacket(conn)
+		if err != nil {
+			return
+		}
+
+		var resp *packets.ControlPacket
+		switch p := cp.Content.(type) {
+		case *packets.Connect:
+			b.connects <- p
+			resp = packets.NewControlPacket(packets.CONNACK)
+		case *packets.Publish:
+			b.publishes <- p
+			if p.QoS == 1 {
+				resp = packets.NewControlPacket(packets.PUBACK)
+				resp.Content.(*packets.Puback).PacketID = p.PacketID
+			}
+		case *packets.Pingreq:
+			resp = packets.NewControlPacket(packets.PINGRESP)
+		case *packets.Disconnect:
+			return
+		}
+		if resp != nil {
+			if _, err := resp.WriteTo(conn); err != nil {
+				return
+			}
+		}
+	}
+}
+
+// next returns the next publication received by the broker
+func (b *fakeBroker) next(t *testing.T) *packets.Publish {
+	t.Helper()
+
+	select {
+	case p := <-b.publishes:
+		return p
+	case <-time.After(5 * time.Second):
+		t.Fatal("timeout waiting for publication")
+		return nil
+	}
+}
+
+func TestMQTTPublisher(t *testing.T) {
+	broker := newFakeBroker(t)
+
+	publisher, err := NewMQTTPublisher(config.MQTTConfig{
+		Broker:      "mqtt://" + broker.listener.Addr().String(),
+		ClientID:    "lndnotify-test",
+		Username:    "user",
+		Password:    "pass",
+		TopicPrefix: "lndnotify",
+		QoS:         1,
+	})
+	if err != nil {
+		t.Fatalf("NewMQTTPublisher() error = %v", err)
+	}
+
+	connect := <-broker.connects
+	if connect.ClientID != "lndnotify-test" || connect.Username != "user" || string(connect.Password) != "pass" {
+		t.Errorf("CONNECT = %q, %q, %q; want lndnotify-test, user, pass", connect.ClientID, connect.Username, connect.Password)
+	}
+	if connect.WillTopic != "lndnotify/status" || string(connect.WillMessage) != "offline" || !connect.WillRetain {
+		t.Errorf("will = %q, %q, retain %v; want retained offline status", connect.WillTopic, connect.WillMessage, connect.WillRetain)
+	}
+
+	if p := broker.next(t); p.Topic != "lndnotify/status" || string(p.Payload) != "online" || !p.Retain {
+		t.Errorf("got %s %q retain %v; want retained online status", p.Topic, p.Payload, p.Retain)
+	}
+
+	publisher.PublishEvent(Notification{Message: "🟢 lndnotify connected"})
+	publisher.PublishEvent(Notification{
+		EventType: events.Event_FORWARD,
+		Severity:  events.SeverityInfo,
+		Node:      events.NodeInfo{Name: "alice", Alias: "Alice's Node"},
+		Data:      map[string]string{"Amount": "1,000"},
+		Message:   "💰 Forwarded 1,000 sats",
+	})
+	p := broker.next(t)
+	if p.Topic != "lndnotify/alice/forward_event" || p.Retain {
+		t.Errorf("got %s retain %v; want lndnotify/alice/forward_event", p.Topic, p.Retain)
+	}
+	var envelope Envelope
+	if err := json.Unmarshal(p.Payload, &envelope); err != nil {
+		t.Fatalf("decoding event: %v", err)
+	}
+	if envelope.EventType != events.Event_FORWARD || envelope.Node.Alias != "Alice's Node" || envelope.Message != "💰 Forwarded 1,000 sats" {
+		t.Errorf("envelope = %+v", envelope)
+	}
+
+	publisher.PublishState("", "healthy", true)
+	publisher.PublishState("", "healthy", true)
+	publisher.PublishState("", "active_channels", 12)
+	for _, want := range []struct{ topic, payload string }{
+		{"lndnotify/default/state/healthy", "true"},
+		{"lndnotify/default/state/active_channels", "12"},
+	} {
+		if p := broker.next(t); p.Topic != want.topic || string(p.Payload) != want.payload || !p.Retain {
+			t.Errorf("got %s %q retain %v; want retained %s %q", p.Topic, p.Payload, p.Retain, want.topic, want.payload)
+		}
+	}
+
+	publisher.Stop()
+	if p := broker.next(t); p.Topic != "lndnotify/status" || string(p.Payload) != "offline" || !p.Retain {
+		t.Errorf("got %s %q retain %v; want retained offline status", p.Topic, p.Payload, p.Retain)
+	}
+
+	// Publishing after stop is a no-op
+	publisher.PublishState("", "healthy", false)
+}
@@ -0,0 +1,340 @@
+package notify
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Primexz/lndnotify/internal/config"
+	"github.com/Primexz/lndnotify/internal/events"
+	"github.com/Primexz/lndnotify/internal/metrics"
+	"github.com/cenkalti/backoff/v5"
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	outboxPendingDir    = "pending"
+	outboxDeadLetterDir = "dead_letter"
+)
+
+// OutboxEntry is a notification that is persisted until it was delivered to its provider
+type OutboxEntry struct {
+	ID        string    `json:"id"`
+	Provider  string    `json:"provider"`
+	Message   string    `json:"message"`
+	Severity  string    `json:"severity,omitempty"`
+	Node      string    `json:"node,omitempty"`
+	Attempts  int       `json:"attempts"`
+	CreatedAt time.Time `json:"created_at"`
+	LastError string    `json:"last_error,omitempty"`
+
+	// Notifications are the IDs of the notifications in the message. They are not persisted,
+	// so deliveries after a restart are not reported.
+	Notifications []uint64 `json:"-"`
+}
+
+// deliverFunc delivers an outbox entry. Returning a backoff.PermanentError moves the
+// entry to the dead letter store without further retries.
+type deliverFunc func(entry *OutboxEntry) error
+
+// providerRetryState tracks the backoff of a single provider
+type providerRetryState struct {
+	backoff     *backoff.ExponentialBackOff
+	nextAttempt time.Time
+}
+
+// outbox is a disk-backed notification queue. Every entry is stored as a JSON file and
+// removed once delivered. Entries failing more than MaxAttempts times are moved to the
+// dead letter directory.
+type outbox struct {
+	cfg     config.OutboxConfig
+	deliver deliverFunc
+
+	pending map[string]*OutboxEntry
+	lastID  int64
+	stopped bool
+	mu      sync.Mutex
+
+	// retry is only accessed by the delivery loop
+	retry map[string]*providerRetryState
+
+	wakeCh chan struct{}
+	stopCh chan struct{}
+	wg     sync.WaitGroup
+}
+
+// newOutbox creates the outbox directories and loads all pending entries from disk
+func newOutbox(cfg config.OutboxConfig, deliver deliverFunc) (*outbox, error) {
+	o := &outbox{
+		cfg:     cfg,
+		deliver: deliver,
+		pending: make(map[string]*OutboxEntry),
+		retry:   make(map[string]*providerRetryState),
+		wakeCh:  make(chan struct{}, 1),
+		stopCh:  make(chan struct{}),
+	}
+
+	for _, dir := range []string{outboxPendingDir, outboxDeadLetterDir} {
+		if err := os.MkdirAll(filepath.Join(cfg.DataDir, dir), 0o700); err != nil {
+			return nil, fmt.Errorf("creating outbox directory: %w", err)
+		}
+	}
+
+	entries, err := readOutboxEntries(filepath.Join(cfg.DataDir, outboxPendingDir))
+	if err != nil {
+		return nil, fmt.Errorf("loading pending outbox entries: %w", err)
+	}
+	for _, entry := range entries {
+		o.pending[entry.ID] = entry
+	}
+	metrics.OutboxPending.Set(float64(len(o.pending)))
+
+	if len(entries) > 0 {
+		log.WithField("count", len(entries)).Info("loaded pending notifications from outbox")
+	}
+
+	return o, nil
+}
+
+// start starts the delivery loop
+func (o *outbox) start() {
+	o.wg.Add(1)
+	go o.run()
+}
+
+// stop stops the delivery loop after a final delivery attempt. Undelivered entries stay on
+// disk and are retried after the next start.
+func (o *outbox) stop() {
+	o.mu.Lock()
+	o.stopped = true
+	o.mu.Unlock()
+
+	close(o.stopCh)
+	o.wg.Wait()
+	o.deliverDue()
+}
+
+// enqueue persists a message of the given notifications for a provider and triggers the delivery loop
+func (o *outbox) enqueue(provider string, severity events.Severity, node string, message string, ids []uint64) error {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	if o.stopped {
+		return errors.New("outbox is stopped")
+	}
+
+	// IDs are zero-padded timestamps, so sorting them by name keeps the delivery order
+	id := time.Now().UnixNano()
+	if id <= o.lastID {
+		id = o.lastID + 1
+	}
+	o.lastID = id
+
+	entry := &OutboxEntry{
+		ID:        fmt.Sprintf("%020d", id),
+		Provider:  provider,
+		Message:   message,
+		Severity:  severity.String(),
+		Node:      node,
+		CreatedAt: time.Now(),
+
+		Notifications: ids,
+	}
+	if err := o.write(outboxPendingDir, entry); err != nil {
+		return err
+	}
+	o.pending[entry.ID] = entry
+	metrics.OutboxPending.Set(float64(len(o.pending)))
+
+	select {
+	case o.wakeCh <- struct{}{}:
+	default:
+	}
+	return nil
+}
+
+func (o *outbox) run() {
+	defer o.wg.Done()
+
+	ticker := time.NewTicker(time.Second)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-o.stopCh:
+			return
+		case <-o.wakeCh:
+			o.deliverDue()
+		case <-ticker.C:
+			o.deliverDue()
+		}
+	}
+}
+
+// deliverDue delivers the pending entries of every provider that is not backing off.
+// Entries of a provider are delivered in order, a failure postpones the remaining ones.
+// It must only be called from the delivery loop, or after the loop has stopped.
+func (o *outbox) deliverDue() {
+	o.mu.Lock()
+	entries := o.sortedPending()
+	o.mu.Unlock()
+
+	now := time.Now()
+	blocked := make(map[string]bool)
+
+	for _, entry := range entries {
+		if blocked[entry.Provider] {
+			continue
+		}
+
+		state := o.retryState(entry.Provider)
+		if now.Before(state.nextAttempt) {
+			blocked[entry.Provider] = true
+			continue
+		}
+
+		logger := log.WithFields(log.Fields{
+			"provider": entry.Provider,
+			"id":       entry.ID,
+			"attempt":  entry.Attempts + 1,
+		})
+
+		err := o.deliver(entry)
+		if err == nil {
+			state.backoff.Reset()
+			o.finish(entry)
+			continue
+		}
+
+		entry.Attempts++
+		entry.LastError = err.Error()
+
+		var permanent *backoff.PermanentError
+		if errors.As(err, &permanent) || entry.Attempts >= o.cfg.MaxAttempts {
+			logger.WithError(err).Error("notification delivery failed permanently, moving to dead letter store")
+			writeErr := o.write(outboxDeadLetterDir, entry)
+			if writeErr == nil {
+				o.finish(entry)
+				continue
+			}
+			// The entry stays pending and is retried with the backoff of the provider, so
+			// the dead letter store is not written on every tick
+			err = fmt.Errorf("writing dead letter entry: %w", writeErr)
+		}
+
+		delay := state.backoff.NextBackOff()
+		state.nextAttempt = now.Add(delay)
+		blocked[entry.Provider] = true
+
+		logger.WithError(err).WithField("next_retry_in", delay).Warn("notification delivery failed, retrying")
+		if err := o.write(outboxPendingDir, entry); err != nil {
+			logger.WithError(err).Error("error updating outbox entry")
+		}
+	}
+}
+
+func (o *outbox) retryState(provider string) *providerRetryState {
+	state, ok := o.retry[provider]
+	if !ok {
+		b := backoff.NewExponentialBackOff()
+		b.InitialInterval = o.cfg.InitialInterval
+		b.MaxInterval = o.cfg.MaxInterval
+		b.Multiplier = 2
+		b.Reset()
+
+		state = &providerRetryState{backoff: b}
+		o.retry[provider] = state
+	}
+	return state
+}
+
+func (o *outbox) sortedPending() []*OutboxEntry {
+	entries := make([]*OutboxEntry, 0, len(o.pending))
+	for _, entry := range o.pending {
+		entries = append(entries, entry)
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].ID < entries[j].ID
+	})
+	return entries
+}
+
+// write atomically stores an entry in the given outbox directory
+func (o *outbox) write(dir string, entry *OutboxEntry) error {
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return fmt.Errorf("encoding outbox entry: %w", err)
+	}
+
+	path := filepath.Join(o.cfg.DataDir, dir, entry.ID+".json")
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o600); err != nil {
+		return fmt.Errorf("writing outbox entry: %w", err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		return fmt.Errorf("writing outbox entry: %w", err)
+	}
+	return nil
+}
+
+// finish removes a delivered or dead-lettered entry from the pending entries
+func (o *outbox) finish(entry *OutboxEntry) {
+	o.mu.Lock()
+	delete(o.pending, entry.ID)
+	metrics.OutboxPending.Set(float64(len(o.pending)))
+	o.mu.Unlock()
+
+	path := filepath.Join(o.cfg.DataDir, outboxPendingDir, entry.ID+".json")
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		log.WithField("id", entry.ID).WithError(err).Error("error removing outbox entry")
+	}
+}
+
+// ListDeadLetters returns all notifications that could not be delivered, oldest first
+func ListDeadLetters(cfg config.OutboxConfig) ([]*OutboxEntry, error) {
+	entries, err := readOutboxEntries(filepath.Join(cfg.DataDir, outboxDeadLetterDir))
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].ID < entries[j].ID
+	})
+	return entries, nil
+}
+
+func readOutboxEntries(dir string) ([]*OutboxEntry, error) {
+	files, err := os.ReadDir(dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	var entries []*OutboxEntry
+	for _, file := range files {
+		if file.IsDir() || !strings.HasSuffix(file.Name(), ".json") {
+			continue
+		}
+
+		// #nosec G304 -- path is built from the configured outbox directory
+		data, err := os.ReadFile(filepath.Join(dir, file.Name()))
+		if err != nil {
+			return nil, err
+		}
+
+		var entry OutboxEntry
+		if err := json.Unmarshal(data, &entry); err != nil {
+			log.WithField("file", file.Name()).WithError(err).Warn("skipping invalid outbox entry")
+			continue
+		}
+		entries = append(entries, &entry)
+	}
+	return entries, nil
+}
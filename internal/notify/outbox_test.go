@@ -0,0 +1,189 @@
+package notify
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/Primexz/lndnotify/internal/config"
+	"github.com/Primexz/lndnotify/internal/events"
+	"github.com/cenkalti/backoff/v5"
+)
+
+// recordingDeliverer records the messages delivered per provider and fails with the
+// configured error per message
+type recordingDeliverer struct {
+	delivered map[string][]string
+	failures  map[string]error
+}
+
+func (d *recordingDeliverer) deliver(entry *OutboxEntry) error {
+	if err := d.failures[entry.Message]; err != nil {
+		return err
+	}
+	if d.delivered == nil {
+		d.delivered = make(map[string][]string)
+	}
+	d.delivered[entry.Provider] = append(d.delivered[entry.Provider], entry.Message)
+	return nil
+}
+
+func newTestOutbox(t *testing.T, dir string, d *recordingDeliverer) *outbox {
+	t.Helper()
+
+	o, err := newOutbox(config.OutboxConfig{
+		DataDir:         dir,
+		MaxAttempts:     3,
+		InitialInterval: time.Hour,
+		MaxInterval:     time.Hour,
+	}, d.deliver)
+	if err != nil {
+		t.Fatalf("newOutbox() error = %v", err)
+	}
+	return o
+}
+
+func enqueueAll(t *testing.T, o *outbox, provider string, messages ...string) {
+	t.Helper()
+	for _, msg := range messages {
+		if err := o.enqueue(provider, events.SeverityInfo, "", msg, nil); err != nil {
+			t.Fatalf("enqueue() error = %v", err)
+		}
+	}
+}
+
+func TestOutboxRetryOrder(t *testing.T) {
+	d := &recordingDeliverer{failures: map[string]error{"a1": errors.New("unavailable")}}
+	o := newTestOutbox(t, t.TempDir(), d)
+	enqueueAll(t, o, "a", "a1", "a2")
+	enqueueAll(t, o, "b", "b1")
+
+	// A failure of a1 postpones a2, other providers are not affected
+	o.deliverDue()
+	if len(d.delivered["a"]) != 0 || !slices.Equal(d.delivered["b"], []string{"b1"}) {
+		t.Fatalf("delivered = %v; want only b1", d.delivered)
+	}
+
+	// The provider is backing off
+	o.deliverDue()
+	if len(d.delivered["a"]) != 0 {
+		t.Fatalf("delivered = %v; want nothing during backoff", d.delivered["a"])
+	}
+
+	pending, err := readOutboxEntries(filepath.Join(o.cfg.DataDir, outboxPendingDir))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(pending) != 2 || pending[0].Message != "a1" || pending[0].Attempts != 1 || pending[0].LastError != "unavailable" {
+		t.Fatalf("pending entries = %+v; want a1 with one attempt and a2", pending)
+	}
+
+	// Once the backoff expired, the entries are delivered in order
+	delete(d.failures, "a1")
+	o.retry["a"].nextAttempt = time.Time{}
+	o.deliverDue()
+	if !slices.Equal(d.delivered["a"], []string{"a1", "a2"}) {
+		t.Errorf("delivered = %v; want [a1 a2]", d.delivered["a"])
+	}
+	if len(o.pending) != 0 {
+		t.Errorf("pending = %d entries; want none", len(o.pending))
+	}
+}
+
+func TestOutboxDeadLetter(t *testing.T) {
+	tests := map[string]struct {
+		err      error
+		attempts int
+	}{
+		"permanent error": {backoff.Permanent(errors.New("invalid payload")), 1},
+		"max attempts":    {errors.New("unavailable"), 3},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			d := &recordingDeliverer{failures: map[string]error{"a1": tt.err}}
+			o := newTestOutbox(t, t.TempDir(), d)
+			enqueueAll(t, o, "a", "a1", "a2")
+
+			for i := 0; i < tt.attempts; i++ {
+				// Skip the backoff of the previous attempt
+				delete(o.retry, "a")
+				o.deliverDue()
+			}
+
+			dead, err := ListDeadLetters(o.cfg)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(dead) != 1 || dead[0].Message != "a1" || dead[0].Attempts != tt.attempts {
+				t.Fatalf("dead letters = %+v; want a1 after %d attempts", dead, tt.attempts)
+			}
+			if !slices.Equal(d.delivered["a"], []string{"a2"}) {
+				t.Errorf("delivered = %v; want [a2]", d.delivered["a"])
+			}
+			if _, err := os.Stat(filepath.Join(o.cfg.DataDir, outboxPendingDir, dead[0].ID+".json")); !os.IsNotExist(err) {
+				t.Errorf("pending entry of dead letter still exists: %v", err)
+			}
+		})
+	}
+}
+
+func TestOutboxReload(t *testing.T) {
+	dir := t.TempDir()
+
+	d := &recordingDeliverer{}
+	o := newTestOutbox(t, dir, d)
+	enqueueAll(t, o, "a", "a1", "a2", "a3")
+	o.stopped = true
+
+	// Pending entries are loaded after a restart and delivered in order
+	restarted := newTestOutbox(t, dir, d)
+	if len(restarted.pending) != 3 {
+		t.Fatalf("pending = %d entries after restart; want 3", len(restarted.pending))
+	}
+	restarted.deliverDue()
+	if !slices.Equal(d.delivered["a"], []string{"a1", "a2", "a3"}) {
+		t.Errorf("delivered = %v; want [a1 a2 a3]", d.delivered["a"])
+	}
+
+	pending, err := readOutboxEntries(filepath.Join(dir, outboxPendingDir))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(pending) != 0 {
+		t.Errorf("pending entries on disk = %d; want none", len(pending))
+	}
+}
+
+func TestOutboxDeadLetterWriteFailure(t *testing.T) {
+	d := &recordingDeliverer{failures: map[string]error{"a1": backoff.Permanent(errors.New("invalid payload"))}}
+	o := newTestOutbox(t, t.TempDir(), d)
+	enqueueAll(t, o, "a", "a1", "a2")
+
+	// Replace the dead letter directory with a file, so writing to it fails
+	deadLetterDir := filepath.Join(o.cfg.DataDir, outboxDeadLetterDir)
+	if err := os.Remove(deadLetterDir); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(deadLetterDir, nil, 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	o.deliverDue()
+	if len(o.pending) != 2 {
+		t.Fatalf("pending = %d entries; want 2", len(o.pending))
+	}
+	state := o.retry["a"]
+	if state == nil || !state.nextAttempt.After(time.Now()) {
+		t.Fatal("provider is not backing off after the dead letter write failed")
+	}
+
+	// The provider is blocked instead of retrying on every tick
+	d.failures["a1"] = nil
+	o.deliverDue()
+	if len(d.delivered["a"]) != 0 {
+		t.Errorf("delivered = %v; want nothing during backoff", d.delivered["a"])
+	}
+}
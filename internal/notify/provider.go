@@ -1,32 +1,199 @@
 package notify
 
 import (
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/Primexz/lndnotify/internal/config"
+	"github.com/Primexz/lndnotify/internal/events"
+	"github.com/Primexz/lndnotify/internal/filter"
 	"github.com/Primexz/lndnotify/pkg/uploader"
 	"github.com/nicholas-fedor/shoutrrr"
+	"github.com/nicholas-fedor/shoutrrr/pkg/types"
 	log "github.com/sirupsen/logrus"
 )
 
-// initializeProviders sets up all notification providers
-func (m *Manager) initializeProviders() {
-	for _, p := range m.cfg.Providers {
+// buildProviders sets up all notification providers. Providers that cannot be created
+// are skipped and reported in the returned error.
+func buildProviders(cfgs []config.ProviderConfig) (map[string]Provider, error) {
+	providers := make(map[string]Provider)
+	var errs []error
+
+	for _, p := range cfgs {
+		filters, err := filter.NewRules(p.Filters, nil)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("provider %q: %w", p.Name, err))
+			continue
+		}
+
+		var minSeverity events.Severity
+		if p.MinSeverity != "" {
+			if minSeverity, err = events.ParseSeverity(p.MinSeverity); err != nil {
+				errs = append(errs, fmt.Errorf("provider %q: %w", p.Name, err))
+				continue
+			}
+		}
+
+		quiet, err := newQuietHours(p.QuietHours)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("provider %q: %w", p.Name, err))
+			continue
+		}
+
+		include, err := parseEventTypes(p.Events)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("provider %q: events: %w", p.Name, err))
+			continue
+		}
+		exclude, err := parseEventTypes(p.ExcludeEvents)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("provider %q: exclude_events: %w", p.Name, err))
+			continue
+		}
+
+		provider := Provider{
+			events:        include,
+			excludeEvents: exclude,
+			filters:       filters,
+			minSeverity:   minSeverity,
+			quietHours:    quiet,
+
+			severityPriority: p.SeverityPriority,
+		}
+
+		if p.Type == config.ProviderTypeWebhook {
+			provider.webhook = newWebhook(p.URL, p.Webhook)
+			providers[p.Name] = provider
+			continue
+		}
+
 		sender, err := shoutrrr.CreateSender(p.URL)
 		if err != nil {
-			log.WithField("provider", p.Name).WithError(err).Error("error creating sender")
+			errs = append(errs, fmt.Errorf("creating sender for provider %q: %w", p.Name, err))
 			continue
 		}
+		provider.Sender = sender
 
 		name, url, err := sender.ExtractServiceName(p.URL)
 		if err != nil {
 			log.WithField("provider", p.Name).WithError(err).Error("cannot initialize uploader, invalid URL")
-			m.providers[p.Name] = Provider{Sender: sender, Uploader: nil}
+			providers[p.Name] = provider
 			continue
 		}
+		provider.service = name
+		provider.urlParams = url.Query()
 		upl, err := uploader.NewUploader(name, url)
 		if err != nil {
 			log.WithField("provider", p.Name).WithError(err).Warn("error creating uploader")
-			m.providers[p.Name] = Provider{Sender: sender, Uploader: nil}
+			providers[p.Name] = provider
 			continue
 		}
-		m.providers[p.Name] = Provider{Sender: sender, Uploader: upl}
+		provider.Uploader = upl
+		providers[p.Name] = provider
 	}
+
+	return providers, errors.Join(errs...)
+}
+
+// accepts reports whether a notification of the given event type, severity and filter
+// environment should be routed to the provider. Notifications without an event type
+// (e.g. status messages) are always accepted.
+func (p Provider) accepts(eventType events.EventType, severity events.Severity, env filter.Env) bool {
+	if eventType == "" {
+		return true
+	}
+	if !severity.AtLeast(p.minSeverity) {
+		return false
+	}
+	if _, excluded := p.excludeEvents[eventType]; excluded {
+		return false
+	}
+	if p.events != nil {
+		if _, included := p.events[eventType]; !included {
+			return false
+		}
+	}
+	// Notifications without template data, e.g. summaries, are not filtered
+	if env == nil {
+		return true
+	}
+	return p.filters.Match(eventType, env)
+}
+
+// sendParams returns the params of a notification with the given severity
+func (p Provider) sendParams(severity events.Severity) *types.Params {
+	if !p.severityPriority {
+		return &types.Params{}
+	}
+	return severityParams(p.service, severity, p.urlParams)
+}
+
+// severityParams maps the severity of a notification to the priority params of ntfy and
+// Pushover and to silent notifications on Telegram. Params set in the provider URL, other
+// services and notifications without a severity use the settings of the provider URL.
+func severityParams(service string, severity events.Severity, urlParams url.Values) *types.Params {
+	params := types.Params{}
+	if severity == "" {
+		return &params
+	}
+
+	var key, value string
+	switch service {
+	case "ntfy":
+		key = "priority"
+		value = map[events.Severity]string{
+			events.SeverityInfo:     "low",
+			events.SeverityWarning:  "high",
+			events.SeverityCritical: "max",
+		}[severity]
+	case "pushover":
+		key = "priority"
+		value = map[events.Severity]string{
+			events.SeverityInfo:     "-1",
+			events.SeverityWarning:  "0",
+			events.SeverityCritical: "1",
+		}[severity]
+	case "telegram":
+		key = "notification"
+		value = "yes"
+		if severity == events.SeverityInfo {
+			value = "no"
+		}
+	default:
+		return &params
+	}
+
+	for name := range urlParams {
+		if strings.EqualFold(name, key) {
+			return &params
+		}
+	}
+	params[key] = value
+	return &params
+}
+
+// parseEventTypes converts the configured event type names into a lookup set. It returns
+// nil if no names are configured and an error for unknown names.
+func parseEventTypes(names []string) (map[events.EventType]struct{}, error) {
+	if len(names) == 0 {
+		return nil, nil
+	}
+
+	set := make(map[events.EventType]struct{}, len(names))
+	for _, name := range names {
+		et, err := events.ParseEventType(name)
+		if err != nil {
+			return nil, err
+		}
+		set[et] = struct{}{}
+	}
+	return set, nil
+}
+
+// CheckProviders reports all providers that cannot be created, e.g. because of an invalid URL or filter
+func CheckProviders(cfgs []config.ProviderConfig) error {
+	_, err := buildProviders(cfgs)
+	return err
 }
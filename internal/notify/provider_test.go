@@ -0,0 +1,111 @@
+package notify
+
+import (
+	"net/url"
+	"testing"
+
+	"github.com/Primexz/lndnotify/internal/config"
+	"github.com/Primexz/lndnotify/internal/events"
+	"github.com/nicholas-fedor/shoutrrr/pkg/types"
+)
+
+func TestProviderAcceptsSeverity(t *testing.T) {
+	p := Provider{minSeverity: events.SeverityWarning}
+
+	tests := []struct {
+		eventType events.EventType
+		severity  events.Severity
+		want      bool
+	}{
+		{events.Event_FORWARD, events.SeverityInfo, false},
+		{events.Event_CHANNEL_STATUS_DOWN, events.SeverityWarning, true},
+		{events.Event_CHANNEL_CLOSE, events.SeverityCritical, true},
+		{"", "", true},
+	}
+
+	for _, tt := range tests {
+		if got := p.accepts(tt.eventType, tt.severity, nil); got != tt.want {
+			t.Errorf("accepts(%q, %q) = %v; want %v", tt.eventType, tt.severity, got, tt.want)
+		}
+	}
+}
+
+func TestSeverityParams(t *testing.T) {
+	tests := []struct {
+		service   string
+		severity  events.Severity
+		urlParams url.Values
+		want      types.Params
+	}{
+		{"ntfy", events.SeverityInfo, nil, types.Params{"priority": "low"}},
+		{"ntfy", events.SeverityCritical, nil, types.Params{"priority": "max"}},
+		{"pushover", events.SeverityWarning, nil, types.Params{"priority": "0"}},
+		{"pushover", events.SeverityCritical, nil, types.Params{"priority": "1"}},
+		{"telegram", events.SeverityInfo, nil, types.Params{"notification": "no"}},
+		{"telegram", events.SeverityWarning, nil, types.Params{"notification": "yes"}},
+		{"discord", events.SeverityCritical, nil, types.Params{}},
+		{"ntfy", "", nil, types.Params{}},
+		{"ntfy", events.SeverityInfo, url.Values{"Priority": {"high"}}, types.Params{}},
+		{"telegram", events.SeverityInfo, url.Values{"notification": {"yes"}}, types.Params{}},
+		{"pushover", events.SeverityInfo, url.Values{"devices": {"phone"}}, types.Params{"priority": "-1"}},
+	}
+
+	for _, tt := range tests {
+		got := *severityParams(tt.service, tt.severity, tt.urlParams)
+		if len(got) != len(tt.want) {
+			t.Errorf("severityParams(%q, %q, %v) = %v; want %v", tt.service, tt.severity, tt.urlParams, got, tt.want)
+			continue
+		}
+		for key, value := range tt.want {
+			if got[key] != value {
+				t.Errorf("severityParams(%q, %q, %v) = %v; want %v", tt.service, tt.severity, tt.urlParams, got, tt.want)
+			}
+		}
+	}
+}
+
+func TestProviderSendParams(t *testing.T) {
+	providers, err := buildProviders([]config.ProviderConfig{
+		{Name: "default", URL: "ntfy://ntfy.sh/topic"},
+		{Name: "enabled", URL: "ntfy://ntfy.sh/topic", SeverityPriority: true},
+		{Name: "url", URL: "ntfy://ntfy.sh/topic?priority=urgent", SeverityPriority: true},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := map[string]types.Params{
+		"default": {},
+		"enabled": {"priority": "low"},
+		"url":     {},
+	}
+	for name, want := range tests {
+		got := *providers[name].sendParams(events.SeverityInfo)
+		if len(got) != len(want) || got["priority"] != want["priority"] {
+			t.Errorf("provider %s: sendParams() = %v; want %v", name, got, want)
+		}
+	}
+}
+
+func TestCheckProvidersUnknownEventType(t *testing.T) {
+	tests := map[string]config.ProviderConfig{
+		"events":         {Name: "test", URL: "generic://localhost", Events: []string{"forwrd_event"}},
+		"exclude_events": {Name: "test", URL: "generic://localhost", ExcludeEvents: []string{"forwrd_event"}},
+		"bypass_events": {Name: "test", URL: "generic://localhost", QuietHours: config.QuietHoursConfig{
+			Schedule:     []config.QuietHoursPeriod{{Start: "22:00", End: "07:00"}},
+			BypassEvents: []string{"forwrd_event"},
+		}},
+	}
+	for name, cfg := range tests {
+		t.Run(name, func(t *testing.T) {
+			if err := CheckProviders([]config.ProviderConfig{cfg}); err == nil {
+				t.Error("CheckProviders() error = nil; want error for unknown event type")
+			}
+		})
+	}
+
+	valid := config.ProviderConfig{Name: "test", URL: "generic://localhost", Events: []string{"forward_event"}}
+	if err := CheckProviders([]config.ProviderConfig{valid}); err != nil {
+		t.Errorf("CheckProviders() error = %v", err)
+	}
+}
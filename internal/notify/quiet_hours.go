@@ -0,0 +1,115 @@
+package notify
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/Primexz/lndnotify/internal/config"
+	"github.com/Primexz/lndnotify/internal/events"
+)
+
+// quietPeriod is a daily period of quiet hours, in minutes since midnight
+type quietPeriod struct {
+	// days are the weekdays the period starts on, nil for every day
+	days  map[time.Weekday]struct{}
+	start int
+	end   int
+}
+
+// quietHours is the do-not-disturb schedule of a provider
+type quietHours struct {
+	loc     *time.Location
+	periods []quietPeriod
+	bypass  map[events.EventType]struct{}
+}
+
+// newQuietHours parses the quiet hours of a provider. It returns nil if no schedule is configured.
+func newQuietHours(cfg config.QuietHoursConfig) (*quietHours, error) {
+	if len(cfg.Schedule) == 0 {
+		return nil, nil
+	}
+
+	loc, err := time.LoadLocation(cfg.Timezone)
+	if err != nil {
+		return nil, fmt.Errorf("invalid quiet hours timezone %q: %w", cfg.Timezone, err)
+	}
+
+	bypass, err := parseEventTypes(cfg.BypassEvents)
+	if err != nil {
+		return nil, fmt.Errorf("invalid quiet hours bypass_events: %w", err)
+	}
+
+	q := &quietHours{
+		loc:    loc,
+		bypass: bypass,
+	}
+	for _, p := range cfg.Schedule {
+		period := quietPeriod{}
+		for i, value := range []string{p.Start, p.End} {
+			t, err := time.Parse("15:04", value)
+			if err != nil {
+				return nil, fmt.Errorf("invalid quiet hours time %q: %w", value, err)
+			}
+			minutes := t.Hour()*60 + t.Minute()
+			if i == 0 {
+				period.start = minutes
+			} else {
+				period.end = minutes
+			}
+		}
+		if len(p.Days) > 0 {
+			period.days = make(map[time.Weekday]struct{}, len(p.Days))
+			for _, day := range p.Days {
+				weekday, err := config.ParseWeekday(day)
+				if err != nil {
+					return nil, err
+				}
+				period.days[weekday] = struct{}{}
+			}
+		}
+		q.periods = append(q.periods, period)
+	}
+	return q, nil
+}
+
+// active reports whether t is within the quiet hours
+func (q *quietHours) active(t time.Time) bool {
+	if q == nil {
+		return false
+	}
+
+	t = t.In(q.loc)
+	for _, p := range q.periods {
+		// A period that ends on the next day may have started yesterday
+		for _, offset := range []int{0, -1} {
+			day := t.AddDate(0, 0, offset)
+			if p.days != nil {
+				if _, ok := p.days[day.Weekday()]; !ok {
+					continue
+				}
+			}
+
+			begin := time.Date(day.Year(), day.Month(), day.Day(), 0, p.start, 0, 0, q.loc)
+			end := time.Date(day.Year(), day.Month(), day.Day(), 0, p.end, 0, 0, q.loc)
+			if p.end <= p.start {
+				end = end.AddDate(0, 0, 1)
+			}
+			if !t.Before(begin) && t.Before(end) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// holds reports whether a notification must be held back at the given time. Status messages,
+// critical events and the bypass event types are never held.
+func (q *quietHours) holds(notification Notification, t time.Time) bool {
+	if q == nil || notification.EventType == "" || notification.Severity == events.SeverityCritical {
+		return false
+	}
+	if _, ok := q.bypass[notification.EventType]; ok {
+		return false
+	}
+	return q.active(t)
+}
@@ -0,0 +1,82 @@
+package notify
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Primexz/lndnotify/internal/config"
+	"github.com/Primexz/lndnotify/internal/events"
+)
+
+func TestQuietHoursActive(t *testing.T) {
+	q, err := newQuietHours(config.QuietHoursConfig{
+		Timezone: "Europe/Berlin",
+		Schedule: []config.QuietHoursPeriod{
+			{Days: []string{"mon", "tue", "wed", "thu", "fri"}, Start: "22:00", End: "07:00"},
+			{Days: []string{"saturday", "sunday"}, Start: "13:00", End: "15:00"},
+		},
+	})
+	if err != nil {
+		t.Fatalf("newQuietHours() error = %v", err)
+	}
+
+	berlin, _ := time.LoadLocation("Europe/Berlin")
+	tests := []struct {
+		name string
+		time time.Time
+		want bool
+	}{
+		{"friday evening", time.Date(2026, 4, 24, 23, 30, 0, 0, berlin), true},
+		{"saturday morning after friday night", time.Date(2026, 4, 25, 6, 59, 0, 0, berlin), true},
+		{"saturday end of period", time.Date(2026, 4, 25, 7, 0, 0, 0, berlin), false},
+		{"saturday afternoon", time.Date(2026, 4, 25, 14, 0, 0, 0, berlin), true},
+		{"saturday night", time.Date(2026, 4, 25, 23, 0, 0, 0, berlin), false},
+		{"monday morning after sunday", time.Date(2026, 4, 27, 3, 0, 0, 0, berlin), false},
+		{"monday noon", time.Date(2026, 4, 27, 12, 0, 0, 0, berlin), false},
+		{"monday evening in UTC", time.Date(2026, 4, 27, 20, 30, 0, 0, time.UTC), true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := q.active(tt.time); got != tt.want {
+				t.Errorf("active(%v) = %v; want %v", tt.time, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestQuietHoursHolds(t *testing.T) {
+	q, err := newQuietHours(config.QuietHoursConfig{
+		Schedule:     []config.QuietHoursPeriod{{Start: "00:00", End: "23:59"}},
+		BypassEvents: []string{"channel_status_down_event"},
+	})
+	if err != nil {
+		t.Fatalf("newQuietHours() error = %v", err)
+	}
+
+	now := time.Date(2026, 4, 26, 12, 0, 0, 0, time.Local)
+	tests := []struct {
+		name         string
+		notification Notification
+		want         bool
+	}{
+		{"info event", Notification{EventType: events.Event_FORWARD, Severity: events.SeverityInfo}, true},
+		{"warning event", Notification{EventType: events.Event_LIQUIDITY_IMBALANCE, Severity: events.SeverityWarning}, true},
+		{"critical event", Notification{EventType: events.Event_CHANNEL_CLOSE, Severity: events.SeverityCritical}, false},
+		{"bypass event", Notification{EventType: events.Event_CHANNEL_STATUS_DOWN, Severity: events.SeverityWarning}, false},
+		{"status message", Notification{}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := q.holds(tt.notification, now); got != tt.want {
+				t.Errorf("holds() = %v; want %v", got, tt.want)
+			}
+		})
+	}
+
+	var none *quietHours
+	if none.holds(tests[0].notification, now) {
+		t.Error("holds() = true without quiet hours")
+	}
+}
@@ -2,61 +2,127 @@ package notify
 
 import (
 	"bytes"
+	"errors"
 	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
 	"text/template"
+	"time"
 
+	"github.com/Primexz/lndnotify/internal/config"
 	"github.com/Primexz/lndnotify/internal/events"
+	"github.com/Primexz/lndnotify/pkg/format"
 	log "github.com/sirupsen/logrus"
+	"golang.org/x/text/language"
+	"golang.org/x/text/message"
 )
 
-// parseTemplates parses all notification templates
-func (m *Manager) parseTemplates() {
-	templates := map[events.EventType]string{
-		events.Event_BACKUP_MULTI:          m.cfg.Templates.BackupMulti,
-		events.Event_FORWARD:               m.cfg.Templates.Forward,
-		events.Event_PEER_OFFLINE:          m.cfg.Templates.PeerOffline,
-		events.Event_PEER_ONLINE:           m.cfg.Templates.PeerOnline,
-		events.Event_CHAIN_SYNC_LOST:       m.cfg.Templates.ChainSyncLost,
-		events.Event_CHAIN_SYNC_RESTORED:   m.cfg.Templates.ChainSyncRestored,
-		events.Event_CHANNEL_OPEN:          m.cfg.Templates.ChannelOpen,
-		events.Event_CHANNEL_OPENING:       m.cfg.Templates.ChannelOpening,
-		events.Event_CHANNEL_CLOSE:         m.cfg.Templates.ChannelClose,
-		events.Event_CHANNEL_CLOSING:       m.cfg.Templates.ChannelClosing,
-		events.Event_CHANNEL_FEE_CHANGE:    m.cfg.Templates.ChannelFeeChange,
-		events.Event_INVOICE_SETTLED:       m.cfg.Templates.InvoiceSettled,
-		events.Event_FAILED_HTLC:           m.cfg.Templates.FailedHtlc,
-		events.Event_HEALTHY:               m.cfg.Templates.Healthy,
-		events.Event_UNHEALTHY:             m.cfg.Templates.Unhealthy,
-		events.Event_KEYSEND:               m.cfg.Templates.Keysend,
-		events.Event_ONCHAIN_CONFIRMED:     m.cfg.Templates.OnChainConfirmed,
-		events.Event_ONCHAIN_MEMPOOL:       m.cfg.Templates.OnChainMempool,
-		events.Event_PAYMENT_SUCCEEDED:     m.cfg.Templates.PaymentSucceeded,
-		events.Event_REBALANCING_SUCCEEDED: m.cfg.Templates.RebalancingSucceeded,
-		events.Event_CHANNEL_STATUS_DOWN:   m.cfg.Templates.ChannelStatusDown,
-		events.Event_CHANNEL_STATUS_UP:     m.cfg.Templates.ChannelStatusUp,
-		events.Event_TLS_CERT_EXPIRY:       m.cfg.Templates.TLSCertExpiry,
-		events.Event_WALLET_STATE:          m.cfg.Templates.WalletState,
-		events.Event_LND_UPDATE_AVAILABLE:  m.cfg.Templates.LndUpdateAvailable,
-		events.Event_HTLC_EXPIRATION:       m.cfg.Templates.HTLCExpiration,
-		events.Event_ALIAS_CHANGED:         m.cfg.Templates.AliasChanged,
-	}
-
-	for name, text := range templates {
+// parseTemplates parses all notification templates with the functions of templateFuncs.
+// Templates that cannot be parsed are skipped and reported in the returned error.
+func parseTemplates(cfg config.NotificationTemplate, lang language.Tag) (map[string]*template.Template, error) {
+	funcs := templateFuncs(lang)
+	templates := make(map[string]*template.Template)
+	var errs []error
+	for name, text := range templateTexts(cfg) {
 		if text == "" {
 			continue
 		}
-		tmpl, err := template.New(name.String()).Parse(text)
+		tmpl, err := template.New(name.String()).Funcs(funcs).Parse(text)
 		if err != nil {
-			log.WithField("template", name).WithError(err).Error("error parsing template")
+			errs = append(errs, fmt.Errorf("parsing template %s: %w", name, err))
 			continue
 		}
-		m.templates[name.String()] = tmpl
+		templates[name.String()] = tmpl
 	}
+
+	return templates, errors.Join(errs...)
+}
+
+// templateTexts returns the configured template of each event type
+func templateTexts(cfg config.NotificationTemplate) map[events.EventType]string {
+	return map[events.EventType]string{
+		events.Event_BACKUP_MULTI:          cfg.BackupMulti,
+		events.Event_FORWARD:               cfg.Forward,
+		events.Event_FORWARD_DIGEST:        cfg.ForwardDigest,
+		events.Event_PEER_OFFLINE:          cfg.PeerOffline,
+		events.Event_PEER_ONLINE:           cfg.PeerOnline,
+		events.Event_CHAIN_SYNC_LOST:       cfg.ChainSyncLost,
+		events.Event_CHAIN_SYNC_RESTORED:   cfg.ChainSyncRestored,
+		events.Event_CHANNEL_OPEN:          cfg.ChannelOpen,
+		events.Event_CHANNEL_OPENING:       cfg.ChannelOpening,
+		events.Event_CHANNEL_CLOSE:         cfg.ChannelClose,
+		events.Event_CHANNEL_CLOSING:       cfg.ChannelClosing,
+		events.Event_CHANNEL_FEE_CHANGE:    cfg.ChannelFeeChange,
+		events.Event_INVOICE_SETTLED:       cfg.InvoiceSettled,
+		events.Event_FAILED_HTLC:           cfg.FailedHtlc,
+		events.Event_FAILED_HTLC_DIGEST:    cfg.FailedHtlcDigest,
+		events.Event_HEALTHY:               cfg.Healthy,
+		events.Event_UNHEALTHY:             cfg.Unhealthy,
+		events.Event_KEYSEND:               cfg.Keysend,
+		events.Event_ONCHAIN_CONFIRMED:     cfg.OnChainConfirmed,
+		events.Event_ONCHAIN_MEMPOOL:       cfg.OnChainMempool,
+		events.Event_PAYMENT_SUCCEEDED:     cfg.PaymentSucceeded,
+		events.Event_REBALANCING_SUCCEEDED: cfg.RebalancingSucceeded,
+		events.Event_CHANNEL_STATUS_DOWN:   cfg.ChannelStatusDown,
+		events.Event_CHANNEL_STATUS_UP:     cfg.ChannelStatusUp,
+		events.Event_TLS_CERT_EXPIRY:       cfg.TLSCertExpiry,
+		events.Event_WALLET_STATE:          cfg.WalletState,
+		events.Event_LND_UPDATE_AVAILABLE:  cfg.LndUpdateAvailable,
+		events.Event_HTLC_EXPIRATION:       cfg.HTLCExpiration,
+		events.Event_ALIAS_CHANGED:         cfg.AliasChanged,
+		events.Event_LIQUIDITY_IMBALANCE:   cfg.LiquidityImbalance,
+		events.Event_LIQUIDITY_RECOVERED:   cfg.LiquidityRecovered,
+		events.Event_NODE_SUMMARY:          cfg.NodeSummary,
+	}
+}
+
+// TemplateCheck is the result of rendering the template of an event type with sample data
+type TemplateCheck struct {
+	EventType events.EventType
+	Output    string
+	Err       error
+}
+
+// CheckTemplates parses the template of every event type and renders it with the sample
+// data of the event type. Numbers are formatted for lang.
+func CheckTemplates(cfg config.NotificationTemplate, lang language.Tag) []TemplateCheck {
+	texts := templateTexts(cfg)
+	funcs := templateFuncs(lang)
+
+	checks := make([]TemplateCheck, 0, len(events.EventTypes))
+	for _, eventType := range events.EventTypes {
+		check := TemplateCheck{EventType: eventType}
+
+		text := texts[eventType]
+		if text == "" {
+			check.Err = fmt.Errorf("no template configured")
+			checks = append(checks, check)
+			continue
+		}
+
+		tmpl, err := template.New(eventType.String()).Funcs(funcs).Parse(text)
+		if err != nil {
+			check.Err = err
+			checks = append(checks, check)
+			continue
+		}
+
+		var buf bytes.Buffer
+		if err := tmpl.Execute(&buf, events.SampleTemplateData(eventType, lang)); err != nil {
+			check.Err = err
+		}
+		check.Output = buf.String()
+		checks = append(checks, check)
+	}
+	return checks
 }
 
 // RenderTemplate renders a notification template with the provided data
 func (m *Manager) RenderTemplate(name string, data interface{}) (string, error) {
+	m.mu.RLock()
 	tmpl, ok := m.templates[name]
+	m.mu.RUnlock()
 	if !ok {
 		return "", fmt.Errorf("template not found: %s", name)
 	}
@@ -73,3 +139,226 @@ func (m *Manager) RenderTemplate(name string, data interface{}) (string, error)
 
 	return buf.String(), nil
 }
+
+// satsPerBTC is the number of satoshis in one bitcoin
+const satsPerBTC = 100_000_000
+
+// templateFuncs returns the functions available in notification templates. Numbers are
+// formatted for lang. Functions taking numbers also accept the formatted numbers of the
+// template data, e.g. {{btc .Amount}} with an Amount of "1,250,000".
+//
+//	FormatBasic      {{FormatBasic 1234.5}}             1,235
+//	FormatDetailed   {{FormatDetailed 0.1234}}          0.123
+//	FormatRatePPM    {{FormatRatePPM .Fee .Amount}}     fee rate in ppm
+//	shortPubkey      {{shortPubkey .PeerPubKey}}        first 8 characters of a pubkey
+//	duration         {{duration 3725}}                  1h2m5s (seconds, Go durations or "1h30m")
+//	btc              {{btc 1250000}}                    0.0125
+//	date             {{date "2006-01-02 15:04" .Time "Europe/Berlin"}}
+//	upper            {{upper .PeerAlias}}
+//	default          {{default "unknown" .PeerAlias}}   fallback for empty values
+//	join             {{join ", " .ChanPoints}}
+//	add, sub, mul, div  {{add .A .B}}                   arithmetic on numbers
+//	pad              {{pad 10 .PeerAlias}}              right-pads a value to a width
+func templateFuncs(lang language.Tag) template.FuncMap {
+	num := func(v any) (float64, error) {
+		return toFloat(v, lang)
+	}
+
+	return template.FuncMap{
+		"FormatBasic": func(v any) (string, error) {
+			n, err := num(v)
+			if err != nil {
+				return "", err
+			}
+			return format.FormatBasic(n, lang), nil
+		},
+		"FormatDetailed": func(v any) (string, error) {
+			n, err := num(v)
+			if err != nil {
+				return "", err
+			}
+			return format.FormatDetailed(n, lang), nil
+		},
+		"FormatRatePPM": func(value any, total any) (string, error) {
+			v, err := num(value)
+			if err != nil {
+				return "", err
+			}
+			t, err := num(total)
+			if err != nil {
+				return "", err
+			}
+			return format.FormatRatePPM(v, t, lang), nil
+		},
+		"shortPubkey": format.FormatPubKey,
+		"duration": func(v any) (string, error) {
+			d, err := toDuration(v, lang)
+			if err != nil {
+				return "", err
+			}
+			return format.FormatDuration(d).String(), nil
+		},
+		"btc": func(v any) (string, error) {
+			sats, err := num(v)
+			if err != nil {
+				return "", err
+			}
+			formatted := message.NewPrinter(lang).Sprintf("%.8f", sats/satsPerBTC)
+			return strings.TrimRight(strings.TrimRight(formatted, "0"), ".,"), nil
+		},
+		"date": func(layout string, v any, timezone ...string) (string, error) {
+			t, err := toTime(v)
+			if err != nil {
+				return "", err
+			}
+			if len(timezone) > 0 {
+				loc, err := time.LoadLocation(timezone[0])
+				if err != nil {
+					return "", err
+				}
+				t = t.In(loc)
+			}
+			return t.Format(layout), nil
+		},
+		"upper": strings.ToUpper,
+		"default": func(def any, v any) any {
+			if isEmpty(v) {
+				return def
+			}
+			return v
+		},
+		"join": func(sep string, v any) (string, error) {
+			list := reflect.ValueOf(v)
+			if list.Kind() != reflect.Slice && list.Kind() != reflect.Array {
+				return "", fmt.Errorf("join: expected a list, got %T", v)
+			}
+			parts := make([]string, list.Len())
+			for i := range parts {
+				parts[i] = fmt.Sprint(list.Index(i).Interface())
+			}
+			return strings.Join(parts, sep), nil
+		},
+		"add": arithmetic(lang, func(a, b float64) (float64, error) { return a + b, nil }),
+		"sub": arithmetic(lang, func(a, b float64) (float64, error) { return a - b, nil }),
+		"mul": arithmetic(lang, func(a, b float64) (float64, error) { return a * b, nil }),
+		"div": arithmetic(lang, func(a, b float64) (float64, error) {
+			if b == 0 {
+				return 0, fmt.Errorf("div: division by zero")
+			}
+			return a / b, nil
+		}),
+		"pad": func(width int, v any) string {
+			s := fmt.Sprint(v)
+			if n := len([]rune(s)); n < width {
+				return s + strings.Repeat(" ", width-n)
+			}
+			return s
+		},
+	}
+}
+
+// arithmetic wraps an operation on two numbers as a template function
+func arithmetic(lang language.Tag, op func(a, b float64) (float64, error)) func(a, b any) (float64, error) {
+	return func(a, b any) (float64, error) {
+		x, err := toFloat(a, lang)
+		if err != nil {
+			return 0, err
+		}
+		y, err := toFloat(b, lang)
+		if err != nil {
+			return 0, err
+		}
+		return op(x, y)
+	}
+}
+
+// toFloat converts a number or a number formatted for lang to float64
+func toFloat(v any, lang language.Tag) (float64, error) {
+	if n, ok := v.(format.Number); ok {
+		return n.Value, nil
+	}
+	value := reflect.ValueOf(v)
+	switch value.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(value.Int()), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return float64(value.Uint()), nil
+	case reflect.Float32, reflect.Float64:
+		return value.Float(), nil
+	case reflect.String:
+		return parseNumber(value.String(), lang)
+	default:
+		return 0, fmt.Errorf("expected a number, got %T", v)
+	}
+}
+
+// parseNumber parses a number with the group and decimal separators of lang, e.g. "1.234,5" for German
+func parseNumber(s string, lang language.Tag) (float64, error) {
+	sample := []rune(message.NewPrinter(lang).Sprintf("%.1f", 1234.5))
+	group, decimal := string(sample[1]), string(sample[len(sample)-2])
+
+	normalized := strings.ReplaceAll(strings.TrimSpace(s), group, "")
+	normalized = strings.Replace(normalized, decimal, ".", 1)
+	n, err := strconv.ParseFloat(normalized, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid number %q", s)
+	}
+	return n, nil
+}
+
+// toDuration converts a time.Duration, a duration string or a number of seconds to a time.Duration
+func toDuration(v any, lang language.Tag) (time.Duration, error) {
+	switch d := v.(type) {
+	case time.Duration:
+		return d, nil
+	case string:
+		if parsed, err := time.ParseDuration(d); err == nil {
+			return parsed, nil
+		}
+	}
+
+	seconds, err := toFloat(v, lang)
+	if err != nil {
+		return 0, fmt.Errorf("duration: %w", err)
+	}
+	return time.Duration(seconds * float64(time.Second)), nil
+}
+
+// toTime converts a time.Time, a unix timestamp in seconds or an RFC 3339 string to a time.Time
+func toTime(v any) (time.Time, error) {
+	switch t := v.(type) {
+	case time.Time:
+		return t, nil
+	case *time.Time:
+		if t == nil {
+			return time.Time{}, fmt.Errorf("date: nil time")
+		}
+		return *t, nil
+	case string:
+		parsed, err := time.Parse(time.RFC3339, t)
+		if err != nil {
+			return time.Time{}, fmt.Errorf("date: %w", err)
+		}
+		return parsed, nil
+	}
+
+	seconds, err := toFloat(v, language.English)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("date: %w", err)
+	}
+	return time.Unix(int64(seconds), 0), nil
+}
+
+// isEmpty reports whether v is nil or the zero value of its type
+func isEmpty(v any) bool {
+	if v == nil {
+		return true
+	}
+	value := reflect.ValueOf(v)
+	switch value.Kind() {
+	case reflect.Slice, reflect.Map, reflect.Array, reflect.String:
+		return value.Len() == 0
+	default:
+		return value.IsZero()
+	}
+}
@@ -0,0 +1,152 @@
+package notify
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"text/template"
+	"time"
+
+	"github.com/Primexz/lndnotify/internal/config"
+	"github.com/Primexz/lndnotify/internal/events"
+	"golang.org/x/text/language"
+)
+
+func render(t *testing.T, lang language.Tag, text string, data any) (string, error) {
+	t.Helper()
+
+	tmpl, err := template.New("test").Funcs(templateFuncs(lang)).Parse(text)
+	if err != nil {
+		t.Fatalf("Parse(%q) error = %v", text, err)
+	}
+
+	var buf bytes.Buffer
+	err = tmpl.Execute(&buf, data)
+	return buf.String(), err
+}
+
+func TestTemplateFuncs(t *testing.T) {
+	ts := time.Date(2026, 4, 26, 12, 30, 0, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		lang     language.Tag
+		template string
+		data     any
+		want     string
+	}{
+		{"FormatBasic number", language.English, `{{FormatBasic 1234.5}}`, nil, "1,235"},
+		{"FormatBasic formatted", language.English, `{{FormatBasic .}}`, "1,250,000", "1,250,000"},
+		{"FormatBasic german", language.German, `{{FormatBasic 1234567}}`, nil, "1.234.567"},
+		{"FormatDetailed", language.English, `{{FormatDetailed 0.12345}}`, nil, "0.123"},
+		{"FormatDetailed german string", language.German, `{{FormatDetailed .}}`, "1.234,5", "1.234,5"},
+		{"FormatRatePPM", language.English, `{{FormatRatePPM 125 1000000}}`, nil, "125"},
+		{"FormatRatePPM formatted", language.English, `{{FormatRatePPM .Fee .Amount}}`, map[string]string{"Fee": "1.5", "Amount": "1,500,000"}, "1"},
+		{"shortPubkey", language.English, `{{shortPubkey .}}`, "02abcdef0123456789", "02abcdef"},
+		{"shortPubkey short", language.English, `{{shortPubkey .}}`, "02ab", "02ab"},
+		{"duration seconds", language.English, `{{duration 3725}}`, nil, "1h2m5s"},
+		{"duration string", language.English, `{{duration "90m"}}`, nil, "1h30m0s"},
+		{"duration value", language.English, `{{duration .}}`, 1500 * time.Millisecond, "2s"},
+		{"btc", language.English, `{{btc 1250000}}`, nil, "0.0125"},
+		{"btc whole", language.English, `{{btc .}}`, "100,000,000", "1"},
+		{"btc german", language.German, `{{btc 1250000}}`, nil, "0,0125"},
+		{"date", language.English, `{{date "2006-01-02 15:04" .}}`, ts, "2026-04-26 12:30"},
+		{"date timezone", language.English, `{{date "15:04 MST" . "Europe/Berlin"}}`, ts, "14:30 CEST"},
+		{"date unix", language.English, `{{date "2006-01-02" .}}`, ts.Unix(), "2026-04-26"},
+		{"upper", language.English, `{{upper .}}`, "acinq", "ACINQ"},
+		{"default empty", language.English, `{{default "unknown" .}}`, "", "unknown"},
+		{"default set", language.English, `{{default "unknown" .}}`, "ACINQ", "ACINQ"},
+		{"default zero", language.English, `{{default 1 .}}`, 0, "1"},
+		{"join", language.English, `{{join ", " .}}`, []string{"a", "b", "c"}, "a, b, c"},
+		{"join numbers", language.English, `{{join "-" .}}`, []int{1, 2}, "1-2"},
+		{"add", language.English, `{{add 1 "1,000"}}`, nil, "1001"},
+		{"sub", language.English, `{{sub 10 2.5}}`, nil, "7.5"},
+		{"mul", language.English, `{{mul 3 4}}`, nil, "12"},
+		{"div", language.English, `{{div 10 4}}`, nil, "2.5"},
+		{"pad", language.English, `[{{pad 6 .}}]`, "ab", "[ab    ]"},
+		{"pad longer", language.English, `[{{pad 1 .}}]`, "abc", "[abc]"},
+		{"nested", language.English, `{{FormatBasic (mul (btc 50000000) 2)}}`, nil, "1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := render(t, tt.lang, tt.template, tt.data)
+			if err != nil {
+				t.Fatalf("Execute() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("Execute() = %q; want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTemplateFuncsErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		template string
+		data     any
+		want     string
+	}{
+		{"FormatBasic invalid", `{{FormatBasic .}}`, "abc", `invalid number "abc"`},
+		{"FormatRatePPM invalid", `{{FormatRatePPM . 1}}`, []int{1}, "expected a number"},
+		{"duration invalid", `{{duration .}}`, "soon", "duration"},
+		{"date invalid timezone", `{{date "15:04" 0 "Mars/Olympus"}}`, nil, "unknown time zone"},
+		{"date invalid", `{{date "15:04" .}}`, "yesterday", "date"},
+		{"join invalid", `{{join ", " .}}`, "abc", "expected a list"},
+		{"div by zero", `{{div 1 0}}`, nil, "division by zero"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := render(t, language.English, tt.template, tt.data)
+			if err == nil {
+				t.Fatal("Execute() error = nil; want error")
+			}
+			if !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("Execute() error = %v; want error containing %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestCheckTemplates(t *testing.T) {
+	checks := CheckTemplates(config.NotificationTemplate{
+		Forward:      "Forwarded {{.Amount}} sats",
+		ChannelClose: "Closed {{.Capacity}} {{.Amout}}",
+		PeerOnline:   "{{if .PeerAlias}}",
+	}, language.English)
+
+	if len(checks) != len(events.EventTypes) {
+		t.Fatalf("CheckTemplates() returned %d checks; want %d", len(checks), len(events.EventTypes))
+	}
+
+	for _, check := range checks {
+		switch check.EventType {
+		case events.Event_FORWARD:
+			if check.Err != nil || check.Output != "Forwarded 1,000,125 sats" {
+				t.Errorf("forward check = %q, %v; want rendered output", check.Output, check.Err)
+			}
+		case events.Event_CHANNEL_CLOSE:
+			if check.Err == nil || !strings.Contains(check.Err.Error(), "Amout") {
+				t.Errorf("channel close check error = %v; want error naming the field", check.Err)
+			}
+		case events.Event_PEER_ONLINE:
+			if check.Err == nil {
+				t.Error("peer online check error = nil; want parse error")
+			}
+		default:
+			if check.Err == nil {
+				t.Errorf("%s check error = nil; want missing template error", check.EventType)
+			}
+		}
+	}
+}
+
+func TestSampleTemplateData(t *testing.T) {
+	for _, eventType := range events.EventTypes {
+		if events.SampleTemplateData(eventType, language.English) == nil {
+			t.Errorf("SampleTemplateData(%s) = nil", eventType)
+		}
+	}
+}
@@ -0,0 +1,68 @@
+package notify
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/Primexz/lndnotify/internal/config"
+	"github.com/Primexz/lndnotify/pkg/uploader"
+)
+
+// testFile is attached to test notifications to check the uploads of providers
+var testFile = &uploader.File{
+	Filename: "lndnotify-test.txt",
+	Data:     []byte("This file was sent by lndnotify to test file uploads.\n"),
+}
+
+// TestResult is the result of sending a test notification to a provider
+type TestResult struct {
+	Provider string
+	// Uploaded is set if the test file was sent with the message
+	Uploaded bool
+	Err      error
+}
+
+// SendTestNotification sends a notification with a test file directly to every provider, or
+// only to the named provider if name is set, and returns the result per provider. Routing,
+// filters, quiet hours, batching and the outbox are bypassed. The file is uploaded to
+// providers supporting uploads and included in the envelope of webhooks.
+func SendTestNotification(cfgs []config.ProviderConfig, name string, notification Notification) ([]TestResult, error) {
+	notification.File = testFile
+
+	var results []TestResult
+	for _, cfg := range cfgs {
+		if name != "" && cfg.Name != name {
+			continue
+		}
+
+		result := TestResult{Provider: cfg.Name}
+		providers, err := buildProviders([]config.ProviderConfig{cfg})
+		if err != nil {
+			result.Err = err
+		} else {
+			result.Uploaded, result.Err = providers[cfg.Name].sendTest(notification)
+		}
+		results = append(results, result)
+	}
+
+	if name != "" && len(results) == 0 {
+		return nil, fmt.Errorf("provider not configured: %s", name)
+	}
+	return results, nil
+}
+
+// sendTest sends a notification synchronously and reports whether its file was sent
+func (p Provider) sendTest(notification Notification) (bool, error) {
+	switch {
+	case p.webhook != nil:
+		body, err := p.webhook.body(notification)
+		if err != nil {
+			return false, err
+		}
+		return true, p.webhook.post(body)
+	case p.Uploader != nil:
+		return true, p.Uploader.Upload(notification.Message, notification.File)
+	default:
+		return false, errors.Join(p.Sender.Send(notification.Message, p.sendParams(notification.Severity))...)
+	}
+}
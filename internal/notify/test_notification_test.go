@@ -0,0 +1,61 @@
+package notify
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/Primexz/lndnotify/internal/config"
+	"github.com/Primexz/lndnotify/internal/events"
+)
+
+func TestSendTestNotification(t *testing.T) {
+	var envelope Envelope
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		if err := json.Unmarshal(body, &envelope); err != nil {
+			t.Errorf("decoding envelope: %v", err)
+		}
+	}))
+	defer server.Close()
+
+	webhook := config.WebhookConfig{Timeout: time.Second, MaxAttempts: 1}
+	// Routing is bypassed, so hook receives the test even though it excludes the event
+	cfgs := []config.ProviderConfig{
+		{Name: "hook", URL: server.URL, Type: config.ProviderTypeWebhook, Webhook: webhook, ExcludeEvents: []string{"forward_event"}},
+		{Name: "down", URL: "http://127.0.0.1:1/hook", Type: config.ProviderTypeWebhook, Webhook: webhook},
+		{Name: "invalid", URL: "invalid://token"},
+	}
+	notification := Notification{EventType: events.Event_FORWARD, Severity: events.SeverityInfo, Message: "💰 Forwarded 1,000 sats"}
+
+	results, err := SendTestNotification(cfgs, "", notification)
+	if err != nil {
+		t.Fatalf("SendTestNotification() error = %v", err)
+	}
+	if len(results) != 3 {
+		t.Fatalf("got %d results; want 3", len(results))
+	}
+	if r := results[0]; r.Provider != "hook" || r.Err != nil || !r.Uploaded {
+		t.Errorf("hook result = %+v; want sent with file", r)
+	}
+	if envelope.Message != notification.Message || envelope.File == nil || envelope.File.Name != testFile.Filename {
+		t.Errorf("envelope = %+v; want message and test file", envelope)
+	}
+	for _, r := range results[1:] {
+		if r.Err == nil {
+			t.Errorf("%s result = %+v; want error", r.Provider, r)
+		}
+	}
+
+	results, err = SendTestNotification(cfgs, "hook", notification)
+	if err != nil || len(results) != 1 || results[0].Provider != "hook" {
+		t.Errorf("SendTestNotification(hook) = %+v, %v; want only hook", results, err)
+	}
+
+	if _, err := SendTestNotification(cfgs, "unknown", notification); err == nil {
+		t.Error("SendTestNotification(unknown) error = nil; want error")
+	}
+}
@@ -1,13 +1,17 @@
 package notify
 
 import (
+	"net/url"
 	"sync"
 	"text/template"
 	"time"
 
 	"github.com/Primexz/lndnotify/internal/config"
+	"github.com/Primexz/lndnotify/internal/events"
+	"github.com/Primexz/lndnotify/internal/filter"
 	"github.com/Primexz/lndnotify/pkg/uploader"
 	"github.com/nicholas-fedor/shoutrrr/pkg/router"
+	"golang.org/x/text/language"
 )
 
 // ManagerConfig holds the configuration for the notification manager
@@ -15,8 +19,31 @@ type ManagerConfig struct {
 	Providers []config.ProviderConfig
 	Templates config.NotificationTemplate
 	Batching  config.BatchingConfig
+	Outbox    config.OutboxConfig
+
+	// Locale is used by the number formatting functions of the templates
+	Locale language.Tag
+
+	// OnDelivery is called with the delivery results of notifications with an ID, if set
+	OnDelivery DeliveryFunc
 }
 
+// DeliveryStatus is the state of a notification at a provider
+type DeliveryStatus string
+
+const (
+	DeliverySent   DeliveryStatus = "sent"
+	DeliveryFailed DeliveryStatus = "failed"
+	// DeliveryQueued notifications are in the outbox, waiting for delivery
+	DeliveryQueued DeliveryStatus = "queued"
+	// DeliveryHeld notifications are held back until the quiet hours of the provider end
+	DeliveryHeld DeliveryStatus = "held"
+)
+
+// DeliveryFunc receives the delivery result of notifications at a provider. Batched notifications
+// are reported together. err is only set for failed deliveries.
+type DeliveryFunc func(ids []uint64, provider string, status DeliveryStatus, err error)
+
 // ProviderConfig holds the configuration for a notification provider
 type ProviderConfig struct {
 	URL  string
@@ -28,14 +55,52 @@ type NotificationTemplates struct {
 	Forward string
 }
 
-// Provider represents a notification provider with sender and uploader
+// Provider represents a notification provider with sender and uploader, or a webhook
 type Provider struct {
 	Sender   *router.ServiceRouter
 	Uploader uploader.Uploader
+
+	// events and excludeEvents restrict which event types are routed to the provider
+	events        map[events.EventType]struct{}
+	excludeEvents map[events.EventType]struct{}
+
+	// filters are the expression rules of the provider per event type
+	filters filter.Rules
+
+	// minSeverity is the lowest severity of events routed to the provider, empty for all
+	minSeverity events.Severity
+
+	// service is the shoutrrr service name, used to map severities to its priority params
+	service string
+
+	// severityPriority enables the priority params per severity, urlParams are the params
+	// set in the provider URL, which are never overridden
+	severityPriority bool
+	urlParams        url.Values
+
+	// quietHours is the do-not-disturb schedule of the provider, nil if not configured
+	quietHours *quietHours
+
+	// webhook posts JSON envelopes instead of sending text via Sender, nil for shoutrrr providers
+	webhook *webhook
 }
 
-// QueuedNotification represents a notification waiting to be sent
-type QueuedNotification struct {
+// Notification is a rendered message and the event it was created from. Notifications
+// without an event type (e.g. status messages) reach all providers.
+type Notification struct {
+	// ID identifies the notification in delivery reports, 0 if the delivery is not reported
+	ID        uint64
+	EventType events.EventType
+	Severity  events.Severity
+
+	// Node, Timestamp and Data describe the source event for webhooks. Data is the
+	// template data of the event with English number formatting.
+	Node      events.NodeInfo
+	Timestamp time.Time
+	Data      interface{}
+
+	// Env is the filter environment of the event, nil if there is no template data
+	Env     filter.Env
 	Message string
 	File    *uploader.File
 }
@@ -43,12 +108,25 @@ type QueuedNotification struct {
 // Manager handles notification delivery
 type Manager struct {
 	cfg       *ManagerConfig
+	lastReset time.Time
+
+	// providers and templates are replaced as a whole on reload
 	providers map[string]Provider
 	templates map[string]*template.Template
-	lastReset time.Time
+	mu        sync.RWMutex
 
 	// Batching fields
-	batchQueue []QueuedNotification
+	batchQueue []Notification
 	batchMu    sync.Mutex
 	flushTimer *time.Timer
+
+	// outbox persists notifications until delivered, nil if disabled
+	outbox *outbox
+
+	// held holds the notifications of each provider during its quiet hours
+	held   map[string][]Notification
+	heldMu sync.Mutex
+
+	stopCh chan struct{}
+	wg     sync.WaitGroup
 }
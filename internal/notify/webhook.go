@@ -0,0 +1,130 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/Primexz/lndnotify/internal/config"
+	"github.com/cenkalti/backoff/v5"
+)
+
+// WebhookSignatureHeader holds the hex encoded HMAC-SHA256 of the request body, prefixed
+// with "sha256=", if a secret is configured
+const WebhookSignatureHeader = "X-Lndnotify-Signature"
+
+// webhook posts notifications as JSON envelopes to an HTTP endpoint
+type webhook struct {
+	url         string
+	secret      []byte
+	headers     map[string]string
+	client      *http.Client
+	maxAttempts uint
+
+	// newBackOff returns the backoff between attempts, replaced in tests
+	newBackOff func() backoff.BackOff
+}
+
+// webhookStatusError is returned for unsuccessful HTTP responses
+type webhookStatusError struct {
+	code int
+	body string
+}
+
+func (e *webhookStatusError) Error() string {
+	return fmt.Sprintf("webhook returned status %d: %s", e.code, e.body)
+}
+
+func newWebhook(url string, cfg config.WebhookConfig) *webhook {
+	w := &webhook{
+		url:         url,
+		headers:     cfg.Headers,
+		client:      &http.Client{Timeout: cfg.Timeout},
+		maxAttempts: 1,
+		newBackOff: func() backoff.BackOff {
+			return backoff.NewExponentialBackOff()
+		},
+	}
+	if cfg.Secret != "" {
+		w.secret = []byte(cfg.Secret)
+	}
+	if cfg.MaxAttempts > 1 {
+		w.maxAttempts = uint(cfg.MaxAttempts)
+	}
+	return w
+}
+
+// body encodes the envelope of a notification
+func (w *webhook) body(n Notification) (string, error) {
+	body, err := json.Marshal(newEnvelope(n, true))
+	if err != nil {
+		return "", fmt.Errorf("encoding webhook envelope: %w", err)
+	}
+	return string(body), nil
+}
+
+// sign returns the signature header value of a body
+func (w *webhook) sign(body []byte) string {
+	mac := hmac.New(sha256.New, w.secret)
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// post sends a body to the webhook, retrying failed requests. Client errors except 429 are
+// not retried and returned as backoff.PermanentError.
+func (w *webhook) post(body string) error {
+	_, err := backoff.Retry(context.Background(), func() (struct{}, error) {
+		return struct{}{}, w.request([]byte(body))
+	}, backoff.WithBackOff(w.newBackOff()), backoff.WithMaxTries(w.maxAttempts))
+
+	var status *webhookStatusError
+	if errors.As(err, &status) && !retryableStatus(status.code) {
+		return backoff.Permanent(status)
+	}
+	return err
+}
+
+// request sends a single POST request
+func (w *webhook) request(body []byte) error {
+	req, err := http.NewRequest(http.MethodPost, w.url, bytes.NewReader(body))
+	if err != nil {
+		return backoff.Permanent(fmt.Errorf("creating webhook request: %w", err))
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "lndnotify")
+	for key, value := range w.headers {
+		req.Header.Set(key, value)
+	}
+	if w.secret != nil {
+		req.Header.Set(WebhookSignatureHeader, w.sign(body))
+	}
+
+	resp, err := w.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return nil
+	}
+
+	msg, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+	err = &webhookStatusError{code: resp.StatusCode, body: string(bytes.TrimSpace(msg))}
+	if !retryableStatus(resp.StatusCode) {
+		return backoff.Permanent(err)
+	}
+	return err
+}
+
+// retryableStatus reports whether a request failing with the status code may succeed later
+func retryableStatus(code int) bool {
+	return code == http.StatusTooManyRequests || code >= 500
+}
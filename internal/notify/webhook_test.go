@@ -0,0 +1,136 @@
+package notify
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/Primexz/lndnotify/internal/config"
+	"github.com/Primexz/lndnotify/internal/events"
+	"github.com/Primexz/lndnotify/pkg/uploader"
+	"github.com/cenkalti/backoff/v5"
+)
+
+func TestWebhookPost(t *testing.T) {
+	var (
+		attempts int
+		body     []byte
+		header   http.Header
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		body, _ = io.ReadAll(r.Body)
+		header = r.Header
+		if attempts == 1 {
+			w.WriteHeader(http.StatusBadGateway)
+		}
+	}))
+	defer server.Close()
+
+	w := newWebhook(server.URL, config.WebhookConfig{
+		Secret:      "secret",
+		Headers:     map[string]string{"Authorization": "Bearer token"},
+		Timeout:     time.Second,
+		MaxAttempts: 3,
+	})
+	w.newBackOff = func() backoff.BackOff { return &backoff.ZeroBackOff{} }
+
+	payload, err := w.body(Notification{
+		EventType: events.Event_BACKUP_MULTI,
+		Severity:  events.SeverityInfo,
+		Node:      events.NodeInfo{Name: "alice", Alias: "Alice's Node"},
+		Timestamp: time.Date(2026, 4, 26, 12, 0, 0, 0, time.UTC),
+		Data:      map[string]string{"Filename": "channel.backup"},
+		Message:   "backup",
+		File:      &uploader.File{Filename: "channel.backup", Data: []byte{1, 2, 3}},
+	})
+	if err != nil {
+		t.Fatalf("body() error = %v", err)
+	}
+	if err := w.post(payload); err != nil {
+		t.Fatalf("post() error = %v", err)
+	}
+
+	if attempts != 2 {
+		t.Errorf("got %d attempts; want 2", attempts)
+	}
+	if got := header.Get("Content-Type"); got != "application/json" {
+		t.Errorf("Content-Type = %q; want application/json", got)
+	}
+	if got := header.Get("Authorization"); got != "Bearer token" {
+		t.Errorf("Authorization = %q; want Bearer token", got)
+	}
+	// echo -n "$body" | openssl dgst -sha256 -hmac secret
+	if got, want := header.Get(WebhookSignatureHeader), w.sign(body); got != want {
+		t.Errorf("%s = %q; want %q", WebhookSignatureHeader, got, want)
+	}
+
+	want := `{"version":1,"event_type":"backup_multi_event","timestamp":"2026-04-26T12:00:00Z",` +
+		`"node":{"name":"alice","alias":"Alice's Node"},"severity":"info","data":{"Filename":"channel.backup"},` +
+		`"message":"backup","file":{"name":"channel.backup","data":"AQID"}}`
+	if string(body) != want {
+		t.Errorf("body = %s; want %s", body, want)
+	}
+}
+
+func TestWebhookPostPermanentError(t *testing.T) {
+	attempts := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		http.Error(w, "invalid payload", http.StatusBadRequest)
+	}))
+	defer server.Close()
+
+	w := newWebhook(server.URL, config.WebhookConfig{Timeout: time.Second, MaxAttempts: 3})
+	w.newBackOff = func() backoff.BackOff { return &backoff.ZeroBackOff{} }
+
+	err := w.post(`{}`)
+	var permanent *backoff.PermanentError
+	if !errors.As(err, &permanent) {
+		t.Errorf("post() error = %v; want permanent error", err)
+	}
+	if attempts != 1 {
+		t.Errorf("got %d attempts; want 1", attempts)
+	}
+}
+
+func TestWebhookDeliveryReport(t *testing.T) {
+	ok := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer ok.Close()
+	failing := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "invalid payload", http.StatusBadRequest)
+	}))
+	defer failing.Close()
+
+	reports := make(map[string]DeliveryStatus)
+	m := &Manager{
+		cfg: &ManagerConfig{OnDelivery: func(ids []uint64, provider string, status DeliveryStatus, err error) {
+			if len(ids) != 1 || ids[0] != 7 {
+				t.Errorf("reported ids = %v; want [7]", ids)
+			}
+			if (status == DeliveryFailed) != (err != nil) {
+				t.Errorf("reported %s with error %v", status, err)
+			}
+			reports[provider] = status
+		}},
+		providers: map[string]Provider{
+			"ok":      {webhook: newWebhook(ok.URL, config.WebhookConfig{Timeout: time.Second})},
+			"failing": {webhook: newWebhook(failing.URL, config.WebhookConfig{Timeout: time.Second})},
+		},
+	}
+
+	m.send(Notification{ID: 7, EventType: events.Event_FORWARD, Message: "forward"})
+	if reports["ok"] != DeliverySent || reports["failing"] != DeliveryFailed {
+		t.Errorf("reports = %v; want ok sent and failing failed", reports)
+	}
+
+	// Notifications without an ID are not reported
+	clear(reports)
+	m.send(Notification{EventType: events.Event_FORWARD, Message: "forward"})
+	if len(reports) != 0 {
+		t.Errorf("reports = %v; want none", reports)
+	}
+}
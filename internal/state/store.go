@@ -0,0 +1,95 @@
+package state
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+const stateFile = "state.json"
+
+// Store is a small key-value store for handler checkpoints. All values are kept in
+// memory and written to a single JSON file on every update. A store without a
+// directory keeps its values in memory only.
+type Store struct {
+	path string
+	data map[string]json.RawMessage
+	mu   sync.Mutex
+}
+
+// NewStore opens the store in the given directory. If dir is empty, the returned
+// store is not persisted.
+func NewStore(dir string) (*Store, error) {
+	s := &Store{
+		data: make(map[string]json.RawMessage),
+	}
+	if dir == "" {
+		return s, nil
+	}
+
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, fmt.Errorf("creating state directory: %w", err)
+	}
+	s.path = filepath.Join(dir, stateFile)
+
+	// #nosec G304 -- path is built from the configured state directory
+	data, err := os.ReadFile(s.path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return s, nil
+		}
+		return nil, fmt.Errorf("reading state file: %w", err)
+	}
+
+	if err := json.Unmarshal(data, &s.data); err != nil {
+		return nil, fmt.Errorf("parsing state file: %w", err)
+	}
+	return s, nil
+}
+
+// Load decodes the value stored under key into v. It returns false if the key does not exist.
+func (s *Store) Load(key string, v any) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	raw, ok := s.data[key]
+	if !ok {
+		return false, nil
+	}
+	if err := json.Unmarshal(raw, v); err != nil {
+		return false, fmt.Errorf("decoding state %q: %w", key, err)
+	}
+	return true, nil
+}
+
+// Save stores v under key and writes the store to disk
+func (s *Store) Save(key string, v any) error {
+	raw, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Errorf("encoding state %q: %w", key, err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.data[key] = raw
+	if s.path == "" {
+		return nil
+	}
+
+	data, err := json.MarshalIndent(s.data, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encoding state file: %w", err)
+	}
+
+	tmp := s.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o600); err != nil {
+		return fmt.Errorf("writing state file: %w", err)
+	}
+	if err := os.Rename(tmp, s.path); err != nil {
+		return fmt.Errorf("writing state file: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,75 @@
+package state
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+type checkpoint struct {
+	Offset  uint32          `json:"offset"`
+	Aliases map[string]bool `json:"aliases"`
+}
+
+func TestStoreRoundTrip(t *testing.T) {
+	dir := t.TempDir()
+
+	store, err := NewStore(dir)
+	if err != nil {
+		t.Fatalf("NewStore() error = %v", err)
+	}
+	if err := store.Save("checkpoint", checkpoint{Offset: 42, Aliases: map[string]bool{"ACINQ": true}}); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+	if err := store.Save("version", "v0.19.0-beta"); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+
+	reopened, err := NewStore(dir)
+	if err != nil {
+		t.Fatalf("NewStore() error = %v", err)
+	}
+
+	var got checkpoint
+	if ok, err := reopened.Load("checkpoint", &got); err != nil || !ok {
+		t.Fatalf("Load() = %v, %v; want true, nil", ok, err)
+	}
+	if got.Offset != 42 || !got.Aliases["ACINQ"] {
+		t.Errorf("Load() checkpoint = %+v; want offset 42 and alias ACINQ", got)
+	}
+
+	var version string
+	if ok, err := reopened.Load("version", &version); err != nil || !ok || version != "v0.19.0-beta" {
+		t.Errorf("Load() version = %q, %v, %v; want v0.19.0-beta, true, nil", version, ok, err)
+	}
+
+	if ok, err := reopened.Load("missing", &version); err != nil || ok {
+		t.Errorf("Load() missing key = %v, %v; want false, nil", ok, err)
+	}
+}
+
+func TestStoreWithoutDirectory(t *testing.T) {
+	store, err := NewStore("")
+	if err != nil {
+		t.Fatalf("NewStore() error = %v", err)
+	}
+	if err := store.Save("offset", 7); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+
+	var offset int
+	if ok, err := store.Load("offset", &offset); err != nil || !ok || offset != 7 {
+		t.Errorf("Load() = %d, %v, %v; want 7, true, nil", offset, ok, err)
+	}
+}
+
+func TestStoreInvalidFile(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, stateFile), []byte("{"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := NewStore(dir); err == nil {
+		t.Error("NewStore() error = nil; want error")
+	}
+}
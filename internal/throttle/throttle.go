@@ -0,0 +1,275 @@
+package throttle
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Primexz/lndnotify/internal/config"
+	"github.com/Primexz/lndnotify/internal/events"
+	"github.com/Primexz/lndnotify/internal/filter"
+	"github.com/Primexz/lndnotify/pkg/format"
+	log "github.com/sirupsen/logrus"
+	"golang.org/x/text/language"
+	"golang.org/x/time/rate"
+)
+
+// flushInterval is how often ended suppressions are checked
+const flushInterval = time.Second
+
+// SummaryFunc sends the summary of suppressed events of an event type
+type SummaryFunc func(eventType events.EventType, message string)
+
+// rule holds the dedup keys and the rate limit of an event type
+type rule struct {
+	keys    []string
+	window  time.Duration
+	limiter *rate.Limiter
+}
+
+// window counts the duplicates of an event within the suppression window
+type window struct {
+	eventType  events.EventType
+	node       string
+	key        string
+	length     time.Duration
+	end        time.Time
+	suppressed int
+}
+
+// Throttle suppresses duplicate events and limits the rate of events. The number of
+// suppressed events is reported with a summary once the suppression ends.
+type Throttle struct {
+	rules     map[events.EventType]rule
+	global    *rate.Limiter
+	onSummary SummaryFunc
+	now       func() time.Time
+
+	mu      sync.Mutex
+	windows map[string]*window
+	limited map[events.EventType]int
+	// ended holds windows with suppressed events that were replaced before they were flushed
+	ended []*window
+
+	done chan struct{}
+	wg   sync.WaitGroup
+}
+
+// New creates a throttle. Summaries of suppressed events are passed to onSummary.
+func New(cfg config.ThrottleConfig, onSummary SummaryFunc) (*Throttle, error) {
+	t := &Throttle{
+		rules:     make(map[events.EventType]rule),
+		global:    newLimiter(cfg.Rate, cfg.Burst),
+		onSummary: onSummary,
+		now:       time.Now,
+		windows:   make(map[string]*window),
+		limited:   make(map[events.EventType]int),
+		done:      make(chan struct{}),
+	}
+
+	for name, eventCfg := range cfg.Events {
+		eventType, err := events.ParseEventType(name)
+		if err != nil {
+			return nil, fmt.Errorf("invalid throttle config: %w", err)
+		}
+
+		// Keys must be variables of the template data of the event
+		sample := filter.NewEnv(events.SampleTemplateData(eventType, language.English))
+		for _, key := range eventCfg.Keys {
+			if _, ok := sample[key]; !ok {
+				return nil, fmt.Errorf("invalid throttle key %q of %s: no such template variable", key, eventType)
+			}
+		}
+
+		window := eventCfg.Window
+		if window == 0 {
+			window = cfg.Window
+		}
+		t.rules[eventType] = rule{
+			keys:    eventCfg.Keys,
+			window:  window,
+			limiter: newLimiter(eventCfg.Rate, eventCfg.Burst),
+		}
+	}
+
+	return t, nil
+}
+
+// newLimiter creates a token bucket for a rate in events per minute, nil if the rate is unlimited
+func newLimiter(perMinute float64, burst int) *rate.Limiter {
+	if perMinute <= 0 {
+		return nil
+	}
+	if burst <= 0 {
+		burst = int(math.Max(1, math.Ceil(perMinute)))
+	}
+	return rate.NewLimiter(rate.Limit(perMinute/60), burst)
+}
+
+// Start starts sending the summaries of ended suppressions
+func (t *Throttle) Start() {
+	t.wg.Add(1)
+	go func() {
+		defer t.wg.Done()
+
+		ticker := time.NewTicker(flushInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-t.done:
+				return
+			case <-ticker.C:
+				t.flush(false)
+			}
+		}
+	}()
+}
+
+// Stop stops the throttle and sends the summaries of all current suppressions
+func (t *Throttle) Stop() {
+	close(t.done)
+	t.wg.Wait()
+	t.flush(true)
+}
+
+// Allow reports whether an event should be sent. Events are suppressed if an event of the
+// same node with the same key was sent within the suppression window, or if the rate limit
+// of the event type or the global rate limit is exceeded.
+func (t *Throttle) Allow(eventType events.EventType, env filter.Env) bool {
+	now := t.now()
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	r := t.rules[eventType]
+
+	var id, node, key string
+	if len(r.keys) > 0 {
+		node, _ = env["NodeName"].(string)
+		key = eventKey(r.keys, env)
+		id = eventType.String() + "|" + node + "|" + key
+		if w, ok := t.windows[id]; ok && now.Before(w.end) {
+			w.suppressed++
+			return false
+		}
+	}
+
+	typeReservation, ok := reserve(r.limiter, now)
+	if !ok {
+		t.limited[eventType]++
+		return false
+	}
+	if _, ok := reserve(t.global, now); !ok {
+		// The event is not sent, so the token of the event type is returned
+		if typeReservation != nil {
+			typeReservation.CancelAt(now)
+		}
+		t.limited[eventType]++
+		return false
+	}
+
+	if id != "" {
+		// An ended window that was not flushed yet is kept for its summary
+		if w, ok := t.windows[id]; ok && w.suppressed > 0 {
+			t.ended = append(t.ended, w)
+		}
+		t.windows[id] = &window{
+			eventType: eventType,
+			node:      node,
+			key:       key,
+			length:    r.window,
+			end:       now.Add(r.window),
+		}
+	}
+	return true
+}
+
+// reserve takes a token from the limiter. It returns false if no token is available.
+func reserve(limiter *rate.Limiter, now time.Time) (*rate.Reservation, bool) {
+	if limiter == nil {
+		return nil, true
+	}
+	reservation := limiter.ReserveN(now, 1)
+	if !reservation.OK() || reservation.DelayFrom(now) > 0 {
+		reservation.CancelAt(now)
+		return nil, false
+	}
+	return reservation, true
+}
+
+// flush sends the summaries of ended suppressions, or of all suppressions if all is set
+func (t *Throttle) flush(all bool) {
+	now := t.now()
+	type summary struct {
+		eventType events.EventType
+		message   string
+	}
+	var summaries []summary
+
+	t.mu.Lock()
+	ended := t.ended
+	t.ended = nil
+	for id, w := range t.windows {
+		if !all && now.Before(w.end) {
+			continue
+		}
+		delete(t.windows, id)
+		ended = append(ended, w)
+	}
+	for _, w := range ended {
+		if w.suppressed == 0 {
+			continue
+		}
+		key := w.key
+		if w.node != "" {
+			key = "NodeName=" + w.node + ", " + key
+		}
+		summaries = append(summaries, summary{w.eventType, fmt.Sprintf("🔇 %d similar %s events suppressed in the last %s (%s)",
+			w.suppressed, eventName(w.eventType), format.FormatDuration(w.length), key)})
+	}
+	for eventType, count := range t.limited {
+		if !all && !t.available(eventType, now) {
+			continue
+		}
+		delete(t.limited, eventType)
+		summaries = append(summaries, summary{eventType, fmt.Sprintf("🔇 %d %s events suppressed by the rate limit",
+			count, eventName(eventType))})
+	}
+	t.mu.Unlock()
+
+	sort.Slice(summaries, func(i, j int) bool {
+		return summaries[i].message < summaries[j].message
+	})
+	for _, s := range summaries {
+		log.WithField("event", s.eventType).Info(s.message)
+		t.onSummary(s.eventType, s.message)
+	}
+}
+
+// available reports whether the rate limits allow an event of the type again
+func (t *Throttle) available(eventType events.EventType, now time.Time) bool {
+	for _, limiter := range []*rate.Limiter{t.rules[eventType].limiter, t.global} {
+		if limiter != nil && limiter.TokensAt(now) < 1 {
+			return false
+		}
+	}
+	return true
+}
+
+// eventKey joins the values of the key variables, e.g. "InChanId=1, OutChanId=2"
+func eventKey(keys []string, env filter.Env) string {
+	parts := make([]string, len(keys))
+	for i, key := range keys {
+		parts[i] = fmt.Sprintf("%s=%v", key, env[key])
+	}
+	return strings.Join(parts, ", ")
+}
+
+// eventName returns a readable name of an event type, e.g. "failed htlc" for failed_htlc_event
+func eventName(eventType events.EventType) string {
+	return strings.ReplaceAll(strings.TrimSuffix(eventType.String(), "_event"), "_", " ")
+}
@@ -0,0 +1,226 @@
+package throttle
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Primexz/lndnotify/internal/config"
+	"github.com/Primexz/lndnotify/internal/events"
+	"github.com/Primexz/lndnotify/internal/filter"
+)
+
+type sentSummary struct {
+	eventType events.EventType
+	message   string
+}
+
+func newTestThrottle(t *testing.T, cfg config.ThrottleConfig) (*Throttle, *time.Time, *[]sentSummary) {
+	t.Helper()
+
+	var summaries []sentSummary
+	throttle, err := New(cfg, func(eventType events.EventType, message string) {
+		summaries = append(summaries, sentSummary{eventType, message})
+	})
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	now := time.Date(2026, 4, 26, 12, 0, 0, 0, time.UTC)
+	throttle.now = func() time.Time { return now }
+	return throttle, &now, &summaries
+}
+
+func failedHtlc(inChan, outChan uint64) filter.Env {
+	return filter.NewEnv(&events.FailedHtlcLinkTemplate{
+		InChanId:    inChan,
+		OutChanId:   outChan,
+		WireFailure: "TEMPORARY_CHANNEL_FAILURE",
+	})
+}
+
+func TestThrottleDedup(t *testing.T) {
+	throttle, now, summaries := newTestThrottle(t, config.ThrottleConfig{
+		Window: 10 * time.Minute,
+		Events: map[string]config.EventThrottleConfig{
+			"failed_htlc_event": {Keys: []string{"InChanId", "OutChanId", "WireFailure"}},
+		},
+	})
+
+	if !throttle.Allow(events.Event_FAILED_HTLC, failedHtlc(1, 2)) {
+		t.Fatal("first event suppressed")
+	}
+	for i := 0; i < 3; i++ {
+		if throttle.Allow(events.Event_FAILED_HTLC, failedHtlc(1, 2)) {
+			t.Fatal("duplicate event allowed")
+		}
+	}
+	if !throttle.Allow(events.Event_FAILED_HTLC, failedHtlc(1, 3)) {
+		t.Fatal("event with another key suppressed")
+	}
+	if !throttle.Allow(events.Event_FORWARD, filter.Env{}) {
+		t.Fatal("event type without throttle config suppressed")
+	}
+
+	throttle.flush(false)
+	if len(*summaries) != 0 {
+		t.Fatalf("summaries sent before the window ended: %v", *summaries)
+	}
+
+	*now = now.Add(10 * time.Minute)
+	throttle.flush(false)
+	if len(*summaries) != 1 {
+		t.Fatalf("got %d summaries; want 1", len(*summaries))
+	}
+	summary := (*summaries)[0]
+	if summary.eventType != events.Event_FAILED_HTLC {
+		t.Errorf("summary event type = %s; want %s", summary.eventType, events.Event_FAILED_HTLC)
+	}
+	for _, want := range []string{"3 similar failed htlc events suppressed", "10m0s", "InChanId=1, OutChanId=2"} {
+		if !strings.Contains(summary.message, want) {
+			t.Errorf("summary %q does not contain %q", summary.message, want)
+		}
+	}
+
+	// The window has ended, so the event is sent again
+	if !throttle.Allow(events.Event_FAILED_HTLC, failedHtlc(1, 2)) {
+		t.Error("event suppressed after the window ended")
+	}
+}
+
+func TestThrottleDedupPerNode(t *testing.T) {
+	throttle, _, summaries := newTestThrottle(t, config.ThrottleConfig{
+		Window: 10 * time.Minute,
+		Events: map[string]config.EventThrottleConfig{
+			"peer_online_event": {Keys: []string{"PeerPubKey"}},
+		},
+	})
+
+	peerOnline := func(node string) filter.Env {
+		return filter.NewEnv(&events.PeerOnlineTemplate{
+			NodeTemplate: events.NodeTemplate{NodeName: node},
+			PeerPubKey:   "02abc",
+		})
+	}
+
+	if !throttle.Allow(events.Event_PEER_ONLINE, peerOnline("alice")) {
+		t.Fatal("first event suppressed")
+	}
+	if !throttle.Allow(events.Event_PEER_ONLINE, peerOnline("bob")) {
+		t.Fatal("event of another node suppressed")
+	}
+	if throttle.Allow(events.Event_PEER_ONLINE, peerOnline("bob")) {
+		t.Fatal("duplicate event allowed")
+	}
+
+	throttle.flush(true)
+	if len(*summaries) != 1 || !strings.Contains((*summaries)[0].message, "NodeName=bob, PeerPubKey=02abc") {
+		t.Errorf("summaries = %v; want summary of bob", *summaries)
+	}
+}
+
+func TestThrottleDedupEndedWindow(t *testing.T) {
+	throttle, now, summaries := newTestThrottle(t, config.ThrottleConfig{
+		Window: 10 * time.Minute,
+		Events: map[string]config.EventThrottleConfig{
+			"failed_htlc_event": {Keys: []string{"InChanId", "OutChanId", "WireFailure"}},
+		},
+	})
+
+	throttle.Allow(events.Event_FAILED_HTLC, failedHtlc(1, 2))
+	throttle.Allow(events.Event_FAILED_HTLC, failedHtlc(1, 2))
+	throttle.Allow(events.Event_FAILED_HTLC, failedHtlc(1, 2))
+
+	// The window ended, but the event arrives before the flush
+	*now = now.Add(10 * time.Minute)
+	if !throttle.Allow(events.Event_FAILED_HTLC, failedHtlc(1, 2)) {
+		t.Fatal("event suppressed after the window ended")
+	}
+
+	throttle.flush(false)
+	if len(*summaries) != 1 || !strings.Contains((*summaries)[0].message, "2 similar failed htlc events suppressed") {
+		t.Fatalf("summaries = %v; want summary of the ended window", *summaries)
+	}
+	throttle.flush(true)
+	if len(*summaries) != 1 {
+		t.Errorf("summaries = %v; want no summary of the new window", *summaries)
+	}
+}
+
+func TestThrottleRateLimit(t *testing.T) {
+	throttle, now, summaries := newTestThrottle(t, config.ThrottleConfig{
+		Events: map[string]config.EventThrottleConfig{
+			"forward_event": {Rate: 2, Burst: 2},
+		},
+	})
+
+	var allowed int
+	for i := 0; i < 5; i++ {
+		if throttle.Allow(events.Event_FORWARD, filter.Env{}) {
+			allowed++
+		}
+	}
+	if allowed != 2 {
+		t.Errorf("allowed %d events; want 2", allowed)
+	}
+	if !throttle.Allow(events.Event_INVOICE_SETTLED, filter.Env{}) {
+		t.Error("event type without rate limit suppressed")
+	}
+
+	throttle.flush(false)
+	if len(*summaries) != 0 {
+		t.Fatalf("summaries sent while rate limited: %v", *summaries)
+	}
+
+	// 2 events per minute refill a token every 30 seconds
+	*now = now.Add(30 * time.Second)
+	throttle.flush(false)
+	if len(*summaries) != 1 || !strings.Contains((*summaries)[0].message, "3 forward events suppressed by the rate limit") {
+		t.Fatalf("summaries = %v; want rate limit summary of 3 events", *summaries)
+	}
+	if !throttle.Allow(events.Event_FORWARD, filter.Env{}) {
+		t.Error("event suppressed after the token was refilled")
+	}
+}
+
+func TestThrottleGlobalRateLimit(t *testing.T) {
+	throttle, _, summaries := newTestThrottle(t, config.ThrottleConfig{
+		Rate:  1,
+		Burst: 1,
+		Events: map[string]config.EventThrottleConfig{
+			"forward_event": {Rate: 10},
+		},
+	})
+
+	if !throttle.Allow(events.Event_INVOICE_SETTLED, filter.Env{}) {
+		t.Fatal("first event suppressed")
+	}
+	if throttle.Allow(events.Event_FORWARD, filter.Env{}) {
+		t.Fatal("event allowed above the global rate limit")
+	}
+
+	// The token of the event type is returned if the global limit suppresses the event
+	if tokens := throttle.rules[events.Event_FORWARD].limiter.TokensAt(throttle.now()); tokens != 10 {
+		t.Errorf("forward tokens = %v; want 10", tokens)
+	}
+
+	throttle.flush(true)
+	if len(*summaries) != 1 || (*summaries)[0].eventType != events.Event_FORWARD {
+		t.Errorf("summaries = %v; want forward rate limit summary", *summaries)
+	}
+}
+
+func TestNewErrors(t *testing.T) {
+	tests := map[string]config.ThrottleConfig{
+		"unknown event type": {Events: map[string]config.EventThrottleConfig{"unknown_event": {}}},
+		"unknown key":        {Events: map[string]config.EventThrottleConfig{"forward_event": {Keys: []string{"ChanId"}}}},
+	}
+
+	for name, cfg := range tests {
+		t.Run(name, func(t *testing.T) {
+			if _, err := New(cfg, nil); err == nil {
+				t.Error("New() error = nil; want error")
+			}
+		})
+	}
+}
@@ -2,6 +2,7 @@ package main
 
 import (
 	"flag"
+	"os"
 	"runtime"
 
 	"github.com/Primexz/lndnotify/internal/app"
@@ -18,6 +19,14 @@ var (
 func main() {
 	versionFlag := flag.Bool("version", false, "Print version and Go version")
 	configPath := flag.String("config", "config.yaml", "Path to configuration file")
+	deadLettersFlag := flag.Bool("dead-letters", false, "List notifications that could not be delivered and exit")
+	checkConfigFlag := flag.Bool("check-config", false, "Validate the config, render all templates with sample data and exit")
+	testNotifyFlag := flag.Bool("test-notify", false, "Send a notification with sample data to the providers and exit")
+	providerFlag := flag.String("provider", "", "Provider to send the test notification to, all providers if empty")
+	eventFlag := flag.String("event", "forward_event", "Event type of the test notification")
+	decryptBackupFlag := flag.String("decrypt-backup", "", "Decrypt an encrypted channel backup attachment and exit")
+	identityFlag := flag.String("identity", "", "age identity file to decrypt the backup, prompts for the passphrase if empty")
+	outputFlag := flag.String("output", "", "Output file of the decrypted backup, the input file without .age if empty")
 	flag.Parse()
 
 	log.SetFormatter(&prefixed.TextFormatter{
@@ -39,5 +48,31 @@ func main() {
 		return
 	}
 
+	if *checkConfigFlag {
+		if !app.CheckConfig(*configPath) {
+			os.Exit(1)
+		}
+		return
+	}
+
+	if *testNotifyFlag {
+		if !app.SendTestNotification(*configPath, *providerFlag, *eventFlag) {
+			os.Exit(1)
+		}
+		return
+	}
+
+	if *decryptBackupFlag != "" {
+		if !app.DecryptBackup(*decryptBackupFlag, *identityFlag, *outputFlag) {
+			os.Exit(1)
+		}
+		return
+	}
+
+	if *deadLettersFlag {
+		app.ListDeadLetters(*configPath)
+		return
+	}
+
 	app.Run(*configPath, version)
 }
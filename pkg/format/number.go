@@ -0,0 +1,44 @@
+package format
+
+import (
+	"encoding/json"
+	"math"
+
+	"golang.org/x/text/language"
+)
+
+// Number is a number of the template data. Templates render the text formatted for a
+// locale, while filter rules and template functions use the value.
+type Number struct {
+	Value float64
+	text  string
+}
+
+// Basic returns the value rounded to the nearest integer, formatted with FormatBasic
+func Basic(value float64, lang language.Tag) Number {
+	return Number{Value: math.Round(value), text: FormatBasic(value, lang)}
+}
+
+// Detailed returns the value formatted with FormatDetailed
+func Detailed(value float64, lang language.Tag) Number {
+	return Number{Value: value, text: FormatDetailed(value, lang)}
+}
+
+// RatePPM returns the rate of value in total in ppm, formatted with FormatRatePPM
+func RatePPM(value float64, total float64, lang language.Tag) Number {
+	var rate float64
+	if total > 0 {
+		rate = value * 1e6 / total
+	}
+	return Basic(rate, lang)
+}
+
+// String returns the formatted number
+func (n Number) String() string {
+	return n.text
+}
+
+// MarshalJSON encodes the formatted number
+func (n Number) MarshalJSON() ([]byte, error) {
+	return json.Marshal(n.text)
+}